@@ -1,25 +1,40 @@
 package adapters
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/denchenko/gg/internal/adapters/primary/cli"
 	httpadapter "github.com/denchenko/gg/internal/adapters/primary/http"
 	"github.com/denchenko/gg/internal/adapters/secondary/cache"
 	"github.com/denchenko/gg/internal/adapters/secondary/repository/cached"
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/gitea"
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/github"
 	"github.com/denchenko/gg/internal/adapters/secondary/repository/gitlab"
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/graphql"
 	"github.com/denchenko/gg/internal/config"
 	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/credentials"
 	ascii "github.com/denchenko/gg/internal/format/ascii"
 	"github.com/denchenko/gg/internal/issue"
+	"github.com/denchenko/gg/internal/oauth"
 	do "github.com/samber/do/v2"
 	"github.com/spf13/cobra"
 	glclient "gitlab.com/gitlab-org/api/client-go"
+	"golang.org/x/oauth2"
 )
 
+// httpTimeout bounds the API calls of the GitHub and Gitea repositories, so
+// that an unresponsive server fails the command instead of hanging it.
+const httpTimeout = 30 * time.Second
+
 var PrimaryPackage = do.Package(
 	do.Lazy[*cobra.Command](cli.Command),
 	do.Lazy[*httpadapter.Server](NewHTTPServer),
+	do.Lazy[app.Factory](NewAppFactory),
 )
 
 var SecondaryPackage = do.Package(
@@ -31,10 +46,37 @@ var SecondaryPackage = do.Package(
 	do.Lazy[*ascii.Formatter](NewFormatter),
 )
 
-// NewGitLabClient creates a new GitLab client.
+// NewGitLabClient creates a new GitLab client. Tokens stored by
+// "gg auth login --oauth" are refreshed when they expire and saved back to the
+// credential store.
 func NewGitLabClient(i do.Injector) (*glclient.Client, error) {
 	cfg := do.MustInvoke[*config.Config](i)
-	client, err := glclient.NewClient(cfg.Token, glclient.WithBaseURL(cfg.BaseURL))
+
+	token, ok := oauth.Decode(cfg.Token)
+	if !ok {
+		client, err := glclient.NewClient(cfg.Token, glclient.WithBaseURL(cfg.BaseURL))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GitLab client: %w", err)
+		}
+
+		return client, nil
+	}
+
+	if cfg.OAuthClientID == "" {
+		return nil, errors.New("oauth.client_id is required to refresh the token stored by gg auth login --oauth")
+	}
+
+	ctx := context.Background()
+	source := oauth.TokenSource(ctx, oauth.Config(cfg.BaseURL, cfg.OAuthClientID), token, func(token *oauth2.Token) error {
+		return saveOAuthToken(cfg.BaseURL, token)
+	})
+
+	// The transport sets the Authorization header of every request from the
+	// token source, overriding the initial access token.
+	client, err := glclient.NewOAuthClient(token.AccessToken,
+		glclient.WithBaseURL(cfg.BaseURL),
+		glclient.WithHTTPClient(oauth2.NewClient(ctx, source)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
@@ -42,6 +84,25 @@ func NewGitLabClient(i do.Injector) (*glclient.Client, error) {
 	return client, nil
 }
 
+// saveOAuthToken stores an OAuth token for baseURL in the default credential store.
+func saveOAuthToken(baseURL string, token *oauth2.Token) error {
+	encoded, err := oauth.Encode(token)
+	if err != nil {
+		return err
+	}
+
+	store, err := credentials.Default()
+	if err != nil {
+		return err
+	}
+
+	if err := store.Set(baseURL, encoded); err != nil {
+		return fmt.Errorf("failed to store token in %s: %w", store.Name(), err)
+	}
+
+	return nil
+}
+
 // NewGitLabRepository creates a new GitLab repository instance.
 func NewGitLabRepository(i do.Injector) (*gitlab.Repository, error) {
 	client := do.MustInvoke[*glclient.Client](i)
@@ -56,12 +117,47 @@ func NewCache(_ do.Injector) (cache.Cache, error) {
 }
 
 // NewRepository creates a repository adapter that implements app.Repository.
-// It wraps the GitLab repository with a cached repository for performance.
+// It wraps the configured backend repository (GitLab over REST or GraphQL,
+// GitHub, or Gitea) with a cached repository for performance.
 func NewRepository(i do.Injector) (app.Repository, error) {
-	gitlabRepo := do.MustInvoke[*gitlab.Repository](i)
 	cacheInstance := do.MustInvoke[cache.Cache](i)
+	cfg := do.MustInvoke[*config.Config](i)
 
-	return cached.NewCachedRepository(gitlabRepo, cacheInstance), nil
+	var repo app.Repository
+	switch {
+	case cfg.Backend == config.BackendGitHub:
+		repo = github.NewRepository(&http.Client{Timeout: httpTimeout}, cfg.BaseURL, cfg.Token)
+	case cfg.Backend == config.BackendGitea:
+		repo = gitea.NewRepository(&http.Client{Timeout: httpTimeout}, cfg.BaseURL, cfg.Token)
+	case cfg.API == config.APIGraphQL:
+		client := do.MustInvoke[*glclient.Client](i)
+		repo = graphql.NewRepository(client, do.MustInvoke[*gitlab.Repository](i))
+	default:
+		repo = do.MustInvoke[*gitlab.Repository](i)
+	}
+
+	return cached.NewCachedRepository(repo, cacheInstance), nil
+}
+
+// NewAppFactory creates a factory that wires a separate set of secondary
+// adapters for every configuration it is given.
+func NewAppFactory(_ do.Injector) (app.Factory, error) {
+	return func(cfg *config.Config) (*app.App, error) {
+		injector := do.New(SecondaryPackage)
+		do.ProvideValue(injector, cfg)
+
+		repo, err := do.Invoke[app.Repository](injector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create repository: %w", err)
+		}
+
+		appInstance, err := app.NewApp(cfg, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create app: %w", err)
+		}
+
+		return appInstance, nil
+	}, nil
 }
 
 // NewHTTPServer creates a new HTTP server.
@@ -69,7 +165,7 @@ func NewHTTPServer(i do.Injector) (*httpadapter.Server, error) {
 	appInstance := do.MustInvoke[*app.App](i)
 	cfg := do.MustInvoke[*config.Config](i)
 
-	return httpadapter.NewServer(cfg.WebhookAddress, appInstance), nil
+	return httpadapter.NewServer(cfg.WebhookAddress, appInstance, cfg.RouletteRules), nil
 }
 
 // NewIssuer creates a new Issuer instance.
@@ -82,6 +178,7 @@ func NewIssuer(i do.Injector) (*issue.Issuer, error) {
 // NewFormatter creates a new Formatter instance.
 func NewFormatter(i do.Injector) (*ascii.Formatter, error) {
 	issuer := do.MustInvoke[*issue.Issuer](i)
+	cfg := do.MustInvoke[*config.Config](i)
 
-	return ascii.NewFormatter(issuer), nil
+	return ascii.NewFormatter(issuer, cfg.TemplateDir), nil
 }
@@ -1,11 +1,20 @@
 package cli
 
 import (
+	"os"
+	"slices"
+
 	"github.com/denchenko/gg/internal/adapters/primary/cli/commands"
 	"github.com/denchenko/gg/internal/config"
 	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/format"
 	ascii "github.com/denchenko/gg/internal/format/ascii"
+	"github.com/denchenko/gg/internal/format/html"
+	"github.com/denchenko/gg/internal/format/markdown"
+	"github.com/denchenko/gg/internal/format/structured"
 	"github.com/denchenko/gg/internal/issue"
+	"github.com/denchenko/gg/internal/log"
+	"github.com/denchenko/gg/internal/terminal"
 	do "github.com/samber/do/v2"
 	"github.com/spf13/cobra"
 )
@@ -16,17 +25,113 @@ func Command(i do.Injector) (*cobra.Command, error) {
 		Long: `A CLI tool for managing GitLab.`,
 	}
 
-	appInstance := do.MustInvoke[*app.App](i)
-	cfg := do.MustInvoke[*config.Config](i)
-	issuer := do.MustInvoke[*issue.Issuer](i)
-	formatter := do.MustInvoke[*ascii.Formatter](i)
+	newApp := do.MustInvoke[app.Factory](i)
+
+	// The profile and the setting flags are read from the parsed flags when
+	// loading the configuration, in PersistentPreRunE.
+	cmd.PersistentFlags().String("profile", "", "Configuration profile to use (overrides GG_PROFILE)")
+	for _, flag := range config.Flags() {
+		cmd.PersistentFlags().String(flag.Name, "", flag.Usage)
+	}
+
+	output := format.OutputTable
+	cmd.PersistentFlags().VarP(&output, "output", "o", "Output format: table, json, yaml, csv, markdown or html")
+	_ = cmd.RegisterFlagCompletionFunc("output", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		outputs := make([]string, 0, len(format.Outputs()))
+		for _, o := range format.Outputs() {
+			outputs = append(outputs, string(o))
+		}
+
+		return outputs, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	color := terminal.ColorAuto
+	cmd.PersistentFlags().Var(&color, "color", "When to color the output: auto, always or never (auto honors NO_COLOR)")
+	_ = cmd.RegisterFlagCompletionFunc("color", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		modes := make([]string, 0, len(terminal.ColorModes()))
+		for _, m := range terminal.ColorModes() {
+			modes = append(modes, string(m))
+		}
 
-	cmd.AddCommand(
-		commands.My(cfg, appInstance, formatter),
+		return modes, cobra.ShellCompDirectiveNoFileComp
+	})
+	var templateFile string
+	cmd.PersistentFlags().StringVar(&templateFile, "template", "",
+		"Template file to render the table output with instead of the default template")
+
+	// The commands are given the configuration and what is built from it
+	// before the flags are parsed, so they are filled in by PersistentPreRunE.
+	cfg := &config.Config{}
+	appInstance := &app.App{}
+	issuer := &issue.Issuer{}
+	tableFormatter := &ascii.Formatter{}
+	formatter := format.NewSwitch(&output,
+		tableFormatter,
+		structured.NewFormatter(format.OutputJSON),
+		structured.NewFormatter(format.OutputYAML),
+		structured.NewFormatter(format.OutputCSV),
+		markdown.NewFormatter(issuer),
+		html.NewFormatter(issuer),
+	)
+
+	// The config, auth and templates commands work without a valid
+	// configuration, so that it can be fixed; the others need the app.
+	cmd.AddCommand(commands.Config(newApp), commands.Auth(newApp), commands.Templates(cfg))
+
+	appCommands := []*cobra.Command{
+		commands.My(cfg, appInstance, formatter, newApp),
 		commands.Team(cfg, appInstance, formatter),
-		commands.MR(cfg, appInstance, formatter),
+		commands.MR(cfg, appInstance, formatter, issuer),
 		commands.Issue(appInstance, issuer),
-	)
+		commands.UI(cfg, appInstance, &color),
+	}
+	cmd.AddCommand(appCommands...)
+
+	cmd.PersistentPreRunE = func(c *cobra.Command, _ []string) error {
+		// Spinners only make sense while a person watches the output.
+		log.Configure(terminal.IsTerminal(os.Stdout), terminal.UseColor(color, os.Stdout))
+
+		do.ProvideValue(i, c.Flags())
+		needsApp := within(c, appCommands)
+		if needsApp {
+			c.SilenceUsage = true
+		}
+
+		resolved, err := do.Invoke[*config.Config](i)
+		if err != nil {
+			if needsApp {
+				return err
+			}
+
+			return nil
+		}
+		*cfg = *resolved
+
+		if !needsApp {
+			return nil
+		}
+
+		built, err := do.Invoke[*app.App](i)
+		if err != nil {
+			return err
+		}
+		*appInstance = *built
+		*issuer = *do.MustInvoke[*issue.Issuer](i)
+		*tableFormatter = *do.MustInvoke[*ascii.Formatter](i).WithTemplateFile(&templateFile).WithColor(&color)
+
+		return nil
+	}
 
 	return cmd, nil
 }
+
+// within reports whether cmd is one of parents or one of their subcommands.
+func within(cmd *cobra.Command, parents []*cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if slices.Contains(parents, c) {
+			return true
+		}
+	}
+
+	return false
+}
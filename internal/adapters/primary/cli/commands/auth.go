@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/credentials"
+	"github.com/denchenko/gg/internal/log"
+	"github.com/denchenko/gg/internal/oauth"
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+)
+
+const hoursPerDay = 24
+
+// Auth works without a complete configuration, so that a token can be stored
+// before anything else is set up.
+func Auth(newApp app.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the access token",
+	}
+
+	cmd.AddCommand(
+		newAuthLoginCommand(newApp),
+		newAuthLogoutCommand(),
+		newAuthStatusCommand(newApp),
+	)
+
+	return cmd
+}
+
+func newAuthLoginCommand(newApp app.Factory) *cobra.Command {
+	var useOAuth bool
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Verify a token and store it in the OS keyring or an encrypted file",
+		Long: `Verify a token and store it for the configured instance. The token is read
+from the terminal, or from standard input when it is not a terminal.
+
+With --oauth, no token is asked for. Instead, gg performs the OAuth device
+authorization grant against the GitLab instance: it shows a code to enter in
+the browser and waits for the request to be approved. The application is set
+with oauth.client_id. The access token is refreshed automatically.
+
+Tokens go to the Secret Service keyring when secret-tool is available and to
+an encrypted file in the user config directory otherwise. The passphrase of
+that file is asked for or taken from GG_PASSPHRASE.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Connection(cmd.Flags())
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			var token string
+			if useOAuth {
+				token, err = oauthLogin(cfg)
+			} else {
+				token, err = credentials.ReadSecret(fmt.Sprintf("Token for %s: ", cfg.BaseURL))
+			}
+			if err != nil {
+				return err
+			}
+			if token == "" {
+				return errors.New("token must not be empty")
+			}
+			cfg.Token = token
+
+			info, err := fetchTokenInfo(newApp, cfg)
+			if err != nil {
+				return err
+			}
+
+			store, err := credentials.Default()
+			if err != nil {
+				return err
+			}
+
+			if err := store.Set(cfg.BaseURL, token); err != nil {
+				return fmt.Errorf("failed to store token: %w", err)
+			}
+
+			fmt.Printf("Logged in to %s as %s, token stored in %s\n", cfg.BaseURL, info.User.Username, store.Name())
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&useOAuth, "oauth", false, "Log in with the OAuth device flow instead of a personal access token")
+
+	return cmd
+}
+
+// oauthLogin runs the device authorization grant and returns the token
+// encoded for the credential store.
+func oauthLogin(cfg *config.Config) (string, error) {
+	if cfg.Backend != config.BackendGitLab {
+		return "", fmt.Errorf("OAuth login is only supported for the %s backend", config.BackendGitLab)
+	}
+	if cfg.OAuthClientID == "" {
+		return "", errors.New("oauth.client_id is required: register an OAuth application with the api scope " +
+			"and the device flow enabled, then set GG_OAUTH_CLIENT_ID or oauth.client_id in the config file")
+	}
+
+	token, err := oauth.Login(context.Background(), oauth.Config(cfg.BaseURL, cfg.OAuthClientID),
+		func(auth *oauth2.DeviceAuthResponse) {
+			uri := auth.VerificationURI
+			if auth.VerificationURIComplete != "" {
+				uri = auth.VerificationURIComplete
+			}
+			fmt.Printf("Open %s and enter the code %s\n", uri, auth.UserCode)
+		})
+	if err != nil {
+		return "", err
+	}
+
+	return oauth.Encode(token)
+}
+
+func newAuthLogoutCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Remove the stored token of the configured instance",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Connection(cmd.Flags())
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			store, err := credentials.Default()
+			if err != nil {
+				return err
+			}
+
+			if err := store.Delete(cfg.BaseURL); err != nil {
+				return fmt.Errorf("failed to remove token: %w", err)
+			}
+
+			fmt.Printf("Removed token for %s from %s\n", cfg.BaseURL, store.Name())
+
+			return nil
+		},
+	}
+}
+
+func newAuthStatusCommand(newApp app.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the user, scopes and expiry of the token in use",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.Connection(cmd.Flags())
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			if cfg.Token == "" {
+				return fmt.Errorf("not logged in to %s: run gg auth login", cfg.BaseURL)
+			}
+
+			info, err := fetchTokenInfo(newApp, cfg)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintf(w, "Instance:\t%s\n", cfg.BaseURL)
+			_, _ = fmt.Fprintf(w, "User:\t%s\n", info.User.Username)
+			if info.Name != "" {
+				_, _ = fmt.Fprintf(w, "Token:\t%s\n", info.Name)
+			}
+			_, _ = fmt.Fprintf(w, "Scopes:\t%s\n", formatScopes(info.Scopes))
+			_, _ = fmt.Fprintf(w, "Expires:\t%s\n", formatExpiry(info.ExpiresAt, time.Now()))
+			_, _ = fmt.Fprintf(w, "Source:\t%s\n", cfg.Source("token"))
+
+			if err := w.Flush(); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func fetchTokenInfo(newApp app.Factory, cfg *config.Config) (*domain.TokenInfo, error) {
+	appInstance, err := newApp(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create app: %w", err)
+	}
+
+	var info *domain.TokenInfo
+	err = log.WithSpinner("Checking token...", func() error {
+		if _, ok := oauth.Decode(cfg.Token); ok {
+			info, err = oauthTokenInfo(appInstance)
+
+			return err
+		}
+
+		info, err = appInstance.GetTokenInfo(context.Background())
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify token: %w", err)
+	}
+
+	return info, nil
+}
+
+// oauthTokenInfo describes an OAuth token. GitLab only reports personal access
+// tokens, and the access token is refreshed when it expires, so only the
+// owner is looked up.
+func oauthTokenInfo(appInstance *app.App) (*domain.TokenInfo, error) {
+	user, err := appInstance.GetCurrentUser(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenInfo{
+		User:   user,
+		Name:   "OAuth (refreshed automatically)",
+		Scopes: []string{oauth.Scope},
+	}, nil
+}
+
+func formatScopes(scopes []string) string {
+	if len(scopes) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(scopes, ", ")
+}
+
+// formatExpiry renders the token expiry relative to now.
+func formatExpiry(expiresAt *time.Time, now time.Time) string {
+	if expiresAt == nil {
+		return "never or unknown"
+	}
+
+	date := expiresAt.Format(time.DateOnly)
+	days := int(expiresAt.Sub(now).Hours() / hoursPerDay)
+
+	switch {
+	case !expiresAt.After(now):
+		return date + " (expired)"
+	case days == 0:
+		return date + " (today)"
+	default:
+		return fmt.Sprintf("%s (in %d days)", date, days)
+	}
+}
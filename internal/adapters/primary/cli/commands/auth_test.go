@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatExpiry(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+
+		return &t
+	}
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      string
+	}{
+		{name: "no expiry", expiresAt: nil, want: "never or unknown"},
+		{name: "expired", expiresAt: at(-time.Hour), want: "2025-03-10 (expired)"},
+		{name: "today", expiresAt: at(6 * time.Hour), want: "2025-03-10 (today)"},
+		{name: "later", expiresAt: at(30 * 24 * time.Hour), want: "2025-04-09 (in 30 days)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatExpiry(tt.expiresAt, now))
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/issue"
+	"github.com/denchenko/gg/internal/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	tokenKey      = "token"
+	redactedValue = "<redacted>"
+	sampleIssue   = "ABC-123"
+	setArgsCount  = 2
+)
+
+// Config works without a loaded configuration, so that a broken or incomplete
+// configuration can be inspected and fixed.
+func Config(newApp app.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and edit settings",
+	}
+
+	cmd.AddCommand(
+		newConfigShowCommand(),
+		newConfigGetCommand(),
+		newConfigSetCommand(),
+		newConfigUnsetCommand(),
+		newConfigValidateCommand(newApp),
+	)
+
+	return cmd
+}
+
+func newConfigShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the effective configuration and where every value comes from",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			profile, entries, err := config.Inspect(cmd.Flags())
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			if profile != "" {
+				fmt.Printf("Profile: %s\n\n", profile)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+			for _, entry := range entries {
+				value, source := entry.Value, entry.Source.String()
+				if entry.Key == tokenKey && value != "" {
+					value = redactedValue
+				}
+				if value == "" {
+					value, source = "-", "-"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Key, value, source)
+			}
+
+			if err := w.Flush(); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newConfigGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <setting>",
+		Short: "Print the effective value of a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, entries, err := config.Inspect(cmd.Flags())
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+
+			for _, entry := range entries {
+				if entry.Key == args[0] {
+					fmt.Println(entry.Value)
+
+					return nil
+				}
+			}
+
+			return fmt.Errorf("unknown setting %q", args[0])
+		},
+	}
+}
+
+func newConfigSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <setting> <value>",
+		Short: "Write a setting to the user config file",
+		Long: `Write a setting to the user config file. With --profile, the setting is
+written to that profile. List settings such as team take comma-separated values.`,
+		Args: cobra.ExactArgs(setArgsCount),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, _ := cmd.Flags().GetString("profile")
+
+			path, err := config.SetUserValue(profile, args[0], args[1])
+			if err != nil {
+				return fmt.Errorf("failed to set %s: %w", args[0], err)
+			}
+
+			fmt.Printf("Set %s in %s\n", args[0], path)
+
+			return nil
+		},
+	}
+}
+
+func newConfigUnsetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <setting>",
+		Short: "Remove a setting from the user config file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, _ := cmd.Flags().GetString("profile")
+
+			path, err := config.UnsetUserValue(profile, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to unset %s: %w", args[0], err)
+			}
+
+			fmt.Printf("Removed %s from %s\n", args[0], path)
+
+			return nil
+		},
+	}
+}
+
+func newConfigValidateCommand(newApp app.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration, the token and the team against the server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return validateConfig(newApp, cmd.Flags())
+		},
+	}
+}
+
+func validateConfig(newApp app.Factory, flags *pflag.FlagSet) error {
+	ctx := context.Background()
+
+	cfg, err := config.New(flags)
+	if err != nil {
+		fmt.Printf("FAIL configuration: %v\n", err)
+
+		return errors.New("configuration is invalid")
+	}
+	fmt.Println("ok   configuration")
+
+	failed := false
+	report := func(err error, format string, args ...any) {
+		status := "ok  "
+		if err != nil {
+			status = "FAIL"
+			failed = true
+		}
+
+		fmt.Printf("%s %s", status, fmt.Sprintf(format, args...))
+		if err != nil {
+			fmt.Printf(": %v", err)
+		}
+		fmt.Println()
+	}
+
+	issueURL, err := renderIssueURL(cfg.IssueURLTemplate)
+	report(err, "issue URL template renders %s", issueURL)
+
+	appInstance, err := newApp(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create app: %w", err)
+	}
+
+	var (
+		currentUser *domain.User
+		checks      []*app.TeamMemberCheck
+	)
+	err = log.WithSpinner("Checking token and team...", func() error {
+		user, err := appInstance.GetCurrentUser(ctx)
+		if err != nil {
+			return err
+		}
+
+		currentUser = user
+		checks = appInstance.CheckTeamMembers(ctx)
+
+		return nil
+	})
+	if err != nil {
+		report(err, "token")
+	} else {
+		report(nil, "token authenticates as %s", currentUser.Username)
+	}
+
+	for _, check := range checks {
+		if check.Team != "" {
+			report(check.Err, "team %s member %s", check.Team, check.Username)
+		} else {
+			report(check.Err, "team member %s", check.Username)
+		}
+	}
+
+	if failed {
+		return errors.New("configuration has problems")
+	}
+
+	return nil
+}
+
+// renderIssueURL renders the issue URL template for a sample issue and checks
+// that the result is an absolute URL.
+func renderIssueURL(urlTemplate string) (string, error) {
+	if urlTemplate == "" {
+		return "(not configured)", nil
+	}
+
+	rendered, err := issue.NewIssuer(urlTemplate).MakeURL(sampleIssue)
+	if err != nil {
+		return "", fmt.Errorf("failed to render issue URL: %w", err)
+	}
+
+	parsed, err := url.Parse(rendered)
+	if err != nil || !parsed.IsAbs() {
+		return rendered, fmt.Errorf("%q is not an absolute URL", rendered)
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderIssueURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		template    string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "not configured",
+			template: "",
+			expected: "(not configured)",
+		},
+		{
+			name:     "absolute URL",
+			template: "https://jira.com/browse/{{.Issue}}",
+			expected: "https://jira.com/browse/ABC-123",
+		},
+		{
+			name:        "relative URL",
+			template:    "browse/{{.Issue}}",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := renderIssueURL(tt.template)
+
+			if tt.expectError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rendered)
+		})
+	}
+}
@@ -63,7 +63,7 @@ func browseIssue(appInstance *app.App, issuer *issue.Issuer) error {
 	var mr *domain.MergeRequest
 	err = log.WithSpinner("Finding merge request...", func() error {
 		var err error
-		mr, err = appInstance.GetMergeRequestByBranch(ctx, currentProject.ID, currentBranch)
+		mr, err = appInstance.GetMergeRequestByBranch(ctx, currentProject, currentBranch)
 		if err != nil {
 			return fmt.Errorf("failed to find merge request for branch %s: %w", currentBranch, err)
 		}
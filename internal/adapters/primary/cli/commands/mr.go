@@ -11,13 +11,14 @@ import (
 	"github.com/denchenko/gg/internal/config"
 	"github.com/denchenko/gg/internal/core/app"
 	"github.com/denchenko/gg/internal/core/domain"
-	ascii "github.com/denchenko/gg/internal/format/ascii"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/issue"
 	"github.com/denchenko/gg/internal/log"
 	"github.com/skratchdot/open-golang/open"
 	"github.com/spf13/cobra"
 )
 
-func MR(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *cobra.Command {
+func MR(cfg *config.Config, appInstance *app.App, formatter format.Formatter, issuer *issue.Issuer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "mr",
 		Short: "Merge Requests",
@@ -25,22 +26,38 @@ func MR(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *c
 
 	cmd.AddCommand(newMRRouletteCommand(cfg, appInstance, formatter))
 	cmd.AddCommand(newMRStatusCommand(cfg, appInstance, formatter))
+	cmd.AddCommand(newMRListCommand(cfg, appInstance, formatter))
 	cmd.AddCommand(newMRBrowseCommand(appInstance))
+	cmd.AddCommand(newMRCreateCommand(cfg, appInstance, issuer))
+	cmd.AddCommand(newMRCheckoutCommand(cfg, appInstance))
+	cmd.AddCommand(newMRApproveCommand(cfg, appInstance))
+	cmd.AddCommand(newMRUnapproveCommand(cfg, appInstance))
+	cmd.AddCommand(newMRMergeCommand(cfg, appInstance))
+	cmd.AddCommand(newMRRebaseCommand(cfg, appInstance))
 
 	return cmd
 }
 
-func newMRRouletteCommand(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *cobra.Command {
-	return &cobra.Command{
+func newMRRouletteCommand(cfg *config.Config, appInstance *app.App, formatter format.Formatter) *cobra.Command {
+	var team string
+
+	cmd := &cobra.Command{
 		Use:   "roulette [MR_URL]",
 		Short: "Suggest assignee and reviewer for a merge request",
 		Long: `Analyze team review workload and suggest appropriate assignee and reviewer for a merge request.
-If MR_URL is not provided, it will try to find the merge request for the current git branch.`,
+If MR_URL is not provided, it will try to find the merge request for the current git branch.
+
+Candidates are drawn from the team given with --team, otherwise from the
+default team of the project, otherwise from the default team.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
 			ctx := context.Background()
 			var mrURL string
 
+			if _, err := appInstance.ForTeam(team); err != nil {
+				return err
+			}
+
 			if len(args) > 0 {
 				mrURL = args[0]
 			} else {
@@ -67,7 +84,7 @@ If MR_URL is not provided, it will try to find the merge request for the current
 				var mr *domain.MergeRequest
 				err = log.WithSpinner("Finding merge request...", func() error {
 					var err error
-					mr, err = appInstance.GetMergeRequestByBranch(ctx, currentProject.ID, currentBranch)
+					mr, err = appInstance.GetMergeRequestByBranch(ctx, currentProject, currentBranch)
 					if err != nil {
 						return fmt.Errorf("failed to find merge request for branch %s: %w", currentBranch, err)
 					}
@@ -81,12 +98,16 @@ If MR_URL is not provided, it will try to find the merge request for the current
 				mrURL = mr.WebURL
 			}
 
-			return suggestAssignees(cfg, appInstance, formatter, mrURL)
+			return suggestAssignees(cfg, appInstance, formatter, mrURL, team)
 		},
 	}
+
+	addTeamFlag(cmd, appInstance, &team)
+
+	return cmd
 }
 
-func newMRStatusCommand(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *cobra.Command {
+func newMRStatusCommand(cfg *config.Config, appInstance *app.App, formatter format.Formatter) *cobra.Command {
 	return &cobra.Command{
 		Use:   "status [MR_URL]",
 		Short: "Show status of a merge request",
@@ -99,7 +120,7 @@ If MR_URL is not provided, it will try to find the merge request for the current
 	}
 }
 
-func showMRStatus(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter, args []string) error {
+func showMRStatus(cfg *config.Config, appInstance *app.App, formatter format.Formatter, args []string) error {
 	ctx := context.Background()
 
 	var mr *domain.MergeRequest
@@ -142,7 +163,7 @@ func showMRStatus(cfg *config.Config, appInstance *app.App, formatter *ascii.For
 		project = currentProject
 		err = log.WithSpinner("Finding merge request...", func() error {
 			var err error
-			mr, err = appInstance.GetMergeRequestByBranch(ctx, project.ID, currentBranch)
+			mr, err = appInstance.GetMergeRequestByBranch(ctx, project, currentBranch)
 			if err != nil {
 				return fmt.Errorf("failed to find merge request for branch %s: %w", currentBranch, err)
 			}
@@ -155,17 +176,37 @@ func showMRStatus(cfg *config.Config, appInstance *app.App, formatter *ascii.For
 	}
 
 	// Fetch approvals
-	approvals, err := fetchApprovals(ctx, appInstance, project.ID, mr.IID)
+	approvals, rules, err := fetchApprovals(ctx, appInstance, project.ID, mr.IID)
+	if err != nil {
+		return err
+	}
+
+	var discussions []*domain.Discussion
+	err = log.WithSpinner("Fetching discussions...", func() error {
+		var err error
+		discussions, err = appInstance.GetMergeRequestDiscussions(ctx, project.ID, mr.IID)
+
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
 	// Calculate and display status
-	return displayMRStatus(cfg, formatter, mr, approvals)
+	return displayMRStatus(cfg, formatter, mr, approvals, rules, discussions)
 }
 
-func fetchApprovals(ctx context.Context, appInstance *app.App, projectID, mrIID int) ([]*domain.User, error) {
-	var approvals []*domain.User
+// fetchApprovals fetches the approvals of a merge request along with its
+// approval rules.
+func fetchApprovals(
+	ctx context.Context,
+	appInstance *app.App,
+	projectID, mrIID int,
+) (*domain.Approvals, []*domain.ApprovalRule, error) {
+	var (
+		approvals *domain.Approvals
+		rules     []*domain.ApprovalRule
+	)
 	err := log.WithSpinner("Fetching approvals...", func() error {
 		var err error
 		approvals, err = appInstance.GetMergeRequestApprovals(ctx, projectID, mrIID)
@@ -173,33 +214,36 @@ func fetchApprovals(ctx context.Context, appInstance *app.App, projectID, mrIID
 			return fmt.Errorf("failed to get merge request approvals: %w", err)
 		}
 
-		return nil
+		rules, err = appInstance.GetMergeRequestApprovalRules(ctx, projectID, mrIID)
+
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch approvals: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch approvals: %w", err)
 	}
 
-	return approvals, nil
+	return approvals, rules, nil
 }
 
 func displayMRStatus(
 	cfg *config.Config,
-	formatter *ascii.Formatter,
+	formatter format.Formatter,
 	mr *domain.MergeRequest,
-	approvals []*domain.User,
+	approvals *domain.Approvals,
+	rules []*domain.ApprovalRule,
+	discussions []*domain.Discussion,
 ) error {
 	// Calculate status
-	const workingDaysThreshold = 3
-	now := time.Now()
-	threeWorkingDaysAgo := subtractWorkingDays(now, workingDaysThreshold)
-	isStalled := mr.UpdatedAt.Before(threeWorkingDaysAgo)
+	stalledSince := subtractWorkingDays(time.Now(), cfg.StalledAfterDays)
+	isStalled := mr.UpdatedAt.Before(stalledSince)
 
 	mrWithStatus := &domain.MergeRequestWithStatus{
 		MergeRequest:  mr,
-		Approvals:     approvals,
-		ApprovalCount: len(approvals),
 		IsStalled:     isStalled,
+		ApprovalRules: rules,
 	}
+	mrWithStatus.SetApprovals(approvals)
+	mrWithStatus.SetDiscussions(discussions)
 
 	// Format and display
 	formatted, err := formatter.FormatMRStatus(cfg.BaseURL, mrWithStatus)
@@ -241,7 +285,7 @@ func newMRBrowseCommand(appInstance *app.App) *cobra.Command {
 			var mr *domain.MergeRequest
 			err = log.WithSpinner("Finding merge request...", func() error {
 				var err error
-				mr, err = appInstance.GetMergeRequestByBranch(ctx, currentProject.ID, currentBranch)
+				mr, err = appInstance.GetMergeRequestByBranch(ctx, currentProject, currentBranch)
 				if err != nil {
 					return fmt.Errorf("failed to find merge request for branch %s: %w", currentBranch, err)
 				}
@@ -262,7 +306,12 @@ func newMRBrowseCommand(appInstance *app.App) *cobra.Command {
 	}
 }
 
-func suggestAssignees(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter, mrURL string) error {
+func suggestAssignees(
+	cfg *config.Config,
+	appInstance *app.App,
+	formatter format.Formatter,
+	mrURL, team string,
+) error {
 	ctx := context.Background()
 
 	projectPath, mrID, err := parseMRURL(cfg.BaseURL, mrURL)
@@ -275,6 +324,12 @@ func suggestAssignees(cfg *config.Config, appInstance *app.App, formatter *ascii
 		return err
 	}
 
+	if team == "" {
+		appInstance = appInstance.ForProject(project.Path)
+	} else if appInstance, err = appInstance.ForTeam(team); err != nil {
+		return err
+	}
+
 	mr, err := fetchMergeRequest(ctx, appInstance, project.ID, mrID)
 	if err != nil {
 		return err
@@ -297,6 +352,11 @@ func suggestAssignees(cfg *config.Config, appInstance *app.App, formatter *ascii
 
 	fmt.Print(formatted)
 
+	// Machine-readable output is meant for scripts, which cannot answer the prompt.
+	if formatter.Output() != format.OutputTable {
+		return nil
+	}
+
 	if suggestedAssignee != nil || suggestedReviewer != nil {
 		return applySuggestions(ctx, appInstance, project.ID, mrID, suggestedAssignee, suggestedReviewer)
 	}
@@ -422,17 +482,15 @@ func applySuggestions(
 	return nil
 }
 
-const urlPartsCount = 2
-
 func parseMRURL(baseURL, mrURL string) (projectPath string, mrID int, err error) {
-	parts := strings.Split(mrURL, "/-/merge_requests/")
-	if len(parts) != urlPartsCount {
+	projectURL, number, ok := domain.SplitMergeRequestURL(mrURL)
+	if !ok {
 		return "", 0, errors.New("invalid merge request URL format")
 	}
 
-	projectPath = strings.TrimPrefix(parts[0], baseURL+"/")
+	projectPath = strings.TrimPrefix(projectURL, baseURL+"/")
 
-	mrID, err = strconv.Atoi(parts[1])
+	mrID, err = strconv.Atoi(number)
 	if err != nil {
 		return "", 0, fmt.Errorf("invalid merge request ID: %w", err)
 	}
@@ -0,0 +1,206 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/git"
+	"github.com/denchenko/gg/internal/log"
+	"github.com/spf13/cobra"
+)
+
+// worktreeAuto is the value of a bare --worktree flag, which places the
+// worktree next to the repository.
+const worktreeAuto = "auto"
+
+func newMRCheckoutCommand(cfg *config.Config, appInstance *app.App) *cobra.Command {
+	var branch, worktree string
+
+	cmd := &cobra.Command{
+		Use:   "checkout [MR_URL|IID]",
+		Short: "Fetch a merge request and switch to its branch",
+		Long: `Fetch the head of a merge request of the current repository, merge requests
+from forks included, into a local branch and switch to it. An existing local
+branch is fast-forwarded.
+
+The local branch is named after the source branch, or mr/<IID> for merge
+requests from forks and when a local branch of that name tracks another
+branch.
+
+Without an argument, the merge requests of the current repository waiting for
+your review are listed to pick one by number.
+
+With --worktree, the branch is checked out in a new git worktree instead, by
+default next to the repository.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return checkoutMergeRequest(cfg, appInstance, args, branch, worktree)
+		},
+	}
+
+	cmd.Flags().StringVar(&branch, "branch", "", "Local branch name (defaults to the source branch or mr/<IID>)")
+	cmd.Flags().StringVar(&worktree, "worktree", "", "Check out in a new worktree at this path")
+	cmd.Flags().Lookup("worktree").NoOptDefVal = worktreeAuto
+
+	return cmd
+}
+
+func checkoutMergeRequest(cfg *config.Config, appInstance *app.App, args []string, branch, worktree string) error {
+	ctx := context.Background()
+
+	var project *domain.Project
+	err := log.WithSpinner("Getting current project info...", func() error {
+		var err error
+		project, _, err = appInstance.GetCurrentProjectInfo(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get current project info: %w", err)
+	}
+
+	mr, err := resolveCheckoutMergeRequest(ctx, cfg, appInstance, project, args)
+	if err != nil {
+		return err
+	}
+
+	if branch == "" {
+		branch = appInstance.LocalBranch(ctx, mr)
+	}
+
+	if worktree == "" {
+		err = log.WithSpinner(fmt.Sprintf("Checking out %s...", branch), func() error {
+			return git.Checkout(ctx, mr.HeadRef, branch)
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Switched to %s for %s\n", branch, format.Reference(cfg.BaseURL, mr))
+
+		return nil
+	}
+
+	if worktree == worktreeAuto {
+		topLevel, err := git.TopLevel(ctx)
+		if err != nil {
+			return err
+		}
+		worktree = defaultWorktreePath(topLevel, branch)
+	}
+
+	err = log.WithSpinner(fmt.Sprintf("Adding worktree for %s...", branch), func() error {
+		return git.AddWorktree(ctx, mr.HeadRef, branch, worktree)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Checked out %s for %s in %s\n", branch, format.Reference(cfg.BaseURL, mr), worktree)
+
+	return nil
+}
+
+// resolveCheckoutMergeRequest returns the merge request named by args, which
+// must belong to project, or lets the user pick one from the review workload.
+func resolveCheckoutMergeRequest(
+	ctx context.Context,
+	cfg *config.Config,
+	appInstance *app.App,
+	project *domain.Project,
+	args []string,
+) (*domain.MergeRequest, error) {
+	if len(args) == 0 {
+		return pickReviewMergeRequest(ctx, cfg, appInstance, project, os.Stdin)
+	}
+
+	mrID, err := strconv.Atoi(strings.TrimPrefix(args[0], "!"))
+	if err != nil {
+		projectPath, id, err := parseMRURL(cfg.BaseURL, args[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse merge request URL: %w", err)
+		}
+		if !strings.EqualFold(projectPath, project.Path) {
+			return nil, fmt.Errorf("the merge request belongs to %s, not to the repository of the current directory (%s)",
+				projectPath, project.Path)
+		}
+		mrID = id
+	}
+
+	return fetchMergeRequest(ctx, appInstance, project.ID, mrID)
+}
+
+// pickReviewMergeRequest lists the merge requests of project waiting for the
+// review of the current user and reads the number of one from in.
+func pickReviewMergeRequest(
+	ctx context.Context,
+	cfg *config.Config,
+	appInstance *app.App,
+	project *domain.Project,
+	in io.Reader,
+) (*domain.MergeRequest, error) {
+	var workload []*domain.MergeRequestWithStatus
+	err := log.WithSpinner("Fetching your review workload...", func() error {
+		var err error
+		workload, err = appInstance.GetMyReviewWorkloadWithStatus(ctx)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review workload: %w", err)
+	}
+
+	var mrs []*domain.MergeRequest
+	for _, mr := range workload {
+		if mr.ProjectID == project.ID {
+			mrs = append(mrs, mr.MergeRequest)
+		}
+	}
+
+	if len(mrs) == 0 {
+		return nil, fmt.Errorf("no merge requests of %s are waiting for your review", project.Path)
+	}
+
+	for i, mr := range mrs {
+		fmt.Printf("%3d. %s %s\n", i+1, format.Reference(cfg.BaseURL, mr), mr.Title)
+	}
+	fmt.Printf("\nMerge request to check out [1-%d]: ", len(mrs))
+
+	return readChoice(in, mrs)
+}
+
+// readChoice reads a 1-based number from in and returns the matching element.
+func readChoice[T any](in io.Reader, choices []T) (T, error) {
+	var zero T
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return zero, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(choices) {
+		return zero, fmt.Errorf("invalid choice %q", strings.TrimSpace(line))
+	}
+
+	return choices[n-1], nil
+}
+
+// defaultWorktreePath places the worktree of branch next to the repository at
+// topLevel, as in "project-feature-login" for branch "feature/login".
+func defaultWorktreePath(topLevel, branch string) string {
+	name := strings.NewReplacer("/", "-", "\\", "-").Replace(branch)
+
+	return filepath.Join(filepath.Dir(topLevel), filepath.Base(topLevel)+"-"+name)
+}
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadChoice(t *testing.T) {
+	choices := []string{"first", "second"}
+
+	choice, err := readChoice(strings.NewReader("2\n"), choices)
+	require.NoError(t, err)
+	assert.Equal(t, "second", choice)
+
+	choice, err = readChoice(strings.NewReader(" 1"), choices)
+	require.NoError(t, err)
+	assert.Equal(t, "first", choice)
+
+	for _, input := range []string{"0\n", "3\n", "x\n", ""} {
+		_, err := readChoice(strings.NewReader(input), choices)
+		assert.Error(t, err, "input %q", input)
+	}
+}
+
+func TestDefaultWorktreePath(t *testing.T) {
+	assert.Equal(t, "/src/project-feature-login", defaultWorktreePath("/src/project", "feature/login"))
+}
@@ -0,0 +1,309 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/git"
+	"github.com/denchenko/gg/internal/issue"
+	"github.com/denchenko/gg/internal/log"
+	"github.com/spf13/cobra"
+)
+
+// descriptionTemplates are the merge request description templates looked up
+// in the repository, in order of preference.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var descriptionTemplates = []string{
+	".gitlab/merge_request_templates/Default.md",
+	".gitlab/merge_request_templates/default.md",
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	".gitea/pull_request_template.md",
+	"docs/pull_request_template.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+}
+
+// mrCreateOptions holds the flags of "gg mr create".
+type mrCreateOptions struct {
+	title       string
+	description string
+	target      string
+	labels      []string
+	draft       bool
+	roulette    bool
+	team        string
+}
+
+func newMRCreateCommand(cfg *config.Config, appInstance *app.App, issuer *issue.Issuer) *cobra.Command {
+	var opts mrCreateOptions
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a merge request for the current branch",
+		Long: `Create a merge request for the current git branch, pushing the branch first
+when the remote lacks some of its commits.
+
+The title defaults to the subject of the last commit, prefixed with the issue
+key found in the branch name. The description defaults to the merge request
+template of the repository, else to the list of commits. The target branch
+defaults to the default branch of the project.
+
+With --roulette, the assignee and the reviewer are suggested from the team
+given with --team, otherwise from the default team of the project, and set
+on creation.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return createMergeRequest(cfg, appInstance, issuer, opts, cmd.Flags().Changed("description"))
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.title, "title", "t", "", "Title of the merge request")
+	cmd.Flags().StringVarP(&opts.description, "description", "d", "", "Description of the merge request")
+	cmd.Flags().StringVarP(&opts.target, "target", "b", "",
+		"Target branch (defaults to the default branch of the project)")
+	cmd.Flags().StringSliceVarP(&opts.labels, "label", "l", nil, "Label to add, repeatable or comma-separated")
+	cmd.Flags().BoolVar(&opts.draft, "draft", false, "Create the merge request as a draft")
+	cmd.Flags().BoolVar(&opts.roulette, "roulette", false, "Set the assignee and the reviewer suggested by the roulette")
+	addTeamFlag(cmd, appInstance, &opts.team)
+
+	return cmd
+}
+
+func createMergeRequest(
+	cfg *config.Config,
+	appInstance *app.App,
+	issuer *issue.Issuer,
+	opts mrCreateOptions,
+	hasDescription bool,
+) error {
+	ctx := context.Background()
+
+	if _, err := appInstance.ForTeam(opts.team); err != nil {
+		return err
+	}
+
+	var (
+		project *domain.Project
+		branch  string
+	)
+	err := log.WithSpinner("Getting current project info...", func() error {
+		var err error
+		project, branch, err = appInstance.GetCurrentProjectInfo(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get current project info: %w", err)
+	}
+
+	target := opts.target
+	if target == "" {
+		target = project.DefaultBranch
+	}
+	if target == "" {
+		return errors.New("failed to determine the target branch, set it with --target")
+	}
+	if target == branch {
+		return fmt.Errorf("branch %s is the target branch, check out the branch to merge first", branch)
+	}
+
+	if err := pushIfNeeded(ctx, branch); err != nil {
+		return err
+	}
+
+	// The commits are only used to fill in defaults, which fall back on the
+	// branch name when the target branch is not known locally.
+	subjects, _ := git.CommitSubjects(ctx, target)
+
+	newMR := &domain.NewMergeRequest{
+		Title:        opts.title,
+		Description:  opts.description,
+		SourceBranch: branch,
+		TargetBranch: target,
+		Draft:        opts.draft,
+		Labels:       opts.labels,
+	}
+	if newMR.Title == "" {
+		newMR.Title = mergeRequestTitle(issuer, branch, subjects)
+	}
+	if !hasDescription {
+		newMR.Description = mergeRequestDescription(ctx, subjects)
+	}
+
+	if opts.roulette {
+		if err := setRouletteSuggestions(ctx, appInstance, project, opts.team, newMR); err != nil {
+			return err
+		}
+	}
+
+	var mr *domain.MergeRequest
+	err = log.WithSpinner("Creating merge request...", func() error {
+		var err error
+		mr, err = appInstance.CreateMergeRequest(ctx, project.ID, newMR)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s: %s\n", format.Reference(cfg.BaseURL, mr), mr.WebURL)
+
+	return nil
+}
+
+// pushIfNeeded pushes branch when the remote lacks some of its commits.
+func pushIfNeeded(ctx context.Context, branch string) error {
+	needsPush, err := git.NeedsPush(ctx)
+	if err != nil {
+		return err
+	}
+	if !needsPush {
+		return nil
+	}
+
+	return log.WithSpinner(fmt.Sprintf("Pushing %s...", branch), func() error {
+		return git.Push(ctx, branch)
+	})
+}
+
+// setRouletteSuggestions sets the assignee and the reviewer suggested for a
+// merge request of the current user.
+func setRouletteSuggestions(
+	ctx context.Context,
+	appInstance *app.App,
+	project *domain.Project,
+	team string,
+	newMR *domain.NewMergeRequest,
+) error {
+	rouletteApp := appInstance.ForProject(project.Path)
+	if team != "" {
+		var err error
+		if rouletteApp, err = appInstance.ForTeam(team); err != nil {
+			return err
+		}
+	}
+
+	author, err := rouletteApp.GetCurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	workloads, err := fetchWorkloads(ctx, rouletteApp, project.ID)
+	if err != nil {
+		return err
+	}
+
+	mr := &domain.MergeRequest{
+		Title:        newMR.Title,
+		Author:       author,
+		ProjectID:    project.ID,
+		SourceBranch: newMR.SourceBranch,
+		TargetBranch: newMR.TargetBranch,
+	}
+
+	assignee, reviewer, err := fetchSuggestions(ctx, rouletteApp, mr, workloads)
+	if err != nil {
+		return err
+	}
+
+	if assignee != nil {
+		newMR.AssigneeID = &assignee.ID
+	}
+	if reviewer != nil {
+		newMR.ReviewerIDs = []int{reviewer.ID}
+	}
+
+	fmt.Printf("Assignee: %s, reviewer: %s\n", usernameOrNone(assignee), usernameOrNone(reviewer))
+
+	return nil
+}
+
+// mergeRequestTitle derives a title from the subject of the last commit, or
+// from the branch name when there are no commits, prefixed with the issue key
+// found in the branch name.
+func mergeRequestTitle(issuer *issue.Issuer, branch string, subjects []string) string {
+	key := issuer.ExtractNumber(branch)
+
+	var title string
+	if len(subjects) > 0 {
+		title = subjects[len(subjects)-1]
+	} else {
+		title = branchTitle(branch, key)
+	}
+
+	if key == "" || strings.Contains(title, key) {
+		return title
+	}
+	if title == "" {
+		return key
+	}
+
+	return key + ": " + title
+}
+
+// branchTitle turns a branch name such as "feature/ABC-1-add-login" into a
+// sentence, leaving out the issue key.
+func branchTitle(branch, key string) string {
+	name := path.Base(branch)
+	if key != "" {
+		name = strings.Replace(name, key, "", 1)
+	}
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	title := strings.Join(words, " ")
+
+	first, size := utf8.DecodeRuneInString(title)
+	if size == 0 {
+		return ""
+	}
+
+	return string(unicode.ToUpper(first)) + title[size:]
+}
+
+// mergeRequestDescription returns the merge request template of the
+// repository, or lists the commits when there are several.
+func mergeRequestDescription(ctx context.Context, subjects []string) string {
+	if topLevel, err := git.TopLevel(ctx); err == nil {
+		for _, name := range descriptionTemplates {
+			if content, err := os.ReadFile(filepath.Join(topLevel, name)); err == nil {
+				return string(content)
+			}
+		}
+	}
+
+	// The subject of a single commit is already the title.
+	if len(subjects) <= 1 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, subject := range subjects {
+		b.WriteString("- " + subject + "\n")
+	}
+
+	return b.String()
+}
+
+func usernameOrNone(user *domain.User) string {
+	if user == nil {
+		return "none"
+	}
+
+	return user.Username
+}
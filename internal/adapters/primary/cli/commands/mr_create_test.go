@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/denchenko/gg/internal/issue"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeRequestTitle(t *testing.T) {
+	issuer := issue.NewIssuer("")
+
+	tests := []struct {
+		name     string
+		branch   string
+		subjects []string
+		expected string
+	}{
+		{
+			name:     "last commit with the issue key of the branch",
+			branch:   "feature/ABC-12-login",
+			subjects: []string{"Add login form", "Validate login"},
+			expected: "ABC-12: Validate login",
+		},
+		{
+			name:     "commit already naming the issue",
+			branch:   "ABC-12-login",
+			subjects: []string{"ABC-12 Add login form"},
+			expected: "ABC-12 Add login form",
+		},
+		{
+			name:     "branch name without commits",
+			branch:   "feature/ABC-12-add_login-form",
+			expected: "ABC-12: Add login form",
+		},
+		{
+			name:     "branch name without issue key",
+			branch:   "fix-typo",
+			expected: "Fix typo",
+		},
+		{
+			name:     "issue key only",
+			branch:   "ABC-12",
+			expected: "ABC-12",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, mergeRequestTitle(issuer, tt.branch, tt.subjects))
+		})
+	}
+}
+
+func TestMergeRequestDescription(t *testing.T) {
+	// Outside of a repository there is no template to use.
+	t.Chdir(t.TempDir())
+	ctx := context.Background()
+
+	assert.Empty(t, mergeRequestDescription(ctx, []string{"Add login form"}))
+	assert.Equal(t, "- Add login form\n- Validate login\n",
+		mergeRequestDescription(ctx, []string{"Add login form", "Validate login"}))
+}
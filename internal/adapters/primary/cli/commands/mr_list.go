@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/log"
+	"github.com/spf13/cobra"
+)
+
+// stateAll is the value of --state listing merge requests in any state.
+const stateAll = "all"
+
+// listOptions holds the flags of mr list.
+type listOptions struct {
+	state        string
+	author       string
+	assignee     string
+	reviewer     string
+	project      string
+	group        string
+	labels       []string
+	milestone    string
+	draft        bool
+	targetBranch string
+	olderThan    int
+	newerThan    int
+	stalled      int
+	approval     string
+	sort         string
+	reverse      bool
+}
+
+func newMRListCommand(cfg *config.Config, appInstance *app.App, formatter format.Formatter) *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List merge requests matching filters",
+		Long: `List the open merge requests of a project, a group or a user that match every
+filter given, most recently updated first. Without --project, --group,
+--author, --assignee or --reviewer, the merge requests of the project of the
+current repository are listed, or yours outside of a repository.
+
+Ages are in days. --stalled keeps the merge requests without updates for the
+configured number of working days, or for the number given as --stalled=DAYS.
+--approval keeps the merge requests with no approvals left (approved), the ones
+still missing approvals (pending), or the ones approved by nobody (none). A
+merge request needing no approvals is approved even when nobody approved it.
+
+--sort orders by last update (updated), creation, newest first (created), age,
+oldest first (age), or number of changed files, largest first (size); --reverse
+flips the order. Sorting by size takes a request per merge request on GitLab.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			query, err := opts.query(cmd.Flags().Changed, time.Now())
+			if err != nil {
+				return err
+			}
+
+			return listMergeRequests(cfg, appInstance, formatter, query)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.state, "state", domain.StateOpened, "State: opened, closed, merged or all")
+	flags.StringVar(&opts.author, "author", "", "Username of the author")
+	flags.StringVar(&opts.assignee, "assignee", "", "Username of the assignee")
+	flags.StringVar(&opts.reviewer, "reviewer", "", "Username of a reviewer")
+	flags.StringVar(&opts.project, "project", "", "Project path, such as group/project")
+	flags.StringVar(&opts.group, "group", "", "Group path, subgroups included")
+	flags.StringSliceVarP(&opts.labels, "label", "l", nil, "Label to require, repeatable or comma-separated")
+	flags.StringVar(&opts.milestone, "milestone", "", "Milestone title")
+	flags.BoolVar(&opts.draft, "draft", false, "Only drafts, or with --draft=false only merge requests ready for review")
+	flags.StringVar(&opts.targetBranch, "target", "", "Target branch")
+	flags.IntVar(&opts.olderThan, "older-than", 0, "Only merge requests created at least this many days ago")
+	flags.IntVar(&opts.newerThan, "newer-than", 0, "Only merge requests created less than this many days ago")
+	flags.IntVar(&opts.stalled, "stalled", 0, "Only merge requests without updates for this many working days")
+	flags.Lookup("stalled").NoOptDefVal = "0"
+	flags.StringVar(&opts.approval, "approval", "", "Approval state: approved, pending or none")
+	flags.StringVar(&opts.sort, "sort", domain.SortUpdated, "Sort by updated, created, age or size")
+	flags.BoolVar(&opts.reverse, "reverse", false, "Reverse the order")
+
+	completeValues(cmd, "state", []string{domain.StateOpened, domain.StateClosed, domain.StateMerged, stateAll})
+	completeValues(cmd, "approval", app.ApprovalStates())
+	completeValues(cmd, "sort", domain.SortKeys())
+
+	return cmd
+}
+
+// completeValues completes the flag with a fixed set of values.
+func completeValues(cmd *cobra.Command, flag string, values []string) {
+	_ = cmd.RegisterFlagCompletionFunc(flag, func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// query converts the flags to a list query, ages relative to now. Flags left
+// unset, as told by changed, do not filter.
+func (o *listOptions) query(changed func(name string) bool, now time.Time) (*app.ListQuery, error) {
+	query := &app.ListQuery{
+		MergeRequestQuery: domain.MergeRequestQuery{
+			State:        o.state,
+			Scope:        domain.ScopeAll,
+			Author:       o.author,
+			Assignee:     o.assignee,
+			Reviewer:     o.reviewer,
+			Project:      o.project,
+			Group:        o.group,
+			Labels:       o.labels,
+			Milestone:    o.milestone,
+			TargetBranch: o.targetBranch,
+			Sort:         o.sort,
+		},
+		Stalled:          changed("stalled"),
+		StalledAfterDays: o.stalled,
+		Approval:         o.approval,
+		Reverse:          o.reverse,
+	}
+
+	switch {
+	case o.state == stateAll:
+		query.State = ""
+	case !slices.Contains([]string{domain.StateOpened, domain.StateClosed, domain.StateMerged}, o.state):
+		return nil, fmt.Errorf("unknown state %q", o.state)
+	}
+
+	if changed("draft") {
+		query.Draft = &o.draft
+	}
+	if o.olderThan > 0 {
+		createdBefore := now.AddDate(0, 0, -o.olderThan)
+		query.CreatedBefore = &createdBefore
+	}
+	if o.newerThan > 0 {
+		createdAfter := now.AddDate(0, 0, -o.newerThan)
+		query.CreatedAfter = &createdAfter
+	}
+
+	return query, nil
+}
+
+func listMergeRequests(
+	cfg *config.Config,
+	appInstance *app.App,
+	formatter format.Formatter,
+	query *app.ListQuery,
+) error {
+	ctx := context.Background()
+
+	var mrs []*domain.MergeRequestWithStatus
+	err := log.WithSpinner("Fetching merge requests...", func() error {
+		var err error
+		mrs, err = appInstance.ListMergeRequests(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to list merge requests: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	formatted, err := formatter.FormatMergeRequestList(cfg.BaseURL, mrs)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+
+	fmt.Print(formatted)
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOptions_Query(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	changed := func(names ...string) func(string) bool {
+		return func(name string) bool { return slices.Contains(names, name) }
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		opts := listOptions{state: domain.StateOpened, sort: domain.SortUpdated}
+
+		query, err := opts.query(changed(), now)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StateOpened, query.State)
+		assert.Equal(t, domain.ScopeAll, query.Scope)
+		assert.Nil(t, query.Draft, "drafts are kept unless --draft is given")
+		assert.False(t, query.Stalled)
+		assert.Nil(t, query.CreatedAfter)
+		assert.Nil(t, query.CreatedBefore)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		opts := listOptions{state: stateAll, olderThan: 7, newerThan: 30}
+
+		query, err := opts.query(changed("draft", "stalled"), now)
+		require.NoError(t, err)
+		assert.Empty(t, query.State, "all states")
+		require.NotNil(t, query.Draft)
+		assert.False(t, *query.Draft, "--draft=false keeps merge requests ready for review")
+		assert.True(t, query.Stalled)
+		assert.Zero(t, query.StalledAfterDays, "a bare --stalled uses the configured days")
+		assert.Equal(t, now.AddDate(0, 0, -7), *query.CreatedBefore)
+		assert.Equal(t, now.AddDate(0, 0, -30), *query.CreatedAfter)
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		opts := listOptions{state: "open"}
+
+		_, err := opts.query(changed(), now)
+		require.ErrorContains(t, err, `unknown state "open"`)
+	})
+}
@@ -0,0 +1,272 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/log"
+	"github.com/spf13/cobra"
+)
+
+func newMRApproveCommand(cfg *config.Config, appInstance *app.App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve [MR_URL]",
+		Short: "Approve a merge request",
+		Long: `Approve a merge request as the current user.
+If MR_URL is not provided, the merge request of the current git branch is approved.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			mr, err := resolveMergeRequest(ctx, cfg, appInstance, args)
+			if err != nil {
+				return err
+			}
+
+			err = log.WithSpinner("Approving merge request...", func() error {
+				return appInstance.ApproveMergeRequest(ctx, mr.ProjectID, mr.IID)
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Approved %s\n", format.Reference(cfg.BaseURL, mr))
+
+			return nil
+		},
+	}
+}
+
+func newMRUnapproveCommand(cfg *config.Config, appInstance *app.App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unapprove [MR_URL]",
+		Short: "Withdraw your approval of a merge request",
+		Long: `Withdraw the approval of the current user from a merge request.
+If MR_URL is not provided, the merge request of the current git branch is used.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			mr, err := resolveMergeRequest(ctx, cfg, appInstance, args)
+			if err != nil {
+				return err
+			}
+
+			err = log.WithSpinner("Withdrawing approval...", func() error {
+				return appInstance.UnapproveMergeRequest(ctx, mr.ProjectID, mr.IID)
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Unapproved %s\n", format.Reference(cfg.BaseURL, mr))
+
+			return nil
+		},
+	}
+}
+
+func newMRMergeCommand(cfg *config.Config, appInstance *app.App) *cobra.Command {
+	var opts domain.MergeOptions
+
+	cmd := &cobra.Command{
+		Use:   "merge [MR_URL]",
+		Short: "Merge a merge request",
+		Long: `Merge a merge request once it is open, not a draft, mergeable without a
+rebase, approved enough, without unresolved threads and its pipeline has
+succeeded. The checks are printed and
+the merge is aborted when any of them fails.
+
+With --when-pipeline-succeeds, a merge request whose pipeline is still running
+is set to merge automatically once it succeeds. The same goes for a pipeline
+the backend does not report, which otherwise fails the check.
+
+If MR_URL is not provided, the merge request of the current git branch is merged.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return mergeMergeRequest(cfg, appInstance, args, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.WhenPipelineSucceeds, "when-pipeline-succeeds", false,
+		"Merge once the running pipeline succeeds")
+	cmd.Flags().BoolVar(&opts.Squash, "squash", false, "Squash the commits on merge")
+	cmd.Flags().BoolVar(&opts.RemoveSourceBranch, "remove-source-branch", false, "Delete the source branch after merging")
+
+	return cmd
+}
+
+// rebasePollInterval is how often the merge request is fetched while the
+// server rebases it, and rebaseTimeout how long to wait for the rebase.
+const (
+	rebasePollInterval = 2 * time.Second
+	rebaseTimeout      = 5 * time.Minute
+)
+
+func newMRRebaseCommand(cfg *config.Config, appInstance *app.App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rebase [MR_URL]",
+		Short: "Rebase a merge request onto its target branch",
+		Long: `Rebase the source branch of a merge request onto its target branch on the
+server and wait for the rebase to finish. The rebase fails when the branches
+conflict, which has to be resolved locally.
+
+If MR_URL is not provided, the merge request of the current git branch is rebased.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(context.Background(), rebaseTimeout)
+			defer cancel()
+
+			mr, err := resolveMergeRequest(ctx, cfg, appInstance, args)
+			if err != nil {
+				return err
+			}
+
+			err = log.WithSpinner("Rebasing merge request...", func() error {
+				_, err := appInstance.RebaseMergeRequest(ctx, mr.ProjectID, mr.IID, rebasePollInterval)
+
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Rebased %s\n", format.Reference(cfg.BaseURL, mr))
+
+			return nil
+		},
+	}
+}
+
+func mergeMergeRequest(cfg *config.Config, appInstance *app.App, args []string, opts domain.MergeOptions) error {
+	ctx := context.Background()
+
+	mr, err := resolveMergeRequest(ctx, cfg, appInstance, args)
+	if err != nil {
+		return err
+	}
+
+	var (
+		mrWithStatus *domain.MergeRequestWithStatus
+		checks       []*app.MergeCheck
+	)
+	err = log.WithSpinner("Checking merge request...", func() error {
+		var err error
+		mrWithStatus, checks, err = appInstance.CheckMerge(ctx, mr.ProjectID, mr.IID, &opts)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(formatMergeChecks(checks))
+
+	if failed := failedMergeChecks(checks); len(failed) > 0 {
+		return fmt.Errorf("cannot merge %s: %s", format.Reference(cfg.BaseURL, mr), strings.Join(failed, ", "))
+	}
+
+	// Merging when the pipeline succeeds only applies to a running pipeline,
+	// a finished one is merged right away. An unknown one is left to the
+	// backend.
+	if !mrWithStatus.PipelineUnknown && !domain.PipelineInProgress(mrWithStatus.PipelineStatus) {
+		opts.WhenPipelineSucceeds = false
+	}
+
+	err = log.WithSpinner("Merging merge request...", func() error {
+		return appInstance.MergeMergeRequest(ctx, mr.ProjectID, mr.IID, &opts)
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.WhenPipelineSucceeds {
+		fmt.Printf("%s will be merged once its pipeline succeeds\n", format.Reference(cfg.BaseURL, mr))
+	} else {
+		fmt.Printf("Merged %s\n", format.Reference(cfg.BaseURL, mr))
+	}
+
+	return nil
+}
+
+// formatMergeChecks lists checks one per line, marked with ✓ or ✗.
+func formatMergeChecks(checks []*app.MergeCheck) string {
+	var b strings.Builder
+	for _, check := range checks {
+		mark := "✗"
+		if check.Passed {
+			mark = "✓"
+		}
+		fmt.Fprintf(&b, "%s %s: %s\n", mark, check.Name, check.Detail)
+	}
+
+	return b.String()
+}
+
+// failedMergeChecks returns the lowercased names of the checks that failed.
+func failedMergeChecks(checks []*app.MergeCheck) []string {
+	var failed []string
+	for _, check := range checks {
+		if !check.Passed {
+			failed = append(failed, strings.ToLower(check.Name))
+		}
+	}
+
+	return failed
+}
+
+// resolveMergeRequest returns the merge request at the URL given in args, or
+// the merge request of the current git branch when args is empty.
+func resolveMergeRequest(
+	ctx context.Context,
+	cfg *config.Config,
+	appInstance *app.App,
+	args []string,
+) (*domain.MergeRequest, error) {
+	if len(args) > 0 {
+		projectPath, mrID, err := parseMRURL(cfg.BaseURL, args[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse merge request URL: %w", err)
+		}
+
+		project, err := fetchProject(ctx, appInstance, projectPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return fetchMergeRequest(ctx, appInstance, project.ID, mrID)
+	}
+
+	var (
+		project *domain.Project
+		branch  string
+	)
+	err := log.WithSpinner("Getting current project info...", func() error {
+		var err error
+		project, branch, err = appInstance.GetCurrentProjectInfo(ctx)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current project info: %w", err)
+	}
+
+	var mr *domain.MergeRequest
+	err = log.WithSpinner("Finding merge request...", func() error {
+		var err error
+		mr, err = appInstance.GetMergeRequestByBranch(ctx, project, branch)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge request for branch %s: %w", branch, err)
+	}
+
+	return mr, nil
+}
@@ -48,6 +48,32 @@ func TestParseMRURL(t *testing.T) {
 				mrID:        456,
 			},
 		},
+		{
+			name:        "GitHub pull request URL",
+			baseURL:     "https://github.com",
+			mrURL:       "https://github.com/owner/repo/pull/789",
+			expectError: false,
+			expected: struct {
+				projectPath string
+				mrID        int
+			}{
+				projectPath: "owner/repo",
+				mrID:        789,
+			},
+		},
+		{
+			name:        "Gitea pull request URL",
+			baseURL:     "https://forgejo.example.com",
+			mrURL:       "https://forgejo.example.com/owner/repo/pulls/12",
+			expectError: false,
+			expected: struct {
+				projectPath string
+				mrID        int
+			}{
+				projectPath: "owner/repo",
+				mrID:        12,
+			},
+		},
 		{
 			name:        "invalid URL format",
 			baseURL:     "https://gitlab.com",
@@ -81,9 +107,9 @@ func TestMR(t *testing.T) {
 	cfg := &config.Config{BaseURL: "https://gitlab.com"}
 	appInstance := &app.App{}
 	issuer := issue.NewIssuer("")
-	formatter := ascii.NewFormatter(issuer)
+	formatter := ascii.NewFormatter(issuer, "")
 
-	cmd := MR(cfg, appInstance, formatter)
+	cmd := MR(cfg, appInstance, formatter, issuer)
 
 	assert.NotNil(t, cmd)
 	assert.Equal(t, "mr", cmd.Use)
@@ -4,24 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/denchenko/gg/internal/config"
 	"github.com/denchenko/gg/internal/core/app"
 	"github.com/denchenko/gg/internal/core/domain"
-	ascii "github.com/denchenko/gg/internal/format/ascii"
+	"github.com/denchenko/gg/internal/format"
 	"github.com/denchenko/gg/internal/log"
 	"github.com/spf13/cobra"
 )
 
-func My(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *cobra.Command {
+func My(cfg *config.Config, appInstance *app.App, formatter format.Formatter, newApp app.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "my",
 		Short: "Everything related to you",
 	}
 
 	cmd.AddCommand(
-		MyMR(cfg, appInstance, formatter),
+		MyMR(cfg, appInstance, formatter, newApp),
 		MyReview(cfg, appInstance, formatter),
 		MyActivity(cfg, appInstance, formatter),
 	)
@@ -29,27 +30,63 @@ func My(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *c
 	return cmd
 }
 
-func MyMR(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *cobra.Command {
-	return &cobra.Command{
+func MyMR(cfg *config.Config, appInstance *app.App, formatter format.Formatter, newApp app.Factory) *cobra.Command {
+	var (
+		allProfiles bool
+		watchOpts   watchOptions
+	)
+
+	cmd := &cobra.Command{
 		Use:   "mr",
 		Short: "Show your merge requests status",
 		RunE: func(_ *cobra.Command, _ []string) error {
+			if allProfiles && watchOpts.enabled {
+				return errors.New("--watch cannot be combined with --all-profiles")
+			}
+			if allProfiles {
+				return showMyMRStatusAllProfiles(cfg, formatter, newApp)
+			}
+			if watchOpts.enabled {
+				return watch(cfg, appInstance.NewWatcher(app.WatchMyMergeRequests), watchOpts,
+					"Fetching your merge requests...", func(mrs []*domain.MergeRequestWithStatus) (string, error) {
+						return formatter.FormatMyMergeRequestStatus(cfg.BaseURL, mrs)
+					})
+			}
+
 			return showMyMRStatus(cfg, appInstance, formatter)
 		},
 	}
+
+	cmd.Flags().BoolVar(&allProfiles, "all-profiles", false, "Show merge requests from every configured profile")
+	addWatchFlags(cmd, &watchOpts)
+
+	return cmd
 }
 
-func MyReview(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *cobra.Command {
-	return &cobra.Command{
+func MyReview(cfg *config.Config, appInstance *app.App, formatter format.Formatter) *cobra.Command {
+	var watchOpts watchOptions
+
+	cmd := &cobra.Command{
 		Use:   "review",
 		Short: "Show your review workload",
 		RunE: func(_ *cobra.Command, _ []string) error {
+			if watchOpts.enabled {
+				return watch(cfg, appInstance.NewWatcher(app.WatchMyReviews), watchOpts,
+					"Fetching your review workload...", func(mrs []*domain.MergeRequestWithStatus) (string, error) {
+						return formatter.FormatMyReviewWorkload(cfg.BaseURL, mrs)
+					})
+			}
+
 			return showMyReviewWorkload(cfg, appInstance, formatter)
 		},
 	}
+
+	addWatchFlags(cmd, &watchOpts)
+
+	return cmd
 }
 
-func showMyReviewWorkload(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) error {
+func showMyReviewWorkload(cfg *config.Config, appInstance *app.App, formatter format.Formatter) error {
 	ctx := context.Background()
 
 	var mrsWithStatus []*domain.MergeRequestWithStatus
@@ -77,7 +114,7 @@ func showMyReviewWorkload(cfg *config.Config, appInstance *app.App, formatter *a
 	return nil
 }
 
-func showMyMRStatus(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) error {
+func showMyMRStatus(cfg *config.Config, appInstance *app.App, formatter format.Formatter) error {
 	ctx := context.Background()
 
 	var mrsWithStatus []*domain.MergeRequestWithStatus
@@ -105,13 +142,89 @@ func showMyMRStatus(cfg *config.Config, appInstance *app.App, formatter *ascii.F
 	return nil
 }
 
+// showMyMRStatusAllProfiles shows my merge requests of the top-level
+// configuration and of every profile. A profile that fails is reported next to
+// the others, the command only fails when all of them do.
+func showMyMRStatusAllProfiles(cfg *config.Config, formatter format.Formatter, newApp app.Factory) error {
+	ctx := context.Background()
+
+	profiles := append([]string{""}, cfg.Profiles()...)
+	results := make([]format.ProfileMergeRequests, len(profiles))
+
+	_ = log.WithSpinner("Fetching your merge requests from all profiles...", func() error {
+		var wg sync.WaitGroup
+		for i, profile := range profiles {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = fetchProfileMergeRequests(ctx, cfg, newApp, profile)
+			}()
+		}
+		wg.Wait()
+
+		return nil
+	})
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed == len(results) {
+		return fmt.Errorf("failed to get merge requests: %w", results[0].Err)
+	}
+
+	formatted, err := formatter.FormatProfilesMergeRequestStatus(results)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+
+	fmt.Print(formatted)
+
+	return nil
+}
+
+// fetchProfileMergeRequests fetches my merge requests with the named profile,
+// or with the top-level configuration when profile is empty.
+func fetchProfileMergeRequests(
+	ctx context.Context,
+	cfg *config.Config,
+	newApp app.Factory,
+	profile string,
+) format.ProfileMergeRequests {
+	result := format.ProfileMergeRequests{Profile: profile}
+
+	profileCfg, err := cfg.ForProfile(profile)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to load profile: %w", err)
+
+		return result
+	}
+	result.BaseURL = profileCfg.BaseURL
+
+	profileApp, err := newApp(profileCfg)
+	if err != nil {
+		result.Err = err
+
+		return result
+	}
+
+	result.MergeRequests, err = profileApp.GetMergeRequestsWithStatus(ctx)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get merge requests: %w", err)
+	}
+
+	return result
+}
+
 // ActivityDateRange holds the date ranges for querying activity events.
 type ActivityDateRange struct {
 	After  time.Time
 	Before *time.Time
 }
 
-func MyActivity(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *cobra.Command {
+func MyActivity(cfg *config.Config, appInstance *app.App, formatter format.Formatter) *cobra.Command {
 	var afterStr, beforeStr string
 
 	cmd := &cobra.Command{
@@ -132,7 +245,7 @@ func MyActivity(cfg *config.Config, appInstance *app.App, formatter *ascii.Forma
 func showMyActivity(
 	cfg *config.Config,
 	appInstance *app.App,
-	formatter *ascii.Formatter,
+	formatter format.Formatter,
 	afterStr, beforeStr string,
 ) error {
 	ctx := context.Background()
@@ -7,12 +7,12 @@ import (
 	"github.com/denchenko/gg/internal/config"
 	"github.com/denchenko/gg/internal/core/app"
 	"github.com/denchenko/gg/internal/core/domain"
-	ascii "github.com/denchenko/gg/internal/format/ascii"
+	"github.com/denchenko/gg/internal/format"
 	"github.com/denchenko/gg/internal/log"
 	"github.com/spf13/cobra"
 )
 
-func Team(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *cobra.Command {
+func Team(cfg *config.Config, appInstance *app.App, formatter format.Formatter) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "team",
 		Short: "Everything related to your team",
@@ -25,17 +25,37 @@ func Team(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter)
 	return cmd
 }
 
-func TeamReview(cfg *config.Config, appInstance *app.App, formatter *ascii.Formatter) *cobra.Command {
-	return &cobra.Command{
+func TeamReview(cfg *config.Config, appInstance *app.App, formatter format.Formatter) *cobra.Command {
+	var team string
+
+	cmd := &cobra.Command{
 		Use:   "review",
 		Short: "Show your team workload",
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return showTeamReviewWorkload(cfg, appInstance, formatter)
+			teamApp, err := appInstance.ForTeam(team)
+			if err != nil {
+				return err
+			}
+
+			return showTeamReviewWorkload(cfg, teamApp, formatter)
 		},
 	}
+
+	addTeamFlag(cmd, appInstance, &team)
+
+	return cmd
+}
+
+// addTeamFlag adds the --team flag selecting one of the named teams instead
+// of the default team.
+func addTeamFlag(cmd *cobra.Command, appInstance *app.App, team *string) {
+	cmd.Flags().StringVar(team, "team", "", "Named team to use instead of the default team")
+	_ = cmd.RegisterFlagCompletionFunc("team", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return appInstance.Teams(), cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
-func showTeamReviewWorkload(_ *config.Config, appInstance *app.App, formatter *ascii.Formatter) error {
+func showTeamReviewWorkload(_ *config.Config, appInstance *app.App, formatter format.Formatter) error {
 	ctx := context.Background()
 
 	var workloads []*domain.UserWorkload
@@ -57,7 +77,7 @@ func showTeamReviewWorkload(_ *config.Config, appInstance *app.App, formatter *a
 		return fmt.Errorf("failed to format output: %w", err)
 	}
 
-	fmt.Println(formattedOutput)
+	fmt.Print(formattedOutput)
 
 	return nil
 }
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/denchenko/gg/internal/config"
+	ascii "github.com/denchenko/gg/internal/format/ascii"
+	"github.com/spf13/cobra"
+)
+
+const (
+	templateDirPerm  = 0o755
+	templateFilePerm = 0o644
+)
+
+// Templates works without a loaded configuration; the template directory of
+// cfg is used when it is set.
+func Templates(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Manage output templates",
+	}
+
+	cmd.AddCommand(newTemplatesDumpCommand(cfg))
+
+	return cmd
+}
+
+func newTemplatesDumpCommand(cfg *config.Config) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "dump [DIR]",
+		Short: "Write the default templates to a directory",
+		Long: `Write the default templates to DIR, or to the directory configured as
+templates.dir, as a starting point for your own. Templates in templates.dir
+replace the default ones with the same file name; --template renders a single
+command with any template file.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir := cfg.TemplateDir
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			if dir == "" {
+				return errors.New("no directory given and templates.dir is not set")
+			}
+
+			written, err := dumpTemplates(dir, force)
+			for _, path := range written {
+				fmt.Println(path)
+			}
+
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing template files")
+
+	return cmd
+}
+
+// dumpTemplates writes every default template to dir and returns the paths
+// written. Existing files are kept unless force is set.
+func dumpTemplates(dir string, force bool) ([]string, error) {
+	if err := os.MkdirAll(dir, templateDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create template directory: %w", err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	var written []string
+	for _, name := range ascii.TemplateNames() {
+		text, _ := ascii.DefaultTemplate(name)
+		path := filepath.Join(dir, ascii.TemplateFileName(name))
+
+		f, err := os.OpenFile(path, flags, templateFilePerm)
+		if errors.Is(err, fs.ErrExist) {
+			return written, fmt.Errorf("%s already exists, use --force to overwrite it", path)
+		}
+		if err != nil {
+			return written, fmt.Errorf("failed to write template: %w", err)
+		}
+
+		_, err = f.WriteString(text)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return written, fmt.Errorf("failed to write template: %w", err)
+		}
+
+		written = append(written, path)
+	}
+
+	return written, nil
+}
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	ascii "github.com/denchenko/gg/internal/format/ascii"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpTemplates(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "templates")
+
+	written, err := dumpTemplates(dir, false)
+	require.NoError(t, err)
+	require.Len(t, written, len(ascii.TemplateNames()))
+
+	data, err := os.ReadFile(filepath.Join(dir, "my_mr.tmpl"))
+	require.NoError(t, err)
+	expected, _ := ascii.DefaultTemplate(ascii.TemplateMyMR)
+	assert.Equal(t, expected, string(data))
+
+	_, err = dumpTemplates(dir, false)
+	require.Error(t, err, "existing templates are kept")
+
+	_, err = dumpTemplates(dir, true)
+	require.NoError(t, err)
+}
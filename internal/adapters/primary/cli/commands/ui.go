@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/denchenko/gg/internal/adapters/primary/tui"
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/terminal"
+	"github.com/spf13/cobra"
+)
+
+const defaultUIRefresh = time.Minute
+
+func UI(cfg *config.Config, appInstance *app.App, color *terminal.ColorMode) *cobra.Command {
+	var (
+		team    string
+		refresh time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ui",
+		Short: "Open a dashboard of your merge requests, reviews and team workload",
+		Long: `Open a full-screen dashboard with tabs for your merge requests, your reviews
+and the team workload, refreshed in the background.
+
+Keys: up/down or j/k move, tab or 1-3 switch tabs, o or enter opens the selected
+merge request in the browser, a approves it, c checks out its source branch,
+r suggests an assignee and a reviewer, ctrl+r refreshes, q quits.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if !terminal.IsTerminal(os.Stdout) {
+				return errors.New("gg ui needs a terminal")
+			}
+
+			model, err := tui.New(appInstance, cfg.BaseURL, team, refresh, terminal.UseColor(*color, os.Stdout))
+			if err != nil {
+				return err
+			}
+
+			if _, err := tea.NewProgram(model, tea.WithAltScreen()).Run(); err != nil {
+				return fmt.Errorf("failed to run dashboard: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&refresh, "refresh", defaultUIRefresh, "Interval between refreshes, 0 to refresh only on demand")
+	addTeamFlag(cmd, appInstance, &team)
+
+	return cmd
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/log"
+	"github.com/denchenko/gg/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+const defaultWatchInterval = time.Minute
+
+// watchOptions configures the watch mode of a command.
+type watchOptions struct {
+	enabled  bool
+	interval time.Duration
+	notify   bool
+}
+
+// addWatchFlags adds the flags of the watch mode.
+func addWatchFlags(cmd *cobra.Command, opts *watchOptions) {
+	cmd.Flags().BoolVarP(&opts.enabled, "watch", "w", false, "Keep polling and print what changes")
+	cmd.Flags().DurationVar(&opts.interval, "interval", defaultWatchInterval, "Polling interval of --watch")
+	cmd.Flags().BoolVar(&opts.notify, "notify", false, "Also send changes as desktop notifications (needs notify-send)")
+}
+
+func (opts watchOptions) validate() error {
+	if opts.interval <= 0 {
+		return errors.New("--interval must be positive")
+	}
+	if opts.notify && !notify.Available() {
+		return notify.ErrUnsupported
+	}
+
+	return nil
+}
+
+// watch prints the report of the first poll of watcher, then polls every
+// interval and prints the changes until it is interrupted.
+func watch(
+	cfg *config.Config,
+	watcher *app.Watcher,
+	opts watchOptions,
+	message string,
+	render func(mrs []*domain.MergeRequestWithStatus) (string, error),
+) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var mrs []*domain.MergeRequestWithStatus
+	err := log.WithSpinner(message, func() error {
+		var err error
+		mrs, _, err = watcher.Poll(ctx)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to poll merge requests: %w", err)
+	}
+
+	formatted, err := render(mrs)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+
+	fmt.Print(formatted)
+	fmt.Fprintf(os.Stderr, "Watching for changes every %s, press Ctrl+C to stop\n", opts.interval)
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		_, changes, err := watcher.Poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "%s failed to poll merge requests: %v\n", time.Now().Format(time.TimeOnly), err)
+
+			continue
+		}
+
+		for _, change := range changes {
+			summary, subject := describeChange(cfg.BaseURL, change)
+			fmt.Printf("%s %s: %s\n", time.Now().Format(time.TimeOnly), subject, summary)
+
+			if opts.notify {
+				if err := notify.Send(ctx, subject, summary); err != nil {
+					fmt.Fprintf(os.Stderr, "%v\n", err)
+				}
+			}
+		}
+	}
+}
+
+// describeChange returns what happened and to which merge request.
+func describeChange(baseURL string, change *app.Change) (summary, subject string) {
+	mr := change.MergeRequest
+	subject = format.Reference(baseURL, mr.MergeRequest) + " " + mr.Title
+
+	switch change.Kind {
+	case app.ChangeReviewRequested:
+		summary = "review requested"
+	case app.ChangeApproved:
+		summary = "approved by " + format.JoinUsernames(change.Users)
+	case app.ChangeCommented:
+		summary = strconv.Itoa(change.Comments) + " new comments"
+		if change.Comments == 1 {
+			summary = "1 new comment"
+		}
+	case app.ChangeReadyToMerge:
+		summary = "ready to merge"
+	case app.ChangeStalled:
+		summary = "stalled"
+	default:
+		summary = string(change.Kind)
+	}
+
+	return summary, subject
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeChange(t *testing.T) {
+	mr := &domain.MergeRequestWithStatus{MergeRequest: &domain.MergeRequest{
+		IID:    7,
+		Title:  "Add watch mode",
+		WebURL: "https://gitlab.example.com/acme/web/-/merge_requests/7",
+	}}
+
+	tests := []struct {
+		name     string
+		change   *app.Change
+		expected string
+	}{
+		{
+			name:     "review requested",
+			change:   &app.Change{Kind: app.ChangeReviewRequested},
+			expected: "review requested",
+		},
+		{
+			name: "approved",
+			change: &app.Change{Kind: app.ChangeApproved, Users: []*domain.User{
+				{Username: "alice"}, {Username: "bob"},
+			}},
+			expected: "approved by alice, bob",
+		},
+		{
+			name:     "one comment",
+			change:   &app.Change{Kind: app.ChangeCommented, Comments: 1},
+			expected: "1 new comment",
+		},
+		{
+			name:     "comments",
+			change:   &app.Change{Kind: app.ChangeCommented, Comments: 3},
+			expected: "3 new comments",
+		},
+		{
+			name:     "ready to merge",
+			change:   &app.Change{Kind: app.ChangeReadyToMerge},
+			expected: "ready to merge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change.MergeRequest = mr
+
+			summary, subject := describeChange("https://gitlab.example.com", tt.change)
+
+			assert.Equal(t, tt.expected, summary)
+			assert.Equal(t, "acme/web!7 Add watch mode", subject)
+		})
+	}
+}
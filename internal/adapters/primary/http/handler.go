@@ -4,13 +4,18 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
 )
 
 // WebhookPayload represents the GitLab webhook payload.
 type WebhookPayload struct {
 	ObjectKind string `json:"object_kind"`
 	Project    struct {
-		ID int `json:"id"`
+		ID                int    `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
 	} `json:"project"`
 	ObjectAttributes struct {
 		ID             int    `json:"iid"`
@@ -49,31 +54,82 @@ func (s *Server) handleGitLabWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	mr, err := s.app.GetMergeRequest(r.Context(), payload.Project.ID, payload.ObjectAttributes.ID)
+	key := domain.MergeRequestKey{ProjectID: payload.Project.ID, IID: payload.ObjectAttributes.ID}
+	if !s.claim(key, time.Now()) {
+		w.WriteHeader(http.StatusOK)
+
+		return
+	}
+
+	rule := s.rules.Match(payload.Project.PathWithNamespace)
+
+	// Projects may have their own default team, which a rule can override.
+	appInstance, err := s.app.ForProject(payload.Project.PathWithNamespace).ForTeam(rule.Team)
+	if err != nil {
+		s.release(key)
+		log.Printf("Failed to select team: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+
+		return
+	}
+
+	mr, err := appInstance.GetMergeRequest(r.Context(), payload.Project.ID, payload.ObjectAttributes.ID)
 	if err != nil {
+		s.release(key)
 		log.Printf("Failed to get merge request: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 
 		return
 	}
 
-	workloads, err := s.app.AnalyzeWorkload(r.Context(), payload.Project.ID)
+	if rule.Skips(mr.Labels) {
+		// The label may be removed right away, which has to be handled.
+		s.release(key)
+		w.WriteHeader(http.StatusOK)
+
+		return
+	}
+
+	workloads, err := appInstance.AnalyzeWorkload(r.Context(), payload.Project.ID)
 	if err != nil {
+		s.release(key)
 		log.Printf("Failed to analyze workload: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 
 		return
 	}
 
-	assignee, reviewer, err := s.app.SuggestAssigneeAndReviewer(r.Context(), mr, workloads)
+	assignee, reviewers, err := appInstance.SuggestAssignment(r.Context(), mr, workloads, app.RouletteOptions{
+		SkipAssignee: !rule.Assignee,
+		Reviewers:    rule.ReviewersFor(mr.TargetBranch),
+	})
 	if err != nil {
-		log.Printf("Failed to suggest assignee and reviewer: %v", err)
+		s.release(key)
+		log.Printf("Failed to suggest assignee and reviewers: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 
 		return
 	}
 
-	if err := s.app.UpdateMergeRequest(r.Context(), mr.ProjectID, mr.IID, &assignee.ID, []int{reviewer.ID}); err != nil {
+	if assignee == nil && len(reviewers) == 0 {
+		log.Printf("No assignee or reviewers to set for %s!%d", payload.Project.PathWithNamespace, mr.IID)
+		w.WriteHeader(http.StatusOK)
+
+		return
+	}
+
+	var assigneeID *int
+	if assignee != nil {
+		assigneeID = &assignee.ID
+	}
+
+	reviewerIDs := make([]int, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		reviewerIDs = append(reviewerIDs, reviewer.ID)
+	}
+
+	if err := appInstance.UpdateMergeRequest(r.Context(), mr.ProjectID, mr.IID, assigneeID, reviewerIDs); err != nil {
+		s.release(key)
 		log.Printf("Failed to update merge request: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 
@@ -82,3 +138,37 @@ func (s *Server) handleGitLabWebhook(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// claim marks the merge request as handled and reports whether the caller
+// should process it. Requests for a merge request handled within dedupWindow
+// are rejected.
+func (s *Server) claim(key domain.MergeRequestKey, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handled == nil {
+		s.handled = make(map[domain.MergeRequestKey]time.Time)
+	}
+
+	for k, at := range s.handled {
+		if now.Sub(at) > dedupWindow {
+			delete(s.handled, k)
+		}
+	}
+
+	if _, ok := s.handled[key]; ok {
+		return false
+	}
+
+	s.handled[key] = now
+
+	return true
+}
+
+// release forgets a claimed merge request so a retried event can process it.
+func (s *Server) release(key domain.MergeRequestKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.handled, key)
+}
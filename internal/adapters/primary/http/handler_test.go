@@ -6,10 +6,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/denchenko/gg/internal/adapters/secondary/repository/mocks"
+	"github.com/denchenko/gg/internal/config"
 	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -71,8 +75,9 @@ func TestServer_handleGitLabWebhook(t *testing.T) {
 			payload: WebhookPayload{
 				ObjectKind: "merge_request",
 				Project: struct {
-					ID int `json:"id"`
-				}{ID: 1},
+					ID                int    `json:"id"`
+					PathWithNamespace string `json:"path_with_namespace"`
+				}{ID: 1, PathWithNamespace: "acme/api"},
 				ObjectAttributes: struct {
 					ID             int    `json:"iid"`
 					State          string `json:"state"`
@@ -128,3 +133,95 @@ func TestServer_handleGitLabWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_handleGitLabWebhook_rules(t *testing.T) {
+	newPayload := func() WebhookPayload {
+		var payload WebhookPayload
+		payload.ObjectKind = "merge_request"
+		payload.Project.ID = 1
+		payload.Project.PathWithNamespace = "acme/api"
+		payload.ObjectAttributes.ID = 42
+
+		return payload
+	}
+
+	alice := &domain.User{ID: 2, Username: "alice", Status: domain.UserStatus{Availability: "available"}}
+
+	tests := []struct {
+		name      string
+		rules     config.RouletteRules
+		labels    []string
+		claimed   bool
+		setupMock func(*mocks.MockRepository)
+	}{
+		{
+			name:   "skip label",
+			rules:  config.RouletteRules{{Pattern: "acme/*", Reviewers: 1, SkipLabels: []string{"no-roulette"}}},
+			labels: []string{"No-Roulette"},
+		},
+		{
+			name:    "reviewer only",
+			rules:   config.RouletteRules{{Pattern: "acme/**", Reviewers: 1}},
+			labels:  []string{"backend"},
+			claimed: true,
+			setupMock: func(m *mocks.MockRepository) {
+				m.On("GetAllUsers", mock.Anything).Return([]*domain.User{alice}, nil)
+				m.On("ListCommits", mock.Anything, 1).Return([]*domain.Commit{}, nil)
+				m.On("GetUserByUsername", mock.Anything, "alice").Return(alice, nil)
+				query := &domain.MergeRequestQuery{State: domain.StateOpened, Scope: domain.ScopeAll}
+				m.On("ListMergeRequests", mock.Anything, query).Return([]*domain.MergeRequest{}, nil)
+				m.On("UpdateMergeRequest", mock.Anything, 1, 42, (*int)(nil), []int{2}).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockRepository{}
+			repo.On("PreloadUsersByUsernames", mock.Anything, []string{"alice"}).Return(nil)
+			repo.On("GetMergeRequest", mock.Anything, 1, 42).Return(&domain.MergeRequest{
+				ProjectID: 1,
+				IID:       42,
+				Author:    &domain.User{ID: 1},
+				Labels:    tt.labels,
+			}, nil)
+			if tt.setupMock != nil {
+				tt.setupMock(repo)
+			}
+
+			appInstance, err := app.NewApp(&config.Config{TeamUsers: []string{"alice"}}, repo)
+			require.NoError(t, err)
+
+			server := NewServer(":0", appInstance, tt.rules)
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(newPayload()))
+
+			w := httptest.NewRecorder()
+			server.handleGitLabWebhook(w, httptest.NewRequest(http.MethodPost, "/gitlab/hook", &body))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			repo.AssertExpectations(t)
+
+			key := domain.MergeRequestKey{ProjectID: 1, IID: 42}
+			assert.Equal(t, !tt.claimed, server.claim(key, time.Now()), "skipped events must not hold the claim")
+		})
+	}
+}
+
+func TestServer_claim(t *testing.T) {
+	server := &Server{}
+	now := time.Now()
+
+	first := domain.MergeRequestKey{ProjectID: 1, IID: 42}
+	sameIIDOtherProject := domain.MergeRequestKey{ProjectID: 2, IID: 42}
+
+	assert.True(t, server.claim(first, now))
+	assert.False(t, server.claim(first, now.Add(time.Second)), "duplicate event should be ignored")
+	assert.True(t, server.claim(sameIIDOtherProject, now), "same IID in another project is a different MR")
+
+	assert.True(t, server.claim(first, now.Add(dedupWindow+time.Second)), "event after the window is processed")
+
+	server.release(sameIIDOtherProject)
+	assert.True(t, server.claim(sameIIDOtherProject, now), "released MR can be claimed again")
+}
@@ -5,25 +5,37 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/denchenko/gg/internal/config"
 	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
 )
 
 const (
 	readTimeout  = 10 * time.Second
 	writeTimeout = 10 * time.Second
 	idleTimeout  = 120 * time.Second
+
+	// dedupWindow is how long a merge request is ignored after being handled,
+	// so that bursts of events for the same MR trigger a single assignment.
+	dedupWindow = time.Minute
 )
 
 // Server represents an HTTP server.
 type Server struct {
 	server *http.Server
 	app    *app.App
+	rules  config.RouletteRules
+
+	mu      sync.Mutex
+	handled map[domain.MergeRequestKey]time.Time
 }
 
-// NewServer creates a new HTTP server.
-func NewServer(addr string, appInstance *app.App) *Server {
+// NewServer creates a new HTTP server. Merge requests are assigned according
+// to the most specific of rules that matches their project.
+func NewServer(addr string, appInstance *app.App, rules config.RouletteRules) *Server {
 	mux := http.NewServeMux()
 
 	s := &Server{
@@ -34,7 +46,9 @@ func NewServer(addr string, appInstance *app.App) *Server {
 			WriteTimeout: writeTimeout,
 			IdleTimeout:  idleTimeout,
 		},
-		app: appInstance,
+		app:     appInstance,
+		rules:   rules,
+		handled: make(map[domain.MergeRequestKey]time.Time),
 	}
 
 	mux.HandleFunc("/gitlab/hook", s.handleGitLabWebhook)
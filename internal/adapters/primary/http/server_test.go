@@ -12,7 +12,7 @@ import (
 func TestNewServer(t *testing.T) {
 	appInstance := &app.App{}
 
-	server := NewServer(":8080", appInstance)
+	server := NewServer(":8080", appInstance, nil)
 
 	assert.NotNil(t, server)
 	assert.NotNil(t, server.server)
@@ -26,14 +26,14 @@ func TestServer_Start(t *testing.T) {
 	// For now, we'll just verify the server can be created
 	appInstance := &app.App{}
 
-	server := NewServer(":0", appInstance) // Use :0 to get a free port
+	server := NewServer(":0", appInstance, nil) // Use :0 to get a free port
 	assert.NotNil(t, server)
 }
 
 func TestServer_Shutdown(t *testing.T) {
 	appInstance := &app.App{}
 
-	server := NewServer(":0", appInstance)
+	server := NewServer(":0", appInstance, nil)
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
 
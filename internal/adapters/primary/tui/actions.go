@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/git"
+	"github.com/skratchdot/open-golang/open"
+)
+
+// load fetches the rows of a tab.
+func (m Model) load(v view) tea.Cmd {
+	appInstance, teamApp, baseURL := m.app, m.teamApp, m.baseURL
+
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		var (
+			rows []row
+			err  error
+		)
+		switch v {
+		case viewMyMRs:
+			var mrs []*domain.MergeRequestWithStatus
+			if mrs, err = appInstance.GetMergeRequestsWithStatus(ctx); err == nil {
+				rows = projectRows(baseURL, mrs)
+			}
+		case viewMyReviews:
+			var mrs []*domain.MergeRequestWithStatus
+			if mrs, err = appInstance.GetMyReviewWorkloadWithStatus(ctx); err == nil {
+				rows = projectRows(baseURL, mrs)
+			}
+		default:
+			var workloads []*domain.UserWorkload
+			if workloads, err = teamApp.AnalyzeActiveMRs(ctx); err == nil {
+				rows = workloadRows(workloads)
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to load %s: %w", viewTitles[v], err)
+		}
+
+		return loadedMsg{view: v, rows: rows, err: err}
+	}
+}
+
+// projectRows lists merge requests under a heading per project, keeping
+// their order.
+func projectRows(baseURL string, mrs []*domain.MergeRequestWithStatus) []row {
+	var (
+		rows     []row
+		projects []string
+	)
+	byProject := make(map[string][]*domain.MergeRequestWithStatus)
+	for _, mr := range mrs {
+		project := format.ProjectName(baseURL, mr.WebURL)
+		if _, ok := byProject[project]; !ok {
+			projects = append(projects, project)
+		}
+		byProject[project] = append(byProject[project], mr)
+	}
+
+	for _, project := range projects {
+		rows = append(rows, row{heading: project})
+		for _, mr := range byProject[project] {
+			rows = append(rows, row{mr: mr.MergeRequest, status: mr})
+		}
+	}
+
+	return rows
+}
+
+// workloadRows lists the merge requests of every team member under a heading
+// naming the member.
+func workloadRows(workloads []*domain.UserWorkload) []row {
+	var rows []row
+	for _, w := range workloads {
+		heading := fmt.Sprintf("%s (%d active)", w.User.Username, w.MRCount)
+		if w.User.Status.Availability != "" {
+			heading += " - " + w.User.Status.Availability
+		}
+		rows = append(rows, row{heading: heading})
+		for _, mr := range w.ActiveMRs {
+			rows = append(rows, row{mr: mr})
+		}
+	}
+
+	return rows
+}
+
+func openInBrowser(webURL, reference string) tea.Cmd {
+	return func() tea.Msg {
+		if err := open.Start(webURL); err != nil {
+			return doneMsg{err: fmt.Errorf("failed to open browser: %w", err)}
+		}
+
+		return doneMsg{message: "Opened " + reference + " in the browser"}
+	}
+}
+
+func approve(appInstance *app.App, mr *domain.MergeRequest, reference string) tea.Cmd {
+	return func() tea.Msg {
+		if err := appInstance.ApproveMergeRequest(context.Background(), mr.ProjectID, mr.IID); err != nil {
+			return doneMsg{err: err}
+		}
+
+		return doneMsg{message: "Approved " + reference, reload: true}
+	}
+}
+
+// checkout checks out the source branch of a merge request of the project of
+// the current working directory.
+func checkout(appInstance *app.App, mr *domain.MergeRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		project, _, err := appInstance.GetCurrentProjectInfo(ctx)
+		if err != nil {
+			return doneMsg{err: fmt.Errorf("failed to get current project: %w", err)}
+		}
+		if project.ID != mr.ProjectID {
+			return doneMsg{err: errors.New("the merge request does not belong to the repository of the current directory")}
+		}
+
+		branch := appInstance.LocalBranch(ctx, mr)
+		if err := git.Checkout(ctx, mr.HeadRef, branch); err != nil {
+			return doneMsg{err: err}
+		}
+
+		return doneMsg{message: "Checked out " + branch, reload: true}
+	}
+}
+
+func suggest(appInstance *app.App, mr *domain.MergeRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		workloads, err := appInstance.AnalyzeWorkload(ctx, mr.ProjectID)
+		if err != nil {
+			return suggestionMsg{err: fmt.Errorf("failed to analyze workload: %w", err)}
+		}
+
+		assignee, reviewer, err := appInstance.SuggestAssigneeAndReviewer(ctx, mr, workloads)
+		if err != nil {
+			return suggestionMsg{err: fmt.Errorf("failed to get suggestions: %w", err)}
+		}
+
+		return suggestionMsg{suggestion: &suggestion{app: appInstance, mr: mr, assignee: assignee, reviewer: reviewer}}
+	}
+}
+
+func applySuggestion(s *suggestion, reference string) tea.Cmd {
+	return func() tea.Msg {
+		var (
+			assigneeID  *int
+			reviewerIDs []int
+		)
+		if s.assignee != nil {
+			assigneeID = &s.assignee.ID
+		}
+		if s.reviewer != nil {
+			reviewerIDs = []int{s.reviewer.ID}
+		}
+
+		err := s.app.UpdateMergeRequest(context.Background(), s.mr.ProjectID, s.mr.IID, assigneeID, reviewerIDs)
+		if err != nil {
+			return doneMsg{err: err}
+		}
+
+		return doneMsg{message: "Applied suggestions to " + reference, reload: true}
+	}
+}
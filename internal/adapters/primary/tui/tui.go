@@ -0,0 +1,559 @@
+// Package tui implements the full-screen terminal dashboard of "gg ui": tabs
+// with my merge requests, my reviews and the team workload, refreshed in the
+// background, with actions on the selected merge request.
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+)
+
+// view identifies a tab of the dashboard.
+type view int
+
+const (
+	viewMyMRs view = iota
+	viewMyReviews
+	viewTeam
+
+	viewCount
+)
+
+// chromeLines is the number of lines around the list: the tabs, two rules,
+// the status line and the key help.
+const chromeLines = 5
+
+const (
+	ansiReset   = "\033[0m"
+	ansiBold    = "\033[1m"
+	ansiReverse = "\033[7m"
+	ansiRed     = "\033[31m"
+	ansiGreen   = "\033[32m"
+	ansiYellow  = "\033[33m"
+	ansiCyan    = "\033[36m"
+	ansiFaint   = "\033[2m"
+)
+
+var viewTitles = [viewCount]string{"My merge requests", "My reviews", "Team review"}
+
+const keyHelp = "↑/↓ move · tab switch · o open · a approve · c checkout · r roulette · ctrl+r refresh · q quit"
+
+// row is a line of a tab: either a heading or a merge request.
+type row struct {
+	heading string
+	mr      *domain.MergeRequest
+	status  *domain.MergeRequestWithStatus
+}
+
+// suggestion is a roulette result waiting for confirmation.
+type suggestion struct {
+	app      *app.App
+	mr       *domain.MergeRequest
+	assignee *domain.User
+	reviewer *domain.User
+}
+
+// Model is the state of the dashboard.
+type Model struct {
+	app      *app.App
+	teamApp  *app.App
+	baseURL  string
+	interval time.Duration
+	color    bool
+
+	view    view
+	rows    [viewCount][]row
+	cursor  [viewCount]int
+	offset  [viewCount]int
+	loading [viewCount]bool
+	updated [viewCount]time.Time
+
+	pending *suggestion
+	message string
+	err     error
+
+	width, height int
+}
+
+// New creates the dashboard. The team tab and the roulette use the named
+// team, or the default team when team is empty. The tabs are reloaded every
+// interval, unless it is zero.
+func New(appInstance *app.App, baseURL, team string, interval time.Duration, color bool) (Model, error) {
+	teamApp, err := appInstance.ForTeam(team)
+	if err != nil {
+		return Model{}, err
+	}
+
+	return Model{
+		app:      appInstance,
+		teamApp:  teamApp,
+		baseURL:  baseURL,
+		interval: interval,
+		color:    color,
+		// Init starts loading every tab.
+		loading: [viewCount]bool{true, true, true},
+	}, nil
+}
+
+// loadedMsg carries the rows of a reloaded tab.
+type loadedMsg struct {
+	view view
+	rows []row
+	err  error
+}
+
+// tickMsg triggers the periodic refresh.
+type tickMsg struct{}
+
+// doneMsg reports the outcome of an action.
+type doneMsg struct {
+	message string
+	err     error
+	reload  bool
+}
+
+// suggestionMsg carries the roulette result for the selected merge request.
+type suggestionMsg struct {
+	suggestion *suggestion
+	err        error
+}
+
+// Init implements tea.Model.
+func (m Model) Init() tea.Cmd {
+	m, cmd := m.reloadAll()
+
+	return tea.Batch(cmd, m.tick())
+}
+
+// Update implements tea.Model.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+
+		return m, nil
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	case loadedMsg:
+		m.loading[msg.view] = false
+		if msg.err != nil {
+			m.err = msg.err
+
+			return m, nil
+		}
+		m.setRows(msg.view, msg.rows)
+		m.updated[msg.view] = time.Now()
+
+		return m, nil
+	case tickMsg:
+		m, cmd := m.reloadAll()
+
+		return m, tea.Batch(cmd, m.tick())
+	case doneMsg:
+		m.message, m.err = msg.message, msg.err
+		if msg.reload && msg.err == nil {
+			return m.reloadAll()
+		}
+
+		return m, nil
+	case suggestionMsg:
+		return m.handleSuggestion(msg)
+	}
+
+	return m, nil
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	if m.pending != nil {
+		pending := m.pending
+		m.pending = nil
+		if key != "y" && key != "Y" {
+			m.message = "Roulette cancelled"
+
+			return m, nil
+		}
+		m.message = "Applying suggestions..."
+
+		return m, applySuggestion(pending, m.reference(pending.mr))
+	}
+
+	switch key {
+	case "q", "esc":
+		return m, tea.Quit
+	case "tab", "right", "l":
+		m.view = (m.view + 1) % viewCount
+		m.scroll()
+	case "shift+tab", "left", "h":
+		m.view = (m.view + viewCount - 1) % viewCount
+		m.scroll()
+	case "1", "2", "3":
+		m.view = view(key[0] - '1')
+		m.scroll()
+	case "down", "j":
+		m.move(1)
+	case "up", "k":
+		m.move(-1)
+	case "pgdown":
+		m.move(m.listHeight())
+	case "pgup":
+		m.move(-m.listHeight())
+	case "ctrl+r", "f5":
+		m.message, m.err = "", nil
+
+		return m.reloadAll()
+	case "enter", "o", "a", "c", "r":
+		return m.act(key)
+	}
+
+	return m, nil
+}
+
+// act runs the action bound to key on the selected merge request.
+func (m Model) act(key string) (tea.Model, tea.Cmd) {
+	mr := m.selected()
+	if mr == nil {
+		m.message = "No merge request selected"
+
+		return m, nil
+	}
+
+	m.message, m.err = "", nil
+	switch key {
+	case "enter", "o":
+		return m, openInBrowser(mr.WebURL, m.reference(mr))
+	case "a":
+		m.message = fmt.Sprintf("Approving %s...", m.reference(mr))
+
+		return m, approve(m.app, mr, m.reference(mr))
+	case "c":
+		m.message = fmt.Sprintf("Checking out %s...", mr.SourceBranch)
+
+		return m, checkout(m.app, mr)
+	default:
+		m.message = fmt.Sprintf("Calculating suggestions for %s...", m.reference(mr))
+
+		return m, suggest(m.rouletteApp(mr), mr)
+	}
+}
+
+func (m Model) handleSuggestion(msg suggestionMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.message, m.err = "", msg.err
+
+		return m, nil
+	}
+
+	s := msg.suggestion
+	if s.assignee == nil && s.reviewer == nil {
+		m.message = "No suitable assignee or reviewer found"
+
+		return m, nil
+	}
+
+	m.pending = s
+	m.message = fmt.Sprintf("Assignee: %s, reviewer: %s. Apply to %s? [y/N]",
+		username(s.assignee), username(s.reviewer), m.reference(s.mr))
+
+	return m, nil
+}
+
+// rouletteApp returns the application to draw candidates from: the team
+// selected on the command line, else the default team of the project.
+func (m Model) rouletteApp(mr *domain.MergeRequest) *app.App {
+	if m.teamApp != m.app {
+		return m.teamApp
+	}
+
+	return m.app.ForProject(format.ProjectName(m.baseURL, mr.WebURL))
+}
+
+func (m Model) reloadAll() (Model, tea.Cmd) {
+	cmds := make([]tea.Cmd, 0, viewCount)
+	for v := range viewCount {
+		m.loading[v] = true
+		cmds = append(cmds, m.load(v))
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) tick() tea.Cmd {
+	if m.interval <= 0 {
+		return nil
+	}
+
+	return tea.Tick(m.interval, func(time.Time) tea.Msg {
+		return tickMsg{}
+	})
+}
+
+// setRows replaces the rows of a tab, keeping the selected merge request
+// selected when it is still listed.
+func (m *Model) setRows(v view, rows []row) {
+	var selectedID int
+	if mr := m.selectedIn(v); mr != nil {
+		selectedID = mr.ID
+	}
+
+	m.rows[v] = rows
+	m.cursor[v] = -1
+	for i, r := range rows {
+		if r.mr == nil {
+			continue
+		}
+		if m.cursor[v] < 0 || r.mr.ID == selectedID {
+			m.cursor[v] = i
+		}
+		if r.mr.ID == selectedID {
+			break
+		}
+	}
+	if m.cursor[v] < 0 {
+		m.cursor[v] = 0
+	}
+
+	if v == m.view {
+		m.scroll()
+	}
+}
+
+// move moves the cursor by delta merge requests, skipping headings.
+func (m *Model) move(delta int) {
+	rows := m.rows[m.view]
+	step := 1
+	if delta < 0 {
+		step, delta = -1, -delta
+	}
+
+	cursor := m.cursor[m.view]
+	for i := cursor + step; i >= 0 && i < len(rows) && delta > 0; i += step {
+		if rows[i].mr != nil {
+			cursor = i
+			delta--
+		}
+	}
+	m.cursor[m.view] = cursor
+	m.scroll()
+}
+
+// scroll keeps the cursor of the current tab in sight.
+func (m *Model) scroll() {
+	height := m.listHeight()
+	if height <= 0 {
+		return
+	}
+
+	v := m.view
+	cursor := m.cursor[v]
+	if cursor < m.offset[v] {
+		m.offset[v] = cursor
+		// Show the heading of the first merge request of a group.
+		if cursor > 0 && m.rows[v][cursor-1].mr == nil {
+			m.offset[v]--
+		}
+	}
+	if cursor >= m.offset[v]+height {
+		m.offset[v] = cursor - height + 1
+	}
+}
+
+func (m Model) listHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+
+	return max(m.height-chromeLines, 1)
+}
+
+func (m Model) selected() *domain.MergeRequest {
+	return m.selectedIn(m.view)
+}
+
+func (m Model) selectedIn(v view) *domain.MergeRequest {
+	rows := m.rows[v]
+	if cursor := m.cursor[v]; cursor < len(rows) {
+		return rows[cursor].mr
+	}
+
+	return nil
+}
+
+// View implements tea.Model.
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.tabs())
+	b.WriteString("\n")
+	b.WriteString(m.rule())
+	b.WriteString("\n")
+
+	rows := m.rows[m.view]
+	start, end := 0, len(rows)
+	if height := m.listHeight(); height > 0 {
+		start = min(m.offset[m.view], len(rows))
+		end = min(start+height, len(rows))
+	}
+
+	lines := 0
+	switch {
+	case len(rows) == 0 && m.loading[m.view]:
+		b.WriteString("Loading...\n")
+		lines++
+	case len(rows) == 0:
+		b.WriteString("Nothing to show.\n")
+		lines++
+	}
+	for i := start; i < end; i++ {
+		b.WriteString(m.renderRow(rows[i], i == m.cursor[m.view]))
+		b.WriteString("\n")
+		lines++
+	}
+	for ; lines < m.listHeight(); lines++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.rule())
+	b.WriteString("\n")
+	b.WriteString(m.statusLine())
+	b.WriteString("\n")
+	b.WriteString(m.paint(ansiFaint, m.fit(keyHelp)))
+
+	return b.String()
+}
+
+func (m Model) tabs() string {
+	parts := make([]string, 0, viewCount)
+	for v := range viewCount {
+		title := fmt.Sprintf(" %d %s ", v+1, viewTitles[v])
+		if v == m.view {
+			title = m.paint(ansiReverse, title)
+		}
+		parts = append(parts, title)
+	}
+
+	line := strings.Join(parts, " ")
+	switch {
+	case m.loading[m.view]:
+		line += "  refreshing..."
+	case !m.updated[m.view].IsZero():
+		line += "  updated " + m.updated[m.view].Format("15:04:05")
+	}
+
+	return line
+}
+
+func (m Model) rule() string {
+	width := m.width
+	if width == 0 {
+		width = 80
+	}
+
+	return strings.Repeat("─", width)
+}
+
+func (m Model) statusLine() string {
+	if m.err != nil {
+		return m.paint(ansiRed, m.fit("Error: "+m.err.Error()))
+	}
+
+	return m.fit(m.message)
+}
+
+func (m Model) renderRow(r row, selected bool) string {
+	if r.mr == nil {
+		return m.paint(ansiBold, m.fit(r.heading))
+	}
+
+	label, color := statusLabel(r)
+	text := "  " + r.mr.Title + "  " + m.reference(r.mr)
+	if r.mr.Author != nil {
+		text += " by " + r.mr.Author.Username
+	}
+
+	plain := m.fit(label + text)
+	if selected {
+		return m.paint(ansiReverse, plain)
+	}
+	if label == "" || utf8.RuneCountInString(plain) < utf8.RuneCountInString(label) {
+		return plain
+	}
+
+	return m.paint(color, label) + plain[len(label):]
+}
+
+// statusLabel labels draft, pipeline-failed, needs-rebase, stalled,
+// ready-to-merge and answered merge requests, as the table output does.
+func statusLabel(r row) (string, string) {
+	var label, color string
+	if r.mr.Draft {
+		label, color = "[draft]", ansiYellow
+	}
+	if r.status != nil {
+		switch format.Status(r.status) {
+		case format.StatusPipelineFailed:
+			label, color = strings.TrimSpace(label+" [pipeline-failed]"), ansiRed
+		case format.StatusNeedsRebase:
+			label, color = strings.TrimSpace(label+" [needs-rebase]"), ansiRed
+		case format.StatusStalled:
+			label, color = strings.TrimSpace(label+" [stalled]"), ansiRed
+		case format.StatusReadyToMerge:
+			label, color = strings.TrimSpace(label+" [ready-to-merge]"), ansiGreen
+		}
+		if r.status.IsAnswered {
+			label = strings.TrimSpace(label + " [answered]")
+			if color == "" {
+				color = ansiCyan
+			}
+		}
+	}
+	if label != "" {
+		label = "  " + label
+	}
+
+	return label, color
+}
+
+// fit cuts text to the width of the terminal.
+func (m Model) fit(text string) string {
+	if m.width <= 0 || utf8.RuneCountInString(text) <= m.width {
+		return text
+	}
+
+	runes := []rune(text)
+
+	return strings.TrimRight(string(runes[:m.width-1]), " ") + "…"
+}
+
+func (m Model) paint(code, text string) string {
+	if !m.color || text == "" {
+		return text
+	}
+
+	return code + text + ansiReset
+}
+
+// reference returns the short reference of a merge request, project!iid.
+func (m Model) reference(mr *domain.MergeRequest) string {
+	return format.Reference(m.baseURL, mr)
+}
+
+func username(user *domain.User) string {
+	if user == nil {
+		return "none"
+	}
+
+	return user.Username
+}
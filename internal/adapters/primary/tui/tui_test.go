@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/mocks"
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/app"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const baseURL = "https://gitlab.example.com"
+
+func newTestModel(t *testing.T) (Model, *mocks.MockRepository) {
+	t.Helper()
+
+	repo := &mocks.MockRepository{}
+	repo.On("PreloadUsersByUsernames", mock.Anything, []string{"alice"}).Return(nil)
+
+	appInstance, err := app.NewApp(&config.Config{TeamUsers: []string{"alice"}}, repo)
+	require.NoError(t, err)
+
+	m, err := New(appInstance, baseURL, "", 0, false)
+	require.NoError(t, err)
+
+	return m, repo
+}
+
+func testMergeRequest(id int, project string) *domain.MergeRequestWithStatus {
+	return &domain.MergeRequestWithStatus{
+		MergeRequest: &domain.MergeRequest{
+			ID:        id,
+			IID:       id,
+			ProjectID: 1,
+			Title:     "Change " + project,
+			WebURL:    baseURL + "/acme/" + project + "/-/merge_requests/" + string(rune('0'+id)),
+			Author:    &domain.User{ID: 1, Username: "alice"},
+		},
+	}
+}
+
+func update(t *testing.T, m Model, msg tea.Msg) (Model, tea.Cmd) {
+	t.Helper()
+
+	updated, cmd := m.Update(msg)
+	model, ok := updated.(Model)
+	require.True(t, ok)
+
+	return model, cmd
+}
+
+func key(k string) tea.KeyMsg {
+	switch k {
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	}
+}
+
+func TestProjectRows(t *testing.T) {
+	rows := projectRows(baseURL, []*domain.MergeRequestWithStatus{
+		testMergeRequest(1, "web"), testMergeRequest(2, "api"), testMergeRequest(3, "web"),
+	})
+
+	require.Len(t, rows, 5)
+	assert.Equal(t, "acme/web", rows[0].heading)
+	assert.Equal(t, 1, rows[1].mr.ID)
+	assert.Equal(t, 3, rows[2].mr.ID)
+	assert.Equal(t, "acme/api", rows[3].heading)
+	assert.Equal(t, 2, rows[4].mr.ID)
+}
+
+func TestModel_navigation(t *testing.T) {
+	m, _ := newTestModel(t)
+	rows := projectRows(baseURL, []*domain.MergeRequestWithStatus{
+		testMergeRequest(1, "web"), testMergeRequest(2, "api"), testMergeRequest(3, "api"),
+	})
+
+	m, _ = update(t, m, loadedMsg{view: viewMyMRs, rows: rows})
+	assert.Equal(t, 1, m.selected().ID, "headings are skipped")
+
+	m, _ = update(t, m, key("down"))
+	assert.Equal(t, 2, m.selected().ID)
+	m, _ = update(t, m, key("j"))
+	m, _ = update(t, m, key("j"))
+	assert.Equal(t, 3, m.selected().ID, "the cursor stops at the last merge request")
+	m, _ = update(t, m, key("up"))
+	assert.Equal(t, 2, m.selected().ID)
+
+	// Reloading keeps the selected merge request selected.
+	reordered := projectRows(baseURL, []*domain.MergeRequestWithStatus{
+		testMergeRequest(2, "api"), testMergeRequest(1, "web"),
+	})
+	m, _ = update(t, m, loadedMsg{view: viewMyMRs, rows: reordered})
+	assert.Equal(t, 2, m.selected().ID)
+
+	m, _ = update(t, m, key("tab"))
+	assert.Equal(t, viewMyReviews, m.view)
+	assert.Nil(t, m.selected())
+	m, _ = update(t, m, key("3"))
+	assert.Equal(t, viewTeam, m.view)
+
+	m, cmd := update(t, m, key("a"))
+	assert.Nil(t, cmd)
+	assert.Equal(t, "No merge request selected", m.message)
+}
+
+func TestModel_View(t *testing.T) {
+	m, _ := newTestModel(t)
+	m, _ = update(t, m, tea.WindowSizeMsg{Width: 40, Height: 10})
+
+	assert.Contains(t, m.View(), "Loading...")
+
+	mr := testMergeRequest(1, "web")
+	mr.IsStalled = true
+	m, _ = update(t, m, loadedMsg{view: viewMyMRs, rows: projectRows(baseURL, []*domain.MergeRequestWithStatus{mr})})
+	m, _ = update(t, m, loadedMsg{view: viewMyMRs, err: errors.New("boom")})
+
+	view := m.View()
+	assert.Contains(t, view, " 1 My merge requests ")
+	assert.Contains(t, view, "acme/web\n")
+	assert.Contains(t, view, "  [stalled]  Change web  acme/web!1 by…\n")
+	assert.Contains(t, view, "Error: boom")
+	assert.Len(t, splitLines(view), 10)
+}
+
+func TestModel_approve(t *testing.T) {
+	m, repo := newTestModel(t)
+	repo.On("ApproveMergeRequest", mock.Anything, 1, 1).Return(nil)
+
+	m, _ = update(t, m, loadedMsg{view: viewMyReviews, rows: projectRows(baseURL,
+		[]*domain.MergeRequestWithStatus{testMergeRequest(1, "web")})})
+	m, _ = update(t, m, key("2"))
+
+	m, cmd := update(t, m, key("a"))
+	require.NotNil(t, cmd)
+	assert.Equal(t, "Approving acme/web!1...", m.message)
+
+	msg := cmd()
+	assert.Equal(t, doneMsg{message: "Approved acme/web!1", reload: true}, msg)
+	repo.AssertExpectations(t)
+
+	m, cmd = update(t, m, msg)
+	assert.NotNil(t, cmd, "approving reloads the tabs")
+	assert.True(t, m.loading[viewMyReviews])
+}
+
+func TestModel_roulette(t *testing.T) {
+	m, repo := newTestModel(t)
+	mr := testMergeRequest(1, "web").MergeRequest
+	bob := &domain.User{ID: 2, Username: "bob"}
+
+	m, _ = update(t, m, suggestionMsg{suggestion: &suggestion{app: m.app, mr: mr, reviewer: bob}})
+	assert.Equal(t, "Assignee: none, reviewer: bob. Apply to acme/web!1? [y/N]", m.message)
+
+	cancelled, cmd := update(t, m, key("n"))
+	assert.Nil(t, cmd)
+	assert.Nil(t, cancelled.pending)
+	assert.Equal(t, "Roulette cancelled", cancelled.message)
+
+	repo.On("UpdateMergeRequest", mock.Anything, 1, 1, (*int)(nil), []int{2}).Return(nil)
+	m, cmd = update(t, m, key("y"))
+	require.NotNil(t, cmd)
+	assert.Nil(t, m.pending)
+	assert.Equal(t, doneMsg{message: "Applied suggestions to acme/web!1", reload: true}, cmd())
+	repo.AssertExpectations(t)
+
+	m, _ = update(t, m, suggestionMsg{suggestion: &suggestion{app: m.app, mr: mr}})
+	assert.Nil(t, m.pending)
+	assert.Equal(t, "No suitable assignee or reviewer found", m.message)
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	start := 0
+	for i, c := range s {
+		if c == '\n' {
+			lines = append(lines, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(lines, s[start:])
+}
@@ -2,7 +2,7 @@ package cache
 
 import "github.com/denchenko/gg/internal/core/domain"
 
-// Cache defines the interface for user caching operations.
+// Cache defines the interface for user and approval caching operations.
 type Cache interface {
 	// GetUserByID retrieves a user by ID from the cache.
 	// Returns the user and true if found, nil and false otherwise.
@@ -17,4 +17,14 @@ type Cache interface {
 
 	// GetAllUsers retrieves all users from the cache.
 	GetAllUsers() []*domain.User
+
+	// GetApprovals retrieves the approvals of a merge request from the cache.
+	// Returns the approvals and true if found and not expired, nil and false otherwise.
+	GetApprovals(key domain.MergeRequestKey) (*domain.Approvals, bool)
+
+	// StoreApprovals stores the approvals of a merge request in the cache.
+	StoreApprovals(key domain.MergeRequestKey, approvals *domain.Approvals)
+
+	// DeleteApprovals removes the approvals of a merge request from the cache.
+	DeleteApprovals(key domain.MergeRequestKey)
 }
@@ -2,14 +2,26 @@ package cache
 
 import (
 	"sync"
+	"time"
 
 	"github.com/denchenko/gg/internal/core/domain"
 )
 
+// approvalsTTL bounds how long approvals are reused. Unlike users, approvals
+// change while the webhook server is running.
+const approvalsTTL = time.Minute
+
 // InMemoryCache is an in-memory thread-safe cache implementation for users.
 type InMemoryCache struct {
 	byID       sync.Map // map[int]*domain.User
 	byUsername sync.Map // map[string]*domain.User
+	approvals  sync.Map // map[domain.MergeRequestKey]approvalsEntry
+	now        func() time.Time
+}
+
+type approvalsEntry struct {
+	approvals *domain.Approvals
+	storedAt  time.Time
 }
 
 // NewInMemoryCache creates a new in-memory cache instance.
@@ -17,6 +29,8 @@ func NewInMemoryCache() *InMemoryCache {
 	return &InMemoryCache{
 		byID:       sync.Map{},
 		byUsername: sync.Map{},
+		approvals:  sync.Map{},
+		now:        time.Now,
 	}
 }
 
@@ -62,3 +76,30 @@ func (c *InMemoryCache) GetAllUsers() []*domain.User {
 
 	return users
 }
+
+// GetApprovals retrieves the approvals of a merge request from the cache.
+func (c *InMemoryCache) GetApprovals(key domain.MergeRequestKey) (*domain.Approvals, bool) {
+	cached, ok := c.approvals.Load(key)
+	if !ok {
+		return nil, false
+	}
+
+	entry, ok := cached.(approvalsEntry)
+	if !ok || c.now().Sub(entry.storedAt) > approvalsTTL {
+		c.approvals.Delete(key)
+
+		return nil, false
+	}
+
+	return entry.approvals, true
+}
+
+// StoreApprovals stores the approvals of a merge request in the cache.
+func (c *InMemoryCache) StoreApprovals(key domain.MergeRequestKey, approvals *domain.Approvals) {
+	c.approvals.Store(key, approvalsEntry{approvals: approvals, storedAt: c.now()})
+}
+
+// DeleteApprovals removes the approvals of a merge request from the cache.
+func (c *InMemoryCache) DeleteApprovals(key domain.MergeRequestKey) {
+	c.approvals.Delete(key)
+}
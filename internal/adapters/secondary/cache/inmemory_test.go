@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryCache_Approvals(t *testing.T) {
+	c := NewInMemoryCache()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	first := domain.MergeRequestKey{ProjectID: 1, IID: 42}
+	second := domain.MergeRequestKey{ProjectID: 2, IID: 42}
+
+	c.StoreApprovals(first, domain.NewApprovals([]*domain.User{{ID: 1}}))
+	c.StoreApprovals(second, domain.NewApprovals([]*domain.User{{ID: 2}}))
+
+	approvals, ok := c.GetApprovals(first)
+	require.True(t, ok)
+	assert.Equal(t, []*domain.User{{ID: 1}}, approvals.ApprovedBy)
+
+	approvals, ok = c.GetApprovals(second)
+	require.True(t, ok)
+	assert.Equal(t, []*domain.User{{ID: 2}}, approvals.ApprovedBy)
+
+	now = now.Add(approvalsTTL + time.Second)
+
+	_, ok = c.GetApprovals(first)
+	assert.False(t, ok, "expired approvals should not be returned")
+}
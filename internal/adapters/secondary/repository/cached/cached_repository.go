@@ -6,11 +6,16 @@ import (
 	"time"
 
 	"github.com/denchenko/gg/internal/adapters/secondary/cache"
-	"github.com/denchenko/gg/internal/adapters/secondary/repository/gitlab"
 	"github.com/denchenko/gg/internal/core/app"
 	"github.com/denchenko/gg/internal/core/domain"
 )
 
+// usersFetcher is implemented by repositories able to fetch users by username
+// in bulk, which lets the cache be warmed up front.
+type usersFetcher interface {
+	FetchUsersByUsernames(ctx context.Context, usernames []string) ([]*domain.User, error)
+}
+
 // CachedRepository wraps a Repository with caching functionality.
 type CachedRepository struct {
 	repo  app.Repository
@@ -35,13 +40,14 @@ func (r *CachedRepository) GetProject(ctx context.Context, path string) (*domain
 	return project, nil
 }
 
-// ListMergeRequests lists merge requests with the given state and scope.
+// ListMergeRequests lists the merge requests selected by query. Approvals
+// reported along with the merge requests are cached for
+// GetMergeRequestApprovals.
 func (r *CachedRepository) ListMergeRequests(
 	ctx context.Context,
-	state string,
-	scope ...string,
+	query *domain.MergeRequestQuery,
 ) ([]*domain.MergeRequest, error) {
-	mrs, err := r.repo.ListMergeRequests(ctx, state, scope...)
+	mrs, err := r.repo.ListMergeRequests(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list merge requests: %w", err)
 	}
@@ -58,6 +64,14 @@ func (r *CachedRepository) ListMergeRequests(
 				r.cache.StoreUser(reviewer)
 			}
 		}
+		if mr.Approvals != nil {
+			for _, user := range mr.Approvals.ApprovedBy {
+				if user != nil {
+					r.cache.StoreUser(user)
+				}
+			}
+			r.cache.StoreApprovals(mr.Key(), mr.Approvals)
+		}
 	}
 
 	return mrs, nil
@@ -67,19 +81,55 @@ func (r *CachedRepository) ListMergeRequests(
 func (r *CachedRepository) GetMergeRequestApprovals(
 	ctx context.Context,
 	projectID, mrID int,
-) ([]*domain.User, error) {
-	users, err := r.repo.GetMergeRequestApprovals(ctx, projectID, mrID)
+) (*domain.Approvals, error) {
+	key := domain.MergeRequestKey{ProjectID: projectID, IID: mrID}
+	if approvals, ok := r.cache.GetApprovals(key); ok {
+		return approvals, nil
+	}
+
+	approvals, err := r.repo.GetMergeRequestApprovals(ctx, projectID, mrID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merge request approvals: %w", err)
 	}
 
-	for _, user := range users {
+	for _, user := range approvals.ApprovedBy {
 		if user != nil {
 			r.cache.StoreUser(user)
 		}
 	}
 
-	return users, nil
+	r.cache.StoreApprovals(key, approvals)
+
+	return approvals, nil
+}
+
+// GetMergeRequestApprovalRules retrieves the approval rules of a merge
+// request. They are not cached, as they are only fetched for a single merge
+// request.
+func (r *CachedRepository) GetMergeRequestApprovalRules(
+	ctx context.Context,
+	projectID, mrID int,
+) ([]*domain.ApprovalRule, error) {
+	rules, err := r.repo.GetMergeRequestApprovalRules(ctx, projectID, mrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request approval rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// ListMergeRequestDiscussions retrieves the discussions of a merge request.
+// They are not cached, replies and resolutions have to show up right away.
+func (r *CachedRepository) ListMergeRequestDiscussions(
+	ctx context.Context,
+	projectID, mrID int,
+) ([]*domain.Discussion, error) {
+	discussions, err := r.repo.ListMergeRequestDiscussions(ctx, projectID, mrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge request discussions: %w", err)
+	}
+
+	return discussions, nil
 }
 
 // GetMergeRequest retrieves a merge request by project ID and MR ID.
@@ -109,16 +159,16 @@ func (r *CachedRepository) GetMergeRequest(
 
 // PreloadUsersByUsernames loads users by their usernames and caches them.
 func (r *CachedRepository) PreloadUsersByUsernames(ctx context.Context, usernames []string) error {
-	gitlabRepo, ok := r.repo.(*gitlab.Repository)
-	if !ok {
-		if err := r.repo.PreloadUsersByUsernames(ctx, usernames); err != nil {
-			return fmt.Errorf("failed to preload users: %w", err)
-		}
+	if err := r.repo.PreloadUsersByUsernames(ctx, usernames); err != nil {
+		return fmt.Errorf("failed to preload users: %w", err)
+	}
 
+	fetcher, ok := r.repo.(usersFetcher)
+	if !ok {
 		return nil
 	}
 
-	users, err := gitlabRepo.FetchUsersByUsernames(ctx, usernames)
+	users, err := fetcher.FetchUsersByUsernames(ctx, usernames)
 	if err != nil {
 		return fmt.Errorf("failed to fetch users: %w", err)
 	}
@@ -165,6 +215,24 @@ func (r *CachedRepository) GetUserByUsername(ctx context.Context, username strin
 	return user, nil
 }
 
+// ListGroupMembers lists the members of a group and caches them. The
+// membership itself is not cached, so that long-running processes notice
+// changes.
+func (r *CachedRepository) ListGroupMembers(ctx context.Context, groupPath string) ([]*domain.User, error) {
+	users, err := r.repo.ListGroupMembers(ctx, groupPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group members: %w", err)
+	}
+
+	for _, user := range users {
+		if user != nil {
+			r.cache.StoreUser(user)
+		}
+	}
+
+	return users, nil
+}
+
 // GetCurrentUser gets the current authenticated user.
 func (r *CachedRepository) GetCurrentUser(ctx context.Context) (*domain.User, error) {
 	user, err := r.repo.GetCurrentUser(ctx)
@@ -179,6 +247,20 @@ func (r *CachedRepository) GetCurrentUser(ctx context.Context) (*domain.User, er
 	return user, nil
 }
 
+// GetTokenInfo gets the access token in use. It is not cached.
+func (r *CachedRepository) GetTokenInfo(ctx context.Context) (*domain.TokenInfo, error) {
+	info, err := r.repo.GetTokenInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token info: %w", err)
+	}
+
+	if info.User != nil {
+		r.cache.StoreUser(info.User)
+	}
+
+	return info, nil
+}
+
 // ListCommits lists commits for a project.
 func (r *CachedRepository) ListCommits(ctx context.Context, projectID int) ([]*domain.Commit, error) {
 	commits, err := r.repo.ListCommits(ctx, projectID)
@@ -203,6 +285,67 @@ func (r *CachedRepository) UpdateMergeRequest(
 	return nil
 }
 
+// CreateMergeRequest creates a merge request.
+func (r *CachedRepository) CreateMergeRequest(
+	ctx context.Context,
+	projectID int,
+	opts *domain.NewMergeRequest,
+) (*domain.MergeRequest, error) {
+	mr, err := r.repo.CreateMergeRequest(ctx, projectID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+
+	return mr, nil
+}
+
+// ApproveMergeRequest approves a merge request as the current user. The cached
+// approvals of the merge request are dropped, so the next read sees the change.
+func (r *CachedRepository) ApproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	if err := r.repo.ApproveMergeRequest(ctx, projectID, mrID); err != nil {
+		return fmt.Errorf("failed to approve merge request: %w", err)
+	}
+
+	r.cache.DeleteApprovals(domain.MergeRequestKey{ProjectID: projectID, IID: mrID})
+
+	return nil
+}
+
+// UnapproveMergeRequest withdraws the approval of the current user.
+func (r *CachedRepository) UnapproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	if err := r.repo.UnapproveMergeRequest(ctx, projectID, mrID); err != nil {
+		return fmt.Errorf("failed to unapprove merge request: %w", err)
+	}
+
+	r.cache.DeleteApprovals(domain.MergeRequestKey{ProjectID: projectID, IID: mrID})
+
+	return nil
+}
+
+// RebaseMergeRequest starts rebasing a merge request on the server.
+func (r *CachedRepository) RebaseMergeRequest(ctx context.Context, projectID, mrID int) error {
+	if err := r.repo.RebaseMergeRequest(ctx, projectID, mrID); err != nil {
+		return fmt.Errorf("failed to rebase merge request: %w", err)
+	}
+
+	return nil
+}
+
+// MergeMergeRequest merges a merge request.
+func (r *CachedRepository) MergeMergeRequest(
+	ctx context.Context,
+	projectID, mrID int,
+	opts *domain.MergeOptions,
+) error {
+	if err := r.repo.MergeMergeRequest(ctx, projectID, mrID, opts); err != nil {
+		return fmt.Errorf("failed to merge merge request: %w", err)
+	}
+
+	r.cache.DeleteApprovals(domain.MergeRequestKey{ProjectID: projectID, IID: mrID})
+
+	return nil
+}
+
 // GetUserEvents retrieves user events within the specified time range.
 func (r *CachedRepository) GetUserEvents(
 	ctx context.Context,
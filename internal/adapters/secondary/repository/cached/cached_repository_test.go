@@ -0,0 +1,38 @@
+package cached
+
+import (
+	"context"
+	"testing"
+
+	"github.com/denchenko/gg/internal/adapters/secondary/cache"
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/mocks"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedRepository_ApproveMergeRequest(t *testing.T) {
+	ctx := context.Background()
+	alice := &domain.User{ID: 1, Username: "alice"}
+
+	repo := &mocks.MockRepository{}
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 42).Return(domain.NewApprovals(nil), nil).Once()
+	repo.On("ApproveMergeRequest", mock.Anything, 1, 42).Return(nil).Once()
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 42).
+		Return(domain.NewApprovals([]*domain.User{alice}), nil).Once()
+
+	cached := NewCachedRepository(repo, cache.NewInMemoryCache())
+
+	approvals, err := cached.GetMergeRequestApprovals(ctx, 1, 42)
+	require.NoError(t, err)
+	assert.Empty(t, approvals.ApprovedBy)
+
+	require.NoError(t, cached.ApproveMergeRequest(ctx, 1, 42))
+
+	approvals, err = cached.GetMergeRequestApprovals(ctx, 1, 42)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.User{alice}, approvals.ApprovedBy, "approving drops the cached approvals")
+
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,1263 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// pageLimit is the default maximum page size of a Gitea instance.
+	pageLimit = 50
+	// fetchLimit caps the requests made in parallel for the users or the pull
+	// requests of a listing.
+	fetchLimit = 8
+
+	// checkStatusRunning is the pipeline status of a commit with unfinished checks.
+	checkStatusRunning = "running"
+
+	reviewStateApproved = "APPROVED"
+	reviewStateComment  = "COMMENT"
+	reviewStatePending  = "PENDING"
+)
+
+// Repository implements the app.Repository interface for Gitea and Forgejo.
+// Pull requests map to merge requests, approving reviews to approvals and the
+// activity feed to events.
+type Repository struct {
+	httpClient *http.Client
+	apiURL     string
+	token      string
+
+	mu        sync.Mutex
+	usernames []string
+	repoNames map[int]string
+	logins    map[int]string
+}
+
+// NewRepository creates a new Gitea repository instance.
+func NewRepository(httpClient *http.Client, baseURL, token string) *Repository {
+	return &Repository{
+		httpClient: httpClient,
+		apiURL:     strings.TrimSuffix(baseURL, "/") + "/api/v1",
+		token:      token,
+		repoNames:  make(map[int]string),
+		logins:     make(map[int]string),
+	}
+}
+
+// PreloadUsersByUsernames remembers the usernames so that listing merge
+// requests with the "all" scope covers every team member.
+func (r *Repository) PreloadUsersByUsernames(_ context.Context, usernames []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.usernames = append([]string(nil), usernames...)
+
+	return nil
+}
+
+// FetchUsersByUsernames fetches users by their usernames.
+func (r *Repository) FetchUsersByUsernames(ctx context.Context, usernames []string) ([]*domain.User, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(fetchLimit)
+	users := make([]*domain.User, len(usernames))
+
+	for i, username := range usernames {
+		g.Go(func() error {
+			user, err := r.GetUserByUsername(ctx, username)
+			if err != nil {
+				return err
+			}
+			users[i] = user
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to fetch users: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUserByUsername gets a user by username.
+func (r *Repository) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	var u user
+	if err := r.get(ctx, "/users/"+url.PathEscape(username), nil, &u); err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", username, err)
+	}
+
+	return r.toDomainUser(&u), nil
+}
+
+// ListGroupMembers lists the members of an organization, or of a team when
+// groupPath has the form "org/team".
+func (r *Repository) ListGroupMembers(ctx context.Context, groupPath string) ([]*domain.User, error) {
+	path := "/orgs/" + url.PathEscape(groupPath) + "/members"
+	if org, team, ok := strings.Cut(groupPath, "/"); ok {
+		id, err := r.teamID(ctx, org, team)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members of %s: %w", groupPath, err)
+		}
+		path = fmt.Sprintf("/teams/%d/members", id)
+	}
+
+	members, err := getAll[user](ctx, r, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members of %s: %w", groupPath, err)
+	}
+
+	users := make([]*domain.User, 0, len(members))
+	for i := range members {
+		users = append(users, r.toDomainUser(&members[i]))
+	}
+
+	return users, nil
+}
+
+// teamID looks up a team of an organization by name.
+func (r *Repository) teamID(ctx context.Context, org, name string) (int, error) {
+	var result struct {
+		Data []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+
+	path := "/orgs/" + url.PathEscape(org) + "/teams/search"
+	if err := r.get(ctx, path, url.Values{"q": {name}}, &result); err != nil {
+		return 0, fmt.Errorf("failed to find team: %w", err)
+	}
+
+	for _, team := range result.Data {
+		if strings.EqualFold(team.Name, name) {
+			return team.ID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("team %s not found in %s", name, org)
+}
+
+// GetCurrentUser gets the current authenticated user.
+func (r *Repository) GetCurrentUser(ctx context.Context) (*domain.User, error) {
+	var u user
+	if err := r.get(ctx, "/user", nil, &u); err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	return r.toDomainUser(&u), nil
+}
+
+// GetTokenInfo gets the owner of the token in use. Gitea does not let a token
+// describe itself, so name, scopes and expiry are left empty.
+func (r *Repository) GetTokenInfo(ctx context.Context) (*domain.TokenInfo, error) {
+	user, err := r.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenInfo{User: user}, nil
+}
+
+// GetAllUsers retrieves all users.
+func (r *Repository) GetAllUsers(_ context.Context) ([]*domain.User, error) {
+	return []*domain.User{}, nil
+}
+
+// GetProject retrieves a repository by its owner/name path.
+func (r *Repository) GetProject(ctx context.Context, path string) (*domain.Project, error) {
+	var repo repository
+	if err := r.get(ctx, "/repos/"+path, nil, &repo); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	r.rememberRepository(&repo)
+
+	return &domain.Project{
+		ID:            repo.ID,
+		Path:          repo.FullName,
+		DefaultBranch: repo.DefaultBranch,
+	}, nil
+}
+
+// GetMergeRequest retrieves a pull request by repository ID and index.
+func (r *Repository) GetMergeRequest(ctx context.Context, projectID, mrID int) (*domain.MergeRequest, error) {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	pr, err := r.pullRequest(ctx, repoName, mrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	mr := r.toDomainMR(pr)
+
+	status, failed, err := r.checks(ctx, repoName, pr.Head.SHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request checks: %w", err)
+	}
+	mr.PipelineStatus, mr.FailedJobs, mr.PipelineUnknown = status, failed, false
+
+	return mr, nil
+}
+
+// checks sums up the commit statuses of a commit, which Gitea Actions and
+// external CI report, as a pipeline status along with the names of the failed
+// checks once all have finished. The status is empty when the commit has no
+// statuses.
+func (r *Repository) checks(ctx context.Context, repoName, sha string) (string, []string, error) {
+	statuses, err := getAll[struct {
+		Context string `json:"context"`
+		Status  string `json:"status"`
+	}](ctx, r, fmt.Sprintf("/repos/%s/commits/%s/statuses", repoName, sha), nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(statuses) == 0 {
+		return "", nil, nil
+	}
+
+	// Statuses are listed newest first and a context may be reported several
+	// times, only its latest status counts.
+	seen := make(map[string]struct{})
+	var failed []string
+	for _, status := range statuses {
+		if _, ok := seen[status.Context]; ok {
+			continue
+		}
+		seen[status.Context] = struct{}{}
+
+		switch status.Status {
+		case "pending":
+			return checkStatusRunning, nil, nil
+		case "failure", "error":
+			failed = append(failed, status.Context)
+		}
+	}
+
+	if len(failed) > 0 {
+		return domain.PipelineFailed, failed, nil
+	}
+
+	return domain.PipelineSuccess, nil, nil
+}
+
+// ListMergeRequests lists the pull requests selected by query. The pull
+// requests of a repository are listed directly, others are found with the
+// issue search, which only filters on labels, milestones and owners. The
+// caller filters on the rest.
+//
+// The issue search only filters on the current user, so the "all" scope is
+// resolved to the pull requests involving the current user and, unless the
+// query names an organization, to those opened by or assigned to a preloaded
+// team member in the organizations they belong to.
+func (r *Repository) ListMergeRequests(
+	ctx context.Context,
+	query *domain.MergeRequestQuery,
+) ([]*domain.MergeRequest, error) {
+	state := "all"
+	switch query.State {
+	case domain.StateOpened:
+		state = "open"
+	case domain.StateClosed, domain.StateMerged:
+		state = "closed"
+	}
+
+	var (
+		prs []*pullRequest
+		err error
+	)
+	if query.Project != "" {
+		prs, err = r.repositoryPullRequests(ctx, query, state)
+	} else {
+		prs, err = r.searchPullRequests(ctx, query, state)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge requests: %w", err)
+	}
+
+	mrs := make([]*domain.MergeRequest, 0, len(prs))
+	for _, pr := range prs {
+		// Gitea only knows open and closed; merged is a flavour of closed.
+		if (query.State == domain.StateMerged && !pr.Merged) || (query.State == domain.StateClosed && pr.Merged) {
+			continue
+		}
+		mrs = append(mrs, r.toDomainMR(pr))
+	}
+
+	return mrs, nil
+}
+
+// repositoryPullRequests lists the pull requests of the repository of the
+// query in the given state, keeping those of the current user unless the
+// scope is "all".
+func (r *Repository) repositoryPullRequests(
+	ctx context.Context,
+	query *domain.MergeRequestQuery,
+	state string,
+) ([]*pullRequest, error) {
+	prs, err := getAll[*pullRequest](ctx, r, "/repos/"+query.Project+"/pulls", url.Values{"state": {state}})
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Scope == domain.ScopeAll {
+		return prs, nil
+	}
+
+	current, err := r.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(prs, func(pr *pullRequest) bool {
+		if query.Scope == domain.ScopeAssignedToMe {
+			return pr.Assignee == nil || pr.Assignee.ID != current.ID
+		}
+
+		return pr.User == nil || pr.User.ID != current.ID
+	}), nil
+}
+
+// searchPullRequests finds the pull requests selected by query in the given
+// state with the issue search and fetches them, as the search leaves out
+// their branches and reviewers.
+func (r *Repository) searchPullRequests(
+	ctx context.Context,
+	query *domain.MergeRequestQuery,
+	state string,
+) ([]*pullRequest, error) {
+	params := url.Values{"type": {"pulls"}, "state": {state}}
+	if len(query.Labels) > 0 {
+		params.Set("labels", strings.Join(query.Labels, ","))
+	}
+	if query.Milestone != "" {
+		params.Set("milestones", query.Milestone)
+	}
+	if query.Group != "" {
+		params.Set("owner", query.Group)
+	}
+
+	searches, err := r.searches(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	usernames := r.usernames
+	r.mu.Unlock()
+
+	type ref struct {
+		repo   string
+		number int
+	}
+
+	seen := make(map[ref]struct{})
+	var issues []issue
+
+	for _, search := range searches {
+		batch, err := getAll[issue](ctx, r, "/repos/issues/search", search)
+		if err != nil {
+			return nil, err
+		}
+
+		// Searches of the organizations of the team list every pull request
+		// of the organization, only those of the team are fetched.
+		teamOnly := query.Group == "" && search.Has("owner")
+
+		for _, is := range batch {
+			key := ref{repo: is.Repository.FullName, number: is.Number}
+			if _, ok := seen[key]; ok || (teamOnly && !is.involves(usernames)) {
+				continue
+			}
+			seen[key] = struct{}{}
+			issues = append(issues, is)
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(fetchLimit)
+	prs := make([]*pullRequest, len(issues))
+
+	for i, is := range issues {
+		g.Go(func() error {
+			pr, err := r.pullRequest(gctx, is.Repository.FullName, is.Number)
+			if err != nil {
+				return err
+			}
+			prs[i] = pr
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// searches returns the parameters of the issue searches for a merge request
+// query, params holding its filters.
+func (r *Repository) searches(ctx context.Context, query *domain.MergeRequestQuery, params url.Values) (
+	[]url.Values, error,
+) {
+	with := func(key, value string) url.Values {
+		search := maps.Clone(params)
+		search.Set(key, value)
+
+		return search
+	}
+
+	switch {
+	case query.Scope == domain.ScopeAll && params.Has("owner"):
+		return []url.Values{params}, nil
+	case query.Scope == domain.ScopeAll:
+		searches := []url.Values{with("created", "true"), with("assigned", "true"), with("review_requested", "true")}
+
+		orgs, err := r.teamOrganizations(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, org := range orgs {
+			searches = append(searches, with("owner", org))
+		}
+
+		return searches, nil
+	case query.Scope == domain.ScopeAssignedToMe:
+		return []url.Values{with("assigned", "true")}, nil
+	default:
+		return []url.Values{with("created", "true")}, nil
+	}
+}
+
+// teamOrganizations returns the organizations the preloaded users belong to.
+func (r *Repository) teamOrganizations(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	usernames := r.usernames
+	r.mu.Unlock()
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(fetchLimit)
+	memberships := make([][]string, len(usernames))
+
+	for i, username := range usernames {
+		g.Go(func() error {
+			orgs, err := getAll[struct {
+				Username string `json:"username"`
+			}](gctx, r, "/users/"+url.PathEscape(username)+"/orgs", nil)
+			if err != nil {
+				return err
+			}
+
+			for _, org := range orgs {
+				memberships[i] = append(memberships[i], org.Username)
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to list team organizations: %w", err)
+	}
+
+	var orgs []string
+	for _, names := range memberships {
+		for _, name := range names {
+			if !slices.Contains(orgs, name) {
+				orgs = append(orgs, name)
+			}
+		}
+	}
+
+	return orgs, nil
+}
+
+// GetMergeRequestApprovals returns the users whose latest review approves the
+// pull request. The number of approvals required by branch protection is not
+// readable by every user, so domain.DefaultRequiredApprovals applies.
+func (r *Repository) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) (*domain.Approvals, error) {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request approvals: %w", err)
+	}
+
+	type review struct {
+		User      user   `json:"user"`
+		State     string `json:"state"`
+		Stale     bool   `json:"stale"`
+		Dismissed bool   `json:"dismissed"`
+	}
+
+	reviews, err := getAll[review](ctx, r, fmt.Sprintf("/repos/%s/pulls/%d/reviews", repoName, mrID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request approvals: %w", err)
+	}
+
+	// Reviews are returned oldest first; a later verdict replaces an earlier
+	// approval from the same user.
+	latest := make(map[int]string)
+	order := make([]*user, 0, len(reviews))
+	for i := range reviews {
+		review := &reviews[i]
+		if review.State == reviewStateComment || review.State == reviewStatePending {
+			continue
+		}
+		if _, ok := latest[review.User.ID]; !ok {
+			order = append(order, &review.User)
+		}
+		latest[review.User.ID] = review.State
+		if review.Dismissed {
+			latest[review.User.ID] = ""
+		}
+	}
+
+	approvals := make([]*domain.User, 0, len(order))
+	for _, u := range order {
+		if latest[u.ID] == reviewStateApproved {
+			approvals = append(approvals, r.toDomainUser(u))
+		}
+	}
+
+	return domain.NewApprovals(approvals), nil
+}
+
+// GetMergeRequestApprovalRules returns no rules, Gitea has no approval rules.
+func (r *Repository) GetMergeRequestApprovalRules(context.Context, int, int) ([]*domain.ApprovalRule, error) {
+	return nil, nil
+}
+
+// ListMergeRequestDiscussions retrieves the conversation comments of a pull
+// request, one discussion each. Review comments are left out as the API does
+// not group them into threads.
+func (r *Repository) ListMergeRequestDiscussions(
+	ctx context.Context,
+	projectID, mrID int,
+) ([]*domain.Discussion, error) {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge request discussions: %w", err)
+	}
+
+	type comment struct {
+		ID        int       `json:"id"`
+		User      user      `json:"user"`
+		Body      string    `json:"body"`
+		CreatedAt time.Time `json:"created_at"`
+		HTMLURL   string    `json:"html_url"`
+	}
+
+	comments, err := getAll[comment](ctx, r, fmt.Sprintf("/repos/%s/issues/%d/comments", repoName, mrID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge request discussions: %w", err)
+	}
+
+	discussions := make([]*domain.Discussion, 0, len(comments))
+	for i := range comments {
+		c := &comments[i]
+		discussions = append(discussions, &domain.Discussion{
+			ID: strconv.Itoa(c.ID),
+			Notes: []*domain.Note{{
+				ID:        c.ID,
+				Author:    r.toDomainUser(&c.User),
+				Body:      c.Body,
+				CreatedAt: c.CreatedAt,
+				WebURL:    c.HTMLURL,
+			}},
+		})
+	}
+
+	return discussions, nil
+}
+
+// ListCommits lists commits on the default branch of a repository.
+func (r *Repository) ListCommits(ctx context.Context, projectID int) ([]*domain.Commit, error) {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	var commits []struct {
+		SHA     string `json:"sha"`
+		HTMLURL string `json:"html_url"`
+		Commit  struct {
+			Message string `json:"message"`
+			Author  struct {
+				Name  string    `json:"name"`
+				Email string    `json:"email"`
+				Date  time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+	}
+
+	params := url.Values{"limit": {strconv.Itoa(pageLimit)}, "stat": {"false"}}
+	if err := r.get(ctx, "/repos/"+repoName+"/commits", params, &commits); err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	result := make([]*domain.Commit, 0, len(commits))
+	for _, c := range commits {
+		result = append(result, &domain.Commit{
+			ID:          c.SHA,
+			AuthorName:  c.Commit.Author.Name,
+			AuthorEmail: c.Commit.Author.Email,
+			CreatedAt:   c.Commit.Author.Date,
+			Message:     c.Commit.Message,
+			WebURL:      c.HTMLURL,
+		})
+	}
+
+	return result, nil
+}
+
+// UpdateMergeRequest sets the assignee and requests reviews on a pull request.
+func (r *Repository) UpdateMergeRequest(
+	ctx context.Context,
+	projectID, mrID int,
+	assigneeID *int,
+	reviewerIDs []int,
+) error {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to update merge request: %w", err)
+	}
+
+	if assigneeID != nil {
+		login, err := r.login(ctx, *assigneeID)
+		if err != nil {
+			return fmt.Errorf("failed to update merge request: %w", err)
+		}
+
+		body := map[string]string{"assignee": login}
+		if err := r.send(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/pulls/%d", repoName, mrID), body, nil); err != nil {
+			return fmt.Errorf("failed to update merge request: %w", err)
+		}
+	}
+
+	if len(reviewerIDs) > 0 {
+		logins := make([]string, 0, len(reviewerIDs))
+		for _, id := range reviewerIDs {
+			login, err := r.login(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to update merge request: %w", err)
+			}
+			logins = append(logins, login)
+		}
+
+		body := map[string][]string{"reviewers": logins}
+		path := fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", repoName, mrID)
+		if err := r.send(ctx, http.MethodPost, path, body, nil); err != nil {
+			return fmt.Errorf("failed to update merge request: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CreateMergeRequest opens a pull request. Drafts are marked with the "WIP:"
+// title prefix. Labels, the assignee and the reviewers are set once the pull
+// request exists.
+func (r *Repository) CreateMergeRequest(
+	ctx context.Context,
+	projectID int,
+	opts *domain.NewMergeRequest,
+) (*domain.MergeRequest, error) {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+
+	title := opts.Title
+	if opts.Draft {
+		title = "WIP: " + title
+	}
+
+	body := map[string]any{
+		"title": title,
+		"body":  opts.Description,
+		"head":  opts.SourceBranch,
+		"base":  opts.TargetBranch,
+	}
+
+	var pr pullRequest
+	if err := r.send(ctx, http.MethodPost, "/repos/"+repoName+"/pulls", body, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+
+	if len(opts.Labels) > 0 {
+		labels := map[string][]string{"labels": opts.Labels}
+		path := fmt.Sprintf("/repos/%s/issues/%d/labels", repoName, pr.Number)
+		if err := r.send(ctx, http.MethodPost, path, labels, nil); err != nil {
+			return nil, fmt.Errorf("failed to label merge request: %w", err)
+		}
+	}
+
+	if err := r.UpdateMergeRequest(ctx, projectID, pr.Number, opts.AssigneeID, opts.ReviewerIDs); err != nil {
+		return nil, err
+	}
+
+	return r.toDomainMR(&pr), nil
+}
+
+// ApproveMergeRequest submits an approving review on a pull request.
+func (r *Repository) ApproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to approve merge request: %w", err)
+	}
+
+	body := map[string]string{"event": "APPROVED"}
+	path := fmt.Sprintf("/repos/%s/pulls/%d/reviews", repoName, mrID)
+	if err := r.send(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("failed to approve merge request: %w", err)
+	}
+
+	return nil
+}
+
+// UnapproveMergeRequest dismisses the latest approving review of the current
+// user on a pull request.
+func (r *Repository) UnapproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to unapprove merge request: %w", err)
+	}
+
+	current, err := r.GetCurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to unapprove merge request: %w", err)
+	}
+
+	type review struct {
+		ID    int    `json:"id"`
+		User  user   `json:"user"`
+		State string `json:"state"`
+	}
+
+	reviews, err := getAll[review](ctx, r, fmt.Sprintf("/repos/%s/pulls/%d/reviews", repoName, mrID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to unapprove merge request: %w", err)
+	}
+
+	for i := len(reviews) - 1; i >= 0; i-- {
+		if reviews[i].User.ID != current.ID || reviews[i].State != reviewStateApproved {
+			continue
+		}
+
+		body := map[string]string{"message": "Approval withdrawn"}
+		path := fmt.Sprintf("/repos/%s/pulls/%d/reviews/%d/dismissals", repoName, mrID, reviews[i].ID)
+		if err := r.send(ctx, http.MethodPost, path, body, nil); err != nil {
+			return fmt.Errorf("failed to unapprove merge request: %w", err)
+		}
+
+		return nil
+	}
+
+	return errors.New("failed to unapprove merge request: it is not approved by you")
+}
+
+// MergeMergeRequest merges a pull request, or sets it to merge when its
+// checks succeed.
+func (r *Repository) MergeMergeRequest(ctx context.Context, projectID, mrID int, opts *domain.MergeOptions) error {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to merge merge request: %w", err)
+	}
+
+	method := "merge"
+	if opts.Squash {
+		method = "squash"
+	}
+
+	body := map[string]any{
+		"Do":                        method,
+		"delete_branch_after_merge": opts.RemoveSourceBranch,
+		"merge_when_checks_succeed": opts.WhenPipelineSucceeds,
+	}
+	if err := r.send(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls/%d/merge", repoName, mrID), body, nil); err != nil {
+		return fmt.Errorf("failed to merge merge request: %w", err)
+	}
+
+	return nil
+}
+
+// RebaseMergeRequest rebases the head branch of a pull request onto its base
+// branch. Gitea rebases synchronously, so the rebase is done on return.
+func (r *Repository) RebaseMergeRequest(ctx context.Context, projectID, mrID int) error {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to rebase merge request: %w", err)
+	}
+
+	path := fmt.Sprintf("/repos/%s/pulls/%d/update?style=rebase", repoName, mrID)
+	if err := r.send(ctx, http.MethodPost, path, nil, nil); err != nil {
+		return fmt.Errorf("failed to rebase merge request: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserEvents retrieves user events within the specified time range from
+// the user's activity feed.
+func (r *Repository) GetUserEvents(
+	ctx context.Context,
+	userID int,
+	after time.Time,
+	before *time.Time,
+) ([]*domain.Event, error) {
+	login, err := r.login(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user events: %w", err)
+	}
+
+	params := url.Values{"only-performed-by": {"true"}, "limit": {strconv.Itoa(pageLimit)}}
+	path := "/users/" + url.PathEscape(login) + "/activities/feeds"
+
+	var result []*domain.Event
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var batch []*activity
+		if err := r.get(ctx, path, params, &batch); err != nil {
+			return nil, fmt.Errorf("failed to get user events: %w", err)
+		}
+
+		// The feed is ordered newest first, so stop once it goes past the range.
+		for _, a := range batch {
+			if a.Created.Before(after) {
+				return result, nil
+			}
+			if before != nil && a.Created.After(*before) {
+				continue
+			}
+			if event := a.toDomain(); event != nil {
+				result = append(result, event)
+			}
+		}
+
+		if len(batch) < pageLimit {
+			return result, nil
+		}
+	}
+}
+
+func (r *Repository) pullRequest(ctx context.Context, repoName string, number int) (*pullRequest, error) {
+	var pr pullRequest
+	if err := r.get(ctx, fmt.Sprintf("/repos/%s/pulls/%d", repoName, number), nil, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+func (r *Repository) repositoryName(ctx context.Context, projectID int) (string, error) {
+	r.mu.Lock()
+	name, ok := r.repoNames[projectID]
+	r.mu.Unlock()
+
+	if ok {
+		return name, nil
+	}
+
+	var repo repository
+	if err := r.get(ctx, fmt.Sprintf("/repositories/%d", projectID), nil, &repo); err != nil {
+		return "", fmt.Errorf("failed to get repository %d: %w", projectID, err)
+	}
+
+	r.rememberRepository(&repo)
+
+	return repo.FullName, nil
+}
+
+func (r *Repository) rememberRepository(repo *repository) {
+	if repo.ID == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	r.repoNames[repo.ID] = repo.FullName
+	r.mu.Unlock()
+}
+
+// login resolves a user ID to a login, which most endpoints expect.
+func (r *Repository) login(ctx context.Context, userID int) (string, error) {
+	r.mu.Lock()
+	login, ok := r.logins[userID]
+	r.mu.Unlock()
+
+	if ok {
+		return login, nil
+	}
+
+	var result struct {
+		Data []user `json:"data"`
+	}
+	if err := r.get(ctx, "/users/search", url.Values{"uid": {strconv.Itoa(userID)}}, &result); err != nil {
+		return "", fmt.Errorf("failed to get user %d: %w", userID, err)
+	}
+
+	if len(result.Data) == 0 {
+		return "", fmt.Errorf("user %d not found", userID)
+	}
+
+	return r.toDomainUser(&result.Data[0]).Username, nil
+}
+
+// getAll reads every page of a listing, until a page comes back short.
+func getAll[T any](ctx context.Context, r *Repository, path string, params url.Values) ([]T, error) {
+	params = maps.Clone(params)
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("limit", strconv.Itoa(pageLimit))
+
+	var items []T
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var batch []T
+		if err := r.get(ctx, path, params, &batch); err != nil {
+			return nil, err
+		}
+
+		items = append(items, batch...)
+		if len(batch) < pageLimit {
+			return items, nil
+		}
+	}
+}
+
+func (r *Repository) get(ctx context.Context, path string, params url.Values, out any) error {
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	return r.send(ctx, http.MethodGet, path, nil, out)
+}
+
+func (r *Repository) send(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.apiURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "token "+r.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+
+		return fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, apiErr.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+type user struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+	Email string `json:"email"`
+}
+
+// toDomainUser converts a Gitea user and remembers its login.
+func (r *Repository) toDomainUser(u *user) *domain.User {
+	r.mu.Lock()
+	r.logins[u.ID] = u.Login
+	r.mu.Unlock()
+
+	return &domain.User{
+		ID:       u.ID,
+		Username: u.Login,
+		Email:    u.Email,
+	}
+}
+
+type repository struct {
+	ID            int    `json:"id"`
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type issue struct {
+	Number     int        `json:"number"`
+	Repository repository `json:"repository"`
+	User       *user      `json:"user"`
+	Assignees  []*user    `json:"assignees"`
+}
+
+// involves reports whether the issue was opened by or is assigned to one of
+// the users.
+func (is *issue) involves(usernames []string) bool {
+	for _, u := range append([]*user{is.User}, is.Assignees...) {
+		if u != nil && slices.ContainsFunc(usernames, func(username string) bool {
+			return strings.EqualFold(username, u.Login)
+		}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type pullRequest struct {
+	ID                 int        `json:"id"`
+	Number             int        `json:"number"`
+	Title              string     `json:"title"`
+	Body               string     `json:"body"`
+	HTMLURL            string     `json:"html_url"`
+	User               *user      `json:"user"`
+	Assignee           *user      `json:"assignee"`
+	RequestedReviewers []*user    `json:"requested_reviewers"`
+	Created            time.Time  `json:"created_at"`
+	Updated            time.Time  `json:"updated_at"`
+	Draft              bool       `json:"draft"`
+	State              string     `json:"state"`
+	Merged             bool       `json:"merged"`
+	Mergeable          bool       `json:"mergeable"`
+	Comments           int        `json:"comments"`
+	ChangedFiles       int        `json:"changed_files"`
+	Milestone          *milestone `json:"milestone"`
+	Head               struct {
+		Ref    string `json:"ref"`
+		SHA    string `json:"sha"`
+		RepoID int    `json:"repo_id"`
+	} `json:"head"`
+	Base struct {
+		Ref  string     `json:"ref"`
+		Repo repository `json:"repo"`
+	} `json:"base"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+type milestone struct {
+	Title string `json:"title"`
+}
+
+// pullRequestState maps the "open" and "closed" states of pull requests to
+// the domain states, telling merged pull requests apart from closed ones.
+func pullRequestState(state string, merged bool) string {
+	switch {
+	case merged:
+		return domain.StateMerged
+	case state == "open":
+		return domain.StateOpened
+	default:
+		return domain.StateClosed
+	}
+}
+
+func (r *Repository) toDomainMR(pr *pullRequest) *domain.MergeRequest {
+	r.rememberRepository(&pr.Base.Repo)
+
+	mr := &domain.MergeRequest{
+		ID:           pr.ID,
+		IID:          pr.Number,
+		Title:        pr.Title,
+		Description:  pr.Body,
+		WebURL:       pr.HTMLURL,
+		Reviewers:    make([]*domain.User, 0, len(pr.RequestedReviewers)),
+		CreatedAt:    pr.Created,
+		UpdatedAt:    pr.Updated,
+		ProjectID:    pr.Base.Repo.ID,
+		Draft:        pr.Draft,
+		SourceBranch: pr.Head.Ref,
+		TargetBranch: pr.Base.Ref,
+		State:        pullRequestState(pr.State, pr.Merged),
+		HeadRef:      fmt.Sprintf("refs/pull/%d/head", pr.Number),
+		Fork:         pr.Head.RepoID != pr.Base.Repo.ID,
+		CommentCount: pr.Comments,
+		ChangedFiles: pr.ChangedFiles,
+		// Only GetMergeRequest looks up the statuses of the head commit.
+		PipelineUnknown: true,
+	}
+
+	// Only open pull requests are checked for conflicts.
+	if mr.State == domain.StateOpened {
+		mr.DetailedMergeStatus = domain.MergeStatusMergeable
+		if !pr.Mergeable {
+			mr.DetailedMergeStatus, mr.HasConflicts = domain.MergeStatusConflict, true
+		}
+	}
+
+	for _, label := range pr.Labels {
+		mr.Labels = append(mr.Labels, label.Name)
+	}
+
+	if pr.Milestone != nil {
+		mr.Milestone = pr.Milestone.Title
+	}
+
+	if pr.User != nil {
+		mr.Author = r.toDomainUser(pr.User)
+	}
+
+	if pr.Assignee != nil {
+		mr.Assignee = r.toDomainUser(pr.Assignee)
+	}
+
+	for _, reviewer := range pr.RequestedReviewers {
+		mr.Reviewers = append(mr.Reviewers, r.toDomainUser(reviewer))
+	}
+
+	return mr
+}
+
+type activity struct {
+	ID      int        `json:"id"`
+	OpType  string     `json:"op_type"`
+	Repo    repository `json:"repo"`
+	RefName string     `json:"ref_name"`
+	Content string     `json:"content"`
+	Created time.Time  `json:"created"`
+	Comment *struct {
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+	} `json:"comment"`
+}
+
+// toDomain maps a Gitea activity onto the GitLab-shaped domain event so the
+// activity view renders it the same way. Unsupported operations yield nil.
+func (a *activity) toDomain() *domain.Event {
+	event := &domain.Event{
+		ID:          a.ID,
+		ProjectID:   a.Repo.ID,
+		ProjectPath: a.Repo.FullName,
+		CreatedAt:   a.Created,
+	}
+
+	switch a.OpType {
+	case "commit_repo", "push_tag":
+		event.Action = "pushed to"
+		event.PushAction = event.Action
+		event.PushRef = qualifyRef(a.RefName, a.OpType == "push_tag")
+
+		// Content holds the pushed commits, newest first.
+		var push struct {
+			Len     int `json:"Len"`
+			Commits []struct {
+				Message string `json:"Message"`
+			} `json:"Commits"`
+		}
+		if json.Unmarshal([]byte(a.Content), &push) == nil {
+			event.CommitCount = push.Len
+			if len(push.Commits) > 0 {
+				event.CommitTitle, _, _ = strings.Cut(push.Commits[0].Message, "\n")
+			}
+		}
+	case "delete_branch", "delete_tag":
+		event.Action = "deleted"
+		event.PushRef = qualifyRef(a.RefName, a.OpType == "delete_tag")
+	case "create_pull_request", "merge_pull_request", "auto_merge_pull_request",
+		"close_pull_request", "reopen_pull_request", "approve_pull_request":
+		event.Action = pullRequestActions[a.OpType]
+		event.TargetType = "MergeRequest"
+		event.TargetID, event.TargetTitle = splitContent(a.Content)
+	case "comment_pull", "reject_pull_request", "comment_issue":
+		event.Action = "commented on"
+		event.TargetType = "Note"
+		event.NoteableType = "MergeRequest"
+		if a.OpType == "comment_issue" {
+			event.NoteableType = "Issue"
+		}
+		_, event.TargetTitle = splitContent(a.Content)
+		if a.Comment != nil {
+			event.NoteBody = a.Comment.Body
+			event.WebURL = a.Comment.HTMLURL
+		}
+	case "create_issue", "close_issue", "reopen_issue":
+		event.Action = strings.TrimSuffix(a.OpType, "_issue") + "d"
+		event.TargetType = "Issue"
+		event.TargetID, event.TargetTitle = splitContent(a.Content)
+	default:
+		return nil
+	}
+
+	return event
+}
+
+//nolint:gochecknoglobals // read-only lookup table
+var pullRequestActions = map[string]string{
+	"create_pull_request":     "opened",
+	"merge_pull_request":      "accepted",
+	"auto_merge_pull_request": "accepted",
+	"close_pull_request":      "closed",
+	"reopen_pull_request":     "reopened",
+	"approve_pull_request":    "approved",
+}
+
+// splitContent splits the "index|title" content of pull request and issue activities.
+func splitContent(content string) (int, string) {
+	index, title, _ := strings.Cut(content, "|")
+	number, _ := strconv.Atoi(index)
+
+	return number, title
+}
+
+// qualifyRef turns a short ref name, as stored by older Gitea versions, into a full ref.
+func qualifyRef(ref string, tag bool) string {
+	switch {
+	case strings.HasPrefix(ref, "refs/"):
+		return ref
+	case tag:
+		return "refs/tags/" + ref
+	default:
+		return "refs/heads/" + ref
+	}
+}
@@ -0,0 +1,410 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pullRequestBody = `{
+	"id": 2001, "number": %[1]d, "title": "PR %[1]d", "body": "",
+	"html_url": "https://forgejo.example.com/owner/repo/pulls/%[1]d",
+	"user": {"id": 9, "login": "author"},
+	"assignee": null,
+	"requested_reviewers": [{"id": 2, "login": "bob"}],
+	"created_at": "2025-01-01T10:00:00Z", "updated_at": "2025-01-02T10:00:00Z",
+	"draft": false, "merged": %[2]t, "head": {"ref": "feature"},
+	"base": {"repo": {"id": 42, "full_name": "owner/repo"}}
+}`
+
+func newTestRepository(t *testing.T, mux *http.ServeMux) *Repository {
+	t.Helper()
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return NewRepository(server.Client(), server.URL, "token")
+}
+
+func TestRepository_ListMergeRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       *domain.MergeRequestQuery
+		expectQuery map[string]string
+		expectIIDs  []int
+	}{
+		{
+			name:        "authored",
+			query:       &domain.MergeRequestQuery{State: domain.StateOpened},
+			expectQuery: map[string]string{"state": "open", "created": "true"},
+			expectIIDs:  []int{1, 2},
+		},
+		{
+			name:        "assigned",
+			query:       &domain.MergeRequestQuery{State: domain.StateOpened, Scope: domain.ScopeAssignedToMe},
+			expectQuery: map[string]string{"state": "open", "assigned": "true"},
+			expectIIDs:  []int{1, 2},
+		},
+		{
+			name:        "all merged",
+			query:       &domain.MergeRequestQuery{State: domain.StateMerged, Scope: domain.ScopeAll},
+			expectQuery: map[string]string{"state": "closed"},
+			expectIIDs:  []int{2},
+		},
+		{
+			name: "filtered",
+			query: &domain.MergeRequestQuery{
+				State:     domain.StateOpened,
+				Scope:     domain.ScopeAll,
+				Group:     "owner",
+				Labels:    []string{"bug", "backend"},
+				Milestone: "v1",
+			},
+			expectQuery: map[string]string{"owner": "owner", "labels": "bug,backend", "milestones": "v1"},
+			expectIIDs:  []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v1/repos/issues/search", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "token token", r.Header.Get("Authorization"))
+				assert.Equal(t, "pulls", r.URL.Query().Get("type"))
+				for key, value := range tt.expectQuery {
+					assert.Equal(t, value, r.URL.Query().Get(key), key)
+				}
+				_, _ = w.Write([]byte(`[
+					{"number": 1, "repository": {"id": 42, "full_name": "owner/repo"}},
+					{"number": 2, "repository": {"id": 42, "full_name": "owner/repo"}}
+				]`))
+			})
+			mux.HandleFunc("/api/v1/repos/owner/repo/pulls/1", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = fmt.Fprintf(w, pullRequestBody, 1, false)
+			})
+			mux.HandleFunc("/api/v1/repos/owner/repo/pulls/2", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = fmt.Fprintf(w, pullRequestBody, 2, true)
+			})
+
+			repo := newTestRepository(t, mux)
+
+			mrs, err := repo.ListMergeRequests(context.Background(), tt.query)
+			require.NoError(t, err)
+
+			iids := make([]int, 0, len(mrs))
+			for _, mr := range mrs {
+				iids = append(iids, mr.IID)
+				assert.Equal(t, 42, mr.ProjectID)
+				assert.Equal(t, "author", mr.Author.Username)
+				assert.Nil(t, mr.Assignee)
+				require.Len(t, mr.Reviewers, 1)
+			}
+			assert.Equal(t, tt.expectIIDs, iids)
+		})
+	}
+}
+
+func TestRepository_ListMergeRequests_Project(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/user", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 9, "login": "author"}`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		_, _ = fmt.Fprintf(w, `[`+pullRequestBody+`, `+strings.Replace(pullRequestBody, `"id": 9`, `"id": 8`, 1)+`]`,
+			1, false)
+	})
+	mux.HandleFunc("/api/v1/repos/issues/search", func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("the pull requests of a repository must be listed without searching")
+	})
+
+	repo := newTestRepository(t, mux)
+	ctx := context.Background()
+
+	mrs, err := repo.ListMergeRequests(ctx, &domain.MergeRequestQuery{
+		State: domain.StateOpened, Scope: domain.ScopeAll, Project: "owner/repo",
+	})
+	require.NoError(t, err)
+	assert.Len(t, mrs, 2)
+
+	mrs, err = repo.ListMergeRequests(ctx, &domain.MergeRequestQuery{State: domain.StateOpened, Project: "owner/repo"})
+	require.NoError(t, err)
+	require.Len(t, mrs, 1, "only the pull requests of the current user are kept")
+	assert.Equal(t, 9, mrs[0].Author.ID)
+}
+
+func TestRepository_ListMergeRequests_All(t *testing.T) {
+	var searches []url.Values
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/users/bob/orgs", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"username": "team-org"}]`))
+	})
+	mux.HandleFunc("/api/v1/repos/issues/search", func(w http.ResponseWriter, r *http.Request) {
+		searches = append(searches, r.URL.Query())
+
+		// Every search finds pull request 1, only the organization one finds 2
+		// and 3, of which only 2 involves the team.
+		issues := `{"number": 1, "repository": {"id": 42, "full_name": "owner/repo"}}`
+		if r.URL.Query().Get("owner") == "team-org" {
+			issues += `, {"number": 2, "repository": {"id": 42, "full_name": "owner/repo"},
+				"user": {"login": "carol"}, "assignees": [{"login": "Bob"}]}`
+			issues += `, {"number": 3, "repository": {"id": 42, "full_name": "owner/repo"},
+				"user": {"login": "carol"}}`
+		}
+		_, _ = w.Write([]byte(`[` + issues + `]`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/", func(w http.ResponseWriter, r *http.Request) {
+		number := map[string]int{"1": 1, "2": 2}[path.Base(r.URL.Path)]
+		assert.NotZero(t, number, "pull requests outside the team must not be fetched")
+		_, _ = fmt.Fprintf(w, pullRequestBody, number, false)
+	})
+
+	repo := newTestRepository(t, mux)
+
+	ctx := context.Background()
+	require.NoError(t, repo.PreloadUsersByUsernames(ctx, []string{"bob"}))
+
+	mrs, err := repo.ListMergeRequests(ctx, &domain.MergeRequestQuery{State: domain.StateOpened, Scope: domain.ScopeAll})
+	require.NoError(t, err)
+	require.Len(t, mrs, 2, "pull requests found by several searches are listed once")
+
+	require.Len(t, searches, 4)
+	assert.Equal(t, "true", searches[0].Get("created"))
+	assert.Equal(t, "true", searches[1].Get("assigned"))
+	assert.Equal(t, "true", searches[2].Get("review_requested"))
+	assert.Equal(t, "team-org", searches[3].Get("owner"), "team members are covered through their organizations")
+}
+
+func TestRepository_GetMergeRequestApprovals(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/7/reviews", func(w http.ResponseWriter, r *http.Request) {
+		// A full first page is followed by a second one.
+		if r.URL.Query().Get("page") == "1" {
+			comments := strings.Repeat(`, {"user": {"id": 4, "login": "dave"}, "state": "COMMENT"}`, pageLimit-2)
+			_, _ = w.Write([]byte(`[
+				{"user": {"id": 1, "login": "alice"}, "state": "APPROVED"},
+				{"user": {"id": 2, "login": "bob"}, "state": "APPROVED"}` + comments + `]`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`[
+			{"user": {"id": 2, "login": "bob"}, "state": "REQUEST_CHANGES"},
+			{"user": {"id": 3, "login": "carol"}, "state": "APPROVED", "dismissed": true},
+			{"user": {"id": 1, "login": "alice"}, "state": "COMMENT"}
+		]`))
+	})
+
+	repo := newTestRepository(t, mux)
+
+	approvals, err := repo.GetMergeRequestApprovals(context.Background(), 42, 7)
+	require.NoError(t, err)
+	require.Len(t, approvals.ApprovedBy, 1)
+	assert.Equal(t, "alice", approvals.ApprovedBy[0].Username, "reviews of every page count")
+	assert.Equal(t, 1, approvals.Left, "the default number of approvals applies")
+}
+
+func TestRepository_UpdateMergeRequest(t *testing.T) {
+	var assignee map[string]string
+	var reviewers map[string][]string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/api/v1/users/search", func(w http.ResponseWriter, r *http.Request) {
+		logins := map[string]string{"1": "alice", "2": "bob"}
+		uid := r.URL.Query().Get("uid")
+		_, _ = fmt.Fprintf(w, `{"ok": true, "data": [{"id": %s, "login": %q}]}`, uid, logins[uid])
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&assignee))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/7/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&reviewers))
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	repo := newTestRepository(t, mux)
+	assigneeID := 1
+
+	require.NoError(t, repo.UpdateMergeRequest(context.Background(), 42, 7, &assigneeID, []int{2}))
+	assert.Equal(t, map[string]string{"assignee": "alice"}, assignee)
+	assert.Equal(t, map[string][]string{"reviewers": {"bob"}}, reviewers)
+}
+
+func TestRepository_ApproveMergeRequest(t *testing.T) {
+	var review map[string]string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/7/reviews", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	require.NoError(t, newTestRepository(t, mux).ApproveMergeRequest(context.Background(), 42, 7))
+	assert.Equal(t, map[string]string{"event": "APPROVED"}, review)
+}
+
+func TestRepository_GetMergeRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   string
+		status     string
+		failedJobs []string
+	}{
+		{name: "no statuses", statuses: `[]`},
+		{
+			name:     "pending status",
+			statuses: `[{"context": "ci/test", "status": "pending"}, {"context": "ci/lint", "status": "failure"}]`,
+			status:   "running",
+		},
+		{
+			name: "failed status",
+			statuses: `[{"context": "ci/lint", "status": "failure"}, {"context": "ci/test", "status": "success"},
+				{"context": "ci/test", "status": "pending"}]`,
+			status:     domain.PipelineFailed,
+			failedJobs: []string{"ci/lint"},
+		},
+		{
+			name:     "passed statuses",
+			statuses: `[{"context": "ci/test", "status": "success"}, {"context": "ci/test", "status": "failure"}]`,
+			status:   domain.PipelineSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v1/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+			})
+			mux.HandleFunc("/api/v1/repos/owner/repo/pulls/7", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"number": 7, "state": "open", "mergeable": true,
+					"head": {"ref": "feature", "sha": "abc"},
+					"base": {"ref": "main", "repo": {"id": 42, "full_name": "owner/repo"}}}`))
+			})
+			mux.HandleFunc("/api/v1/repos/owner/repo/commits/abc/statuses", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(tt.statuses))
+			})
+
+			mr, err := newTestRepository(t, mux).GetMergeRequest(context.Background(), 42, 7)
+			require.NoError(t, err)
+			assert.False(t, mr.PipelineUnknown)
+			assert.Equal(t, tt.status, mr.PipelineStatus)
+			assert.Equal(t, tt.failedJobs, mr.FailedJobs)
+		})
+	}
+}
+
+func TestRepository_MergeMergeRequest(t *testing.T) {
+	var merge map[string]any
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/7/merge", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&merge))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	opts := &domain.MergeOptions{WhenPipelineSucceeds: true, Squash: true, RemoveSourceBranch: true}
+	require.NoError(t, newTestRepository(t, mux).MergeMergeRequest(context.Background(), 42, 7, opts))
+	assert.Equal(t, map[string]any{
+		"Do": "squash", "delete_branch_after_merge": true, "merge_when_checks_succeed": true,
+	}, merge)
+}
+
+func TestRepository_RebaseMergeRequest(t *testing.T) {
+	var style string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/7/update", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		style = r.URL.Query().Get("style")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	require.NoError(t, newTestRepository(t, mux).RebaseMergeRequest(context.Background(), 42, 7))
+	assert.Equal(t, "rebase", style)
+}
+
+func TestRepository_GetUserEvents(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/users/search", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "data": [{"id": 1, "login": "alice"}]}`))
+	})
+	mux.HandleFunc("/api/v1/users/alice/activities/feeds", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("only-performed-by"))
+		_, _ = w.Write([]byte(`[
+			{"id": 3, "op_type": "commit_repo", "ref_name": "refs/heads/main",
+			 "repo": {"id": 42, "full_name": "owner/repo"}, "created": "2025-01-03T10:00:00Z",
+			 "content": "{\"Len\": 2, \"Commits\": [{\"Message\": \"Fix bug\\n\\nDetails\"}]}"},
+			{"id": 2, "op_type": "merge_pull_request", "content": "7|Add feature",
+			 "repo": {"id": 42, "full_name": "owner/repo"}, "created": "2025-01-02T10:00:00Z"},
+			{"id": 1, "op_type": "star_repo",
+			 "repo": {"id": 42, "full_name": "owner/repo"}, "created": "2025-01-02T09:00:00Z"},
+			{"id": 0, "op_type": "create_issue", "content": "1|Old",
+			 "repo": {"id": 42, "full_name": "owner/repo"}, "created": "2024-12-01T10:00:00Z"}
+		]`))
+	})
+
+	repo := newTestRepository(t, mux)
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	events, err := repo.GetUserEvents(context.Background(), 1, after, nil)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "pushed to", events[0].Action)
+	assert.Equal(t, "refs/heads/main", events[0].PushRef)
+	assert.Equal(t, 2, events[0].CommitCount)
+	assert.Equal(t, "Fix bug", events[0].CommitTitle)
+	assert.Equal(t, "owner/repo", events[0].ProjectPath)
+
+	assert.Equal(t, "accepted", events[1].Action)
+	assert.Equal(t, "MergeRequest", events[1].TargetType)
+	assert.Equal(t, 7, events[1].TargetID)
+	assert.Equal(t, "Add feature", events[1].TargetTitle)
+}
+
+func TestRepository_APIError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/user", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message": "token is required"}`))
+	})
+
+	repo := newTestRepository(t, mux)
+
+	_, err := repo.GetCurrentUser(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "token is required")
+}
@@ -0,0 +1,1280 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	perPageLimit = 100
+	// fetchLimit caps the requests made in parallel for the pull requests of a listing.
+	fetchLimit = 8
+	// searchResultsLimit is the number of results GitHub returns at most for a search.
+	searchResultsLimit = 1000
+	// searchTermsPerQuery is the number of user qualifiers ORed in a search
+	// query, which takes at most five operators.
+	searchTermsPerQuery = 6
+
+	publicWebURL = "https://github.com"
+	publicAPIURL = "https://api.github.com"
+
+	// checkStatusRunning is the pipeline status of a commit with unfinished checks.
+	checkStatusRunning = "running"
+
+	reviewStateApproved = "APPROVED"
+	reviewStateComment  = "COMMENTED"
+
+	tokenExpirationLayout = "2006-01-02 15:04:05 MST"
+)
+
+// ErrAutoMergeUnsupported is returned when asked to merge a pull request once
+// its checks succeed.
+var ErrAutoMergeUnsupported = errors.New("merging when the pipeline succeeds is not supported for GitHub")
+
+// ErrRebaseUnsupported is returned when asked to rebase a pull request, which
+// the GitHub API can only update by merging the base branch.
+var ErrRebaseUnsupported = errors.New("rebasing is not supported for GitHub")
+
+// Repository implements the app.Repository interface for GitHub. Pull requests
+// map to merge requests, approving reviews to approvals and requested
+// reviewers to reviewers.
+type Repository struct {
+	httpClient *http.Client
+	apiURL     string
+	token      string
+
+	mu        sync.Mutex
+	usernames []string
+	repoNames map[int]string
+	logins    map[int]string
+}
+
+// NewRepository creates a new GitHub repository instance. baseURL is the web
+// URL of the instance, e.g. https://github.com or a GitHub Enterprise host.
+func NewRepository(httpClient *http.Client, baseURL, token string) *Repository {
+	return &Repository{
+		httpClient: httpClient,
+		apiURL:     apiURL(baseURL),
+		token:      token,
+		repoNames:  make(map[int]string),
+		logins:     make(map[int]string),
+	}
+}
+
+// apiURL maps a web URL to the REST API root.
+func apiURL(baseURL string) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if baseURL == publicWebURL {
+		return publicAPIURL
+	}
+
+	return baseURL + "/api/v3"
+}
+
+// PreloadUsersByUsernames remembers the usernames so that listing merge
+// requests with the "all" scope covers every team member.
+func (r *Repository) PreloadUsersByUsernames(_ context.Context, usernames []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.usernames = append([]string(nil), usernames...)
+
+	return nil
+}
+
+// FetchUsersByUsernames fetches users by their usernames.
+func (r *Repository) FetchUsersByUsernames(ctx context.Context, usernames []string) ([]*domain.User, error) {
+	g, ctx := errgroup.WithContext(ctx)
+	users := make([]*domain.User, len(usernames))
+
+	for i, username := range usernames {
+		g.Go(func() error {
+			user, err := r.GetUserByUsername(ctx, username)
+			if err != nil {
+				return err
+			}
+			users[i] = user
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to fetch users: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetUserByUsername gets a user by username.
+func (r *Repository) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	var u user
+	if err := r.get(ctx, "/users/"+url.PathEscape(username), nil, &u); err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", username, err)
+	}
+
+	return r.toDomainUser(&u), nil
+}
+
+// ListGroupMembers lists the members of an organization, or of a team when
+// groupPath has the form "org/team-slug".
+func (r *Repository) ListGroupMembers(ctx context.Context, groupPath string) ([]*domain.User, error) {
+	path := "/orgs/" + url.PathEscape(groupPath) + "/members"
+	if org, team, ok := strings.Cut(groupPath, "/"); ok {
+		path = "/orgs/" + url.PathEscape(org) + "/teams/" + url.PathEscape(team) + "/members"
+	}
+
+	members, err := getAll[user](ctx, r, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members of %s: %w", groupPath, err)
+	}
+
+	users := make([]*domain.User, 0, len(members))
+	for i := range members {
+		users = append(users, r.toDomainUser(&members[i]))
+	}
+
+	return users, nil
+}
+
+// GetCurrentUser gets the current authenticated user.
+func (r *Repository) GetCurrentUser(ctx context.Context) (*domain.User, error) {
+	var u user
+	if err := r.get(ctx, "/user", nil, &u); err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	return r.toDomainUser(&u), nil
+}
+
+// GetTokenInfo gets the owner of the token in use along with the scopes and
+// expiry GitHub reports in response headers.
+func (r *Repository) GetTokenInfo(ctx context.Context) (*domain.TokenInfo, error) {
+	var u user
+	header, err := r.request(ctx, http.MethodGet, "/user", nil, &u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	info := &domain.TokenInfo{User: r.toDomainUser(&u)}
+
+	// Fine-grained tokens report no scopes at all.
+	for _, scope := range strings.Split(header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			info.Scopes = append(info.Scopes, scope)
+		}
+	}
+
+	if expiration := header.Get("GitHub-Authentication-Token-Expiration"); expiration != "" {
+		expiresAt, err := time.Parse(tokenExpirationLayout, expiration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse token expiration %q: %w", expiration, err)
+		}
+		info.ExpiresAt = &expiresAt
+	}
+
+	return info, nil
+}
+
+// GetAllUsers retrieves all users.
+func (r *Repository) GetAllUsers(_ context.Context) ([]*domain.User, error) {
+	return []*domain.User{}, nil
+}
+
+// GetProject retrieves a repository by its owner/name path.
+func (r *Repository) GetProject(ctx context.Context, path string) (*domain.Project, error) {
+	var repo repository
+	if err := r.get(ctx, "/repos/"+path, nil, &repo); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	r.rememberRepository(&repo)
+
+	return &domain.Project{
+		ID:            repo.ID,
+		Path:          repo.FullName,
+		DefaultBranch: repo.DefaultBranch,
+	}, nil
+}
+
+// GetMergeRequest retrieves a pull request by repository ID and number,
+// along with the checks of its head commit as its pipeline.
+func (r *Repository) GetMergeRequest(ctx context.Context, projectID, mrID int) (*domain.MergeRequest, error) {
+	var pr pullRequest
+	if err := r.get(ctx, fmt.Sprintf("/repositories/%d/pulls/%d", projectID, mrID), nil, &pr); err != nil {
+		return nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	mr := r.toDomainMR(&pr)
+
+	status, failed, err := r.checks(ctx, pr.Base.Repo.FullName, pr.Head.SHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request checks: %w", err)
+	}
+	mr.PipelineStatus, mr.FailedJobs, mr.PipelineUnknown = status, failed, false
+
+	return mr, nil
+}
+
+// checks sums up the check runs and commit statuses of a commit as a pipeline
+// status, along with the names of the failed checks once all have finished.
+// The status is empty when the commit has no checks.
+func (r *Repository) checks(ctx context.Context, repoName, sha string) (string, []string, error) {
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	params := url.Values{"per_page": {strconv.Itoa(perPageLimit)}}
+	if err := r.get(ctx, fmt.Sprintf("/repos/%s/commits/%s/check-runs", repoName, sha), params, &runs); err != nil {
+		return "", nil, err
+	}
+
+	var combined struct {
+		Statuses []struct {
+			Context string `json:"context"`
+			State   string `json:"state"`
+		} `json:"statuses"`
+	}
+	if err := r.get(ctx, fmt.Sprintf("/repos/%s/commits/%s/status", repoName, sha), params, &combined); err != nil {
+		return "", nil, err
+	}
+
+	if len(runs.CheckRuns) == 0 && len(combined.Statuses) == 0 {
+		return "", nil, nil
+	}
+
+	var failed []string
+	for _, run := range runs.CheckRuns {
+		switch {
+		case run.Status != "completed":
+			return checkStatusRunning, nil, nil
+		case slices.Contains([]string{"failure", "timed_out", "cancelled", "action_required"}, run.Conclusion):
+			failed = append(failed, run.Name)
+		}
+	}
+	for _, status := range combined.Statuses {
+		switch status.State {
+		case "pending":
+			return checkStatusRunning, nil, nil
+		case "failure", "error":
+			failed = append(failed, status.Context)
+		}
+	}
+
+	if len(failed) > 0 {
+		return domain.PipelineFailed, failed, nil
+	}
+
+	return domain.PipelineSuccess, nil, nil
+}
+
+// ListMergeRequests lists the pull requests selected by query.
+//
+// GitHub has no instance-wide pull request listing, so the "all" scope is
+// resolved to pull requests involving or awaiting review from the current
+// user or any preloaded team member, unless the query filters on more.
+func (r *Repository) ListMergeRequests(
+	ctx context.Context,
+	query *domain.MergeRequestQuery,
+) ([]*domain.MergeRequest, error) {
+	queries, err := r.searchQueries(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	type ref struct {
+		repo   string
+		number int
+	}
+
+	seen := make(map[ref]struct{})
+	var refs []ref
+
+	for _, q := range queries {
+		items, err := r.search(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merge requests: %w", err)
+		}
+
+		for _, item := range items {
+			repoName := item.RepositoryURL[strings.Index(item.RepositoryURL, "/repos/")+len("/repos/"):]
+			key := ref{repo: repoName, number: item.Number}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			refs = append(refs, key)
+		}
+	}
+
+	// Search results leave out the branches and reviewers of pull requests.
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(fetchLimit)
+	mrs := make([]*domain.MergeRequest, len(refs))
+
+	for i, ref := range refs {
+		g.Go(func() error {
+			var pr pullRequest
+			if err := r.get(gctx, fmt.Sprintf("/repos/%s/pulls/%d", ref.repo, ref.number), nil, &pr); err != nil {
+				return err
+			}
+			mrs[i] = r.toDomainMR(&pr)
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to list merge requests: %w", err)
+	}
+
+	return mrs, nil
+}
+
+// searchItem is a pull request found by an issue search.
+type searchItem struct {
+	Number        int    `json:"number"`
+	RepositoryURL string `json:"repository_url"`
+}
+
+// search returns every pull request matching an issue search query, up to
+// the number of results GitHub returns.
+func (r *Repository) search(ctx context.Context, q string) ([]searchItem, error) {
+	var items []searchItem
+	for page := 1; ; page++ {
+		var result struct {
+			TotalCount int          `json:"total_count"`
+			Items      []searchItem `json:"items"`
+		}
+
+		params := url.Values{
+			"q":               {q},
+			"advanced_search": {"true"},
+			"per_page":        {strconv.Itoa(perPageLimit)},
+			"page":            {strconv.Itoa(page)},
+		}
+		if err := r.get(ctx, "/search/issues", params, &result); err != nil {
+			return nil, err
+		}
+		items = append(items, result.Items...)
+
+		if len(result.Items) < perPageLimit || page*perPageLimit >= min(result.TotalCount, searchResultsLimit) {
+			return items, nil
+		}
+	}
+}
+
+// searchQueries builds the issue search queries for a merge request query,
+// its filters as qualifiers. A query naming a repository, an owner or a user
+// needs no scope, which only applies otherwise. The "all" scope ORs the users
+// in as few queries as possible, searches being rate limited.
+func (r *Repository) searchQueries(ctx context.Context, query *domain.MergeRequestQuery) ([]string, error) {
+	base := "is:pr"
+	switch query.State {
+	case domain.StateOpened:
+		base += " is:open"
+	case domain.StateClosed:
+		base += " is:closed is:unmerged"
+	case domain.StateMerged:
+		base += " is:merged"
+	}
+
+	qualifiers := searchQualifiers(query)
+
+	switch {
+	case query.HasOwner():
+		return []string{base + qualifiers}, nil
+	case query.Scope == domain.ScopeAll:
+		current, err := r.GetCurrentUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		r.mu.Lock()
+		usernames := append([]string{current.Username}, r.usernames...)
+		r.mu.Unlock()
+
+		terms := make([]string, 0, 2*len(usernames))
+		for _, username := range usernames {
+			terms = append(terms, "involves:"+username, "review-requested:"+username)
+		}
+
+		var queries []string
+		for chunk := range slices.Chunk(terms, searchTermsPerQuery) {
+			queries = append(queries, base+" ("+strings.Join(chunk, " OR ")+")"+qualifiers)
+		}
+
+		return queries, nil
+	case query.Scope == domain.ScopeAssignedToMe:
+		return []string{base + " assignee:@me" + qualifiers}, nil
+	default:
+		return []string{base + " author:@me" + qualifiers}, nil
+	}
+}
+
+// searchQualifiers converts the filters of a query, but the scope, to search
+// qualifiers. Groups are owners, users or organizations.
+func searchQualifiers(query *domain.MergeRequestQuery) string {
+	var b strings.Builder
+
+	if query.Author != "" {
+		b.WriteString(" author:" + query.Author)
+	}
+	if query.Assignee != "" {
+		b.WriteString(" assignee:" + query.Assignee)
+	}
+	if query.Reviewer != "" {
+		b.WriteString(" review-requested:" + query.Reviewer)
+	}
+	if query.Project != "" {
+		b.WriteString(" repo:" + query.Project)
+	}
+	if query.Group != "" {
+		b.WriteString(" user:" + query.Group)
+	}
+	for _, label := range query.Labels {
+		b.WriteString(" label:" + strconv.Quote(label))
+	}
+	if query.Milestone != "" {
+		b.WriteString(" milestone:" + strconv.Quote(query.Milestone))
+	}
+	if query.Draft != nil {
+		b.WriteString(" draft:" + strconv.FormatBool(*query.Draft))
+	}
+	if query.SourceBranch != "" {
+		b.WriteString(" head:" + query.SourceBranch)
+	}
+	if query.TargetBranch != "" {
+		b.WriteString(" base:" + query.TargetBranch)
+	}
+	if query.CreatedAfter != nil {
+		b.WriteString(" created:>=" + query.CreatedAfter.UTC().Format(time.RFC3339))
+	}
+	if query.CreatedBefore != nil {
+		b.WriteString(" created:<" + query.CreatedBefore.UTC().Format(time.RFC3339))
+	}
+
+	return b.String()
+}
+
+// GetMergeRequestApprovals returns the users whose latest review approves the
+// pull request. The number of approvals required by branch protection is not
+// readable by every user, so domain.DefaultRequiredApprovals applies.
+func (r *Repository) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) (*domain.Approvals, error) {
+	type review struct {
+		User  user   `json:"user"`
+		State string `json:"state"`
+	}
+
+	reviews, err := getAll[review](ctx, r, fmt.Sprintf("/repositories/%d/pulls/%d/reviews", projectID, mrID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request approvals: %w", err)
+	}
+
+	// Reviews are returned oldest first; a later non-comment review replaces
+	// an earlier approval from the same user.
+	latest := make(map[int]string)
+	order := make([]*user, 0, len(reviews))
+	for i := range reviews {
+		review := &reviews[i]
+		if review.State == reviewStateComment {
+			continue
+		}
+		if _, ok := latest[review.User.ID]; !ok {
+			order = append(order, &review.User)
+		}
+		latest[review.User.ID] = review.State
+	}
+
+	approvals := make([]*domain.User, 0, len(order))
+	for _, u := range order {
+		if latest[u.ID] == reviewStateApproved {
+			approvals = append(approvals, r.toDomainUser(u))
+		}
+	}
+
+	return domain.NewApprovals(approvals), nil
+}
+
+// GetMergeRequestApprovalRules returns no rules, GitHub has no approval rules.
+func (r *Repository) GetMergeRequestApprovalRules(context.Context, int, int) ([]*domain.ApprovalRule, error) {
+	return nil, nil
+}
+
+// ListMergeRequestDiscussions retrieves the conversation comments of a pull
+// request, one discussion each, and its review comment threads. The REST API
+// does not tell whether a review thread is resolved, so none is resolvable.
+func (r *Repository) ListMergeRequestDiscussions(
+	ctx context.Context,
+	projectID, mrID int,
+) ([]*domain.Discussion, error) {
+	issuePath := fmt.Sprintf("/repositories/%d/issues/%d/comments", projectID, mrID)
+	issueComments, err := getAll[*comment](ctx, r, issuePath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge request discussions: %w", err)
+	}
+
+	reviewPath := fmt.Sprintf("/repositories/%d/pulls/%d/comments", projectID, mrID)
+	reviewComments, err := getAll[*comment](ctx, r, reviewPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge request discussions: %w", err)
+	}
+
+	discussions := make([]*domain.Discussion, 0, len(issueComments)+len(reviewComments))
+	for _, c := range issueComments {
+		discussions = append(discussions, &domain.Discussion{
+			ID:    strconv.Itoa(c.ID),
+			Notes: []*domain.Note{r.toDomainNote(c)},
+		})
+	}
+
+	// Replies point at the first comment of their thread and come after it.
+	threads := make(map[int]*domain.Discussion)
+	for _, c := range reviewComments {
+		if thread, ok := threads[c.InReplyToID]; ok {
+			thread.Notes = append(thread.Notes, r.toDomainNote(c))
+
+			continue
+		}
+
+		thread := &domain.Discussion{ID: strconv.Itoa(c.ID), Notes: []*domain.Note{r.toDomainNote(c)}}
+		threads[c.ID] = thread
+		discussions = append(discussions, thread)
+	}
+
+	return discussions, nil
+}
+
+// ListCommits lists commits on the default branch of a repository.
+func (r *Repository) ListCommits(ctx context.Context, projectID int) ([]*domain.Commit, error) {
+	var commits []struct {
+		SHA     string `json:"sha"`
+		HTMLURL string `json:"html_url"`
+		Commit  struct {
+			Message string `json:"message"`
+			Author  struct {
+				Name  string    `json:"name"`
+				Email string    `json:"email"`
+				Date  time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+	}
+
+	path := fmt.Sprintf("/repositories/%d/commits", projectID)
+	if err := r.get(ctx, path, url.Values{"per_page": {strconv.Itoa(perPageLimit)}}, &commits); err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	result := make([]*domain.Commit, 0, len(commits))
+	for _, c := range commits {
+		result = append(result, &domain.Commit{
+			ID:          c.SHA,
+			AuthorName:  c.Commit.Author.Name,
+			AuthorEmail: c.Commit.Author.Email,
+			CreatedAt:   c.Commit.Author.Date,
+			Message:     c.Commit.Message,
+			WebURL:      c.HTMLURL,
+		})
+	}
+
+	return result, nil
+}
+
+// UpdateMergeRequest sets the assignee and requests reviews on a pull request.
+func (r *Repository) UpdateMergeRequest(
+	ctx context.Context,
+	projectID, mrID int,
+	assigneeID *int,
+	reviewerIDs []int,
+) error {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to update merge request: %w", err)
+	}
+
+	if assigneeID != nil {
+		login, err := r.login(ctx, *assigneeID)
+		if err != nil {
+			return fmt.Errorf("failed to update merge request: %w", err)
+		}
+
+		body := map[string][]string{"assignees": {login}}
+		if err := r.send(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", repoName, mrID), body, nil); err != nil {
+			return fmt.Errorf("failed to update merge request: %w", err)
+		}
+	}
+
+	if len(reviewerIDs) > 0 {
+		logins := make([]string, 0, len(reviewerIDs))
+		for _, id := range reviewerIDs {
+			login, err := r.login(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to update merge request: %w", err)
+			}
+			logins = append(logins, login)
+		}
+
+		body := map[string][]string{"reviewers": logins}
+		path := fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", repoName, mrID)
+		if err := r.send(ctx, http.MethodPost, path, body, nil); err != nil {
+			return fmt.Errorf("failed to update merge request: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CreateMergeRequest opens a pull request. Labels, the assignee and the
+// reviewers are set once the pull request exists, as the API does not accept
+// them on creation.
+func (r *Repository) CreateMergeRequest(
+	ctx context.Context,
+	projectID int,
+	opts *domain.NewMergeRequest,
+) (*domain.MergeRequest, error) {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+
+	body := map[string]any{
+		"title": opts.Title,
+		"body":  opts.Description,
+		"head":  opts.SourceBranch,
+		"base":  opts.TargetBranch,
+		"draft": opts.Draft,
+	}
+
+	var pr pullRequest
+	if err := r.send(ctx, http.MethodPost, "/repos/"+repoName+"/pulls", body, &pr); err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+
+	if len(opts.Labels) > 0 {
+		labels := map[string][]string{"labels": opts.Labels}
+		path := fmt.Sprintf("/repos/%s/issues/%d/labels", repoName, pr.Number)
+		if err := r.send(ctx, http.MethodPost, path, labels, nil); err != nil {
+			return nil, fmt.Errorf("failed to label merge request: %w", err)
+		}
+	}
+
+	if err := r.UpdateMergeRequest(ctx, projectID, pr.Number, opts.AssigneeID, opts.ReviewerIDs); err != nil {
+		return nil, err
+	}
+
+	return r.toDomainMR(&pr), nil
+}
+
+// ApproveMergeRequest submits an approving review on a pull request.
+func (r *Repository) ApproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to approve merge request: %w", err)
+	}
+
+	body := map[string]string{"event": "APPROVE"}
+	path := fmt.Sprintf("/repos/%s/pulls/%d/reviews", repoName, mrID)
+	if err := r.send(ctx, http.MethodPost, path, body, nil); err != nil {
+		return fmt.Errorf("failed to approve merge request: %w", err)
+	}
+
+	return nil
+}
+
+// UnapproveMergeRequest dismisses the latest approving review of the current
+// user on a pull request.
+func (r *Repository) UnapproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to unapprove merge request: %w", err)
+	}
+
+	current, err := r.GetCurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to unapprove merge request: %w", err)
+	}
+
+	type review struct {
+		ID    int    `json:"id"`
+		User  user   `json:"user"`
+		State string `json:"state"`
+	}
+
+	reviews, err := getAll[review](ctx, r, fmt.Sprintf("/repos/%s/pulls/%d/reviews", repoName, mrID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to unapprove merge request: %w", err)
+	}
+
+	for i := len(reviews) - 1; i >= 0; i-- {
+		if reviews[i].User.ID != current.ID || reviews[i].State != reviewStateApproved {
+			continue
+		}
+
+		body := map[string]string{"message": "Approval withdrawn", "event": "DISMISS"}
+		path := fmt.Sprintf("/repos/%s/pulls/%d/reviews/%d/dismissals", repoName, mrID, reviews[i].ID)
+		if err := r.send(ctx, http.MethodPut, path, body, nil); err != nil {
+			return fmt.Errorf("failed to unapprove merge request: %w", err)
+		}
+
+		return nil
+	}
+
+	return errors.New("failed to unapprove merge request: it is not approved by you")
+}
+
+// MergeMergeRequest merges a pull request and removes its source branch when
+// asked to. GitHub only offers auto-merge through its GraphQL API, so
+// WhenPipelineSucceeds is not supported.
+func (r *Repository) MergeMergeRequest(ctx context.Context, projectID, mrID int, opts *domain.MergeOptions) error {
+	if opts.WhenPipelineSucceeds {
+		return ErrAutoMergeUnsupported
+	}
+
+	repoName, err := r.repositoryName(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to merge merge request: %w", err)
+	}
+
+	var pr pullRequest
+	if err := r.get(ctx, fmt.Sprintf("/repos/%s/pulls/%d", repoName, mrID), nil, &pr); err != nil {
+		return fmt.Errorf("failed to merge merge request: %w", err)
+	}
+
+	method := "merge"
+	if opts.Squash {
+		method = "squash"
+	}
+
+	body := map[string]string{"merge_method": method}
+	if err := r.send(ctx, http.MethodPut, fmt.Sprintf("/repos/%s/pulls/%d/merge", repoName, mrID), body, nil); err != nil {
+		return fmt.Errorf("failed to merge merge request: %w", err)
+	}
+
+	// Branches of forks belong to their owners.
+	if opts.RemoveSourceBranch && pr.Head.Repo != nil && pr.Head.Repo.ID == pr.Base.Repo.ID {
+		path := fmt.Sprintf("/repos/%s/git/refs/heads/%s", repoName, pr.Head.Ref)
+		if err := r.send(ctx, http.MethodDelete, path, nil, nil); err != nil {
+			return fmt.Errorf("failed to remove source branch: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RebaseMergeRequest returns ErrRebaseUnsupported.
+func (r *Repository) RebaseMergeRequest(_ context.Context, _, _ int) error {
+	return ErrRebaseUnsupported
+}
+
+// GetUserEvents retrieves user events within the specified time range.
+func (r *Repository) GetUserEvents(
+	ctx context.Context,
+	userID int,
+	after time.Time,
+	before *time.Time,
+) ([]*domain.Event, error) {
+	login, err := r.login(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user events: %w", err)
+	}
+
+	events, err := getAll[*event](ctx, r, "/users/"+url.PathEscape(login)+"/events", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user events: %w", err)
+	}
+
+	result := make([]*domain.Event, 0, len(events))
+	for _, e := range events {
+		if e.CreatedAt.Before(after) || (before != nil && e.CreatedAt.After(*before)) {
+			continue
+		}
+
+		if domainEvent := e.toDomain(); domainEvent != nil {
+			result = append(result, domainEvent)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) repositoryName(ctx context.Context, projectID int) (string, error) {
+	r.mu.Lock()
+	name, ok := r.repoNames[projectID]
+	r.mu.Unlock()
+
+	if ok {
+		return name, nil
+	}
+
+	var repo repository
+	if err := r.get(ctx, fmt.Sprintf("/repositories/%d", projectID), nil, &repo); err != nil {
+		return "", fmt.Errorf("failed to get repository %d: %w", projectID, err)
+	}
+
+	r.rememberRepository(&repo)
+
+	return repo.FullName, nil
+}
+
+func (r *Repository) rememberRepository(repo *repository) {
+	r.mu.Lock()
+	r.repoNames[repo.ID] = repo.FullName
+	r.mu.Unlock()
+}
+
+// login resolves a user ID to a login, which most write endpoints expect.
+func (r *Repository) login(ctx context.Context, userID int) (string, error) {
+	r.mu.Lock()
+	login, ok := r.logins[userID]
+	r.mu.Unlock()
+
+	if ok {
+		return login, nil
+	}
+
+	var u user
+	if err := r.get(ctx, fmt.Sprintf("/user/%d", userID), nil, &u); err != nil {
+		return "", fmt.Errorf("failed to get user %d: %w", userID, err)
+	}
+
+	r.toDomainUser(&u)
+
+	return u.Login, nil
+}
+
+// getAll reads every page of a listing, following the next links of the Link
+// header.
+func getAll[T any](ctx context.Context, r *Repository, path string, params url.Values) ([]T, error) {
+	params = maps.Clone(params)
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("per_page", strconv.Itoa(perPageLimit))
+	path += "?" + params.Encode()
+
+	var items []T
+	for path != "" {
+		var batch []T
+		header, err := r.request(ctx, http.MethodGet, path, nil, &batch)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, batch...)
+		path = r.nextPage(header)
+	}
+
+	return items, nil
+}
+
+// nextPage returns the path of the page following a response, or an empty
+// string on the last page.
+func (r *Repository) nextPage(header http.Header) string {
+	for link := range strings.SplitSeq(header.Get("Link"), ",") {
+		target, rel, ok := strings.Cut(link, ";")
+		if !ok || !strings.Contains(rel, `rel="next"`) {
+			continue
+		}
+
+		next := strings.Trim(strings.TrimSpace(target), "<>")
+		if !strings.HasPrefix(next, r.apiURL) {
+			return ""
+		}
+
+		return strings.TrimPrefix(next, r.apiURL)
+	}
+
+	return ""
+}
+
+func (r *Repository) get(ctx context.Context, path string, params url.Values, out any) error {
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	return r.send(ctx, http.MethodGet, path, nil, out)
+}
+
+func (r *Repository) send(ctx context.Context, method, path string, body, out any) error {
+	_, err := r.request(ctx, method, path, body, out)
+
+	return err
+}
+
+// request performs an API call and returns the response headers, which carry
+// metadata such as token scopes.
+func (r *Repository) request(ctx context.Context, method, path string, body, out any) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.apiURL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+
+		return nil, fmt.Errorf("%s %s: %d %s", method, path, resp.StatusCode, apiErr.Message)
+	}
+
+	if out == nil {
+		return resp.Header, nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return resp.Header, nil
+}
+
+type comment struct {
+	ID          int       `json:"id"`
+	InReplyToID int       `json:"in_reply_to_id"`
+	User        user      `json:"user"`
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	HTMLURL     string    `json:"html_url"`
+}
+
+func (r *Repository) toDomainNote(c *comment) *domain.Note {
+	return &domain.Note{
+		ID:        c.ID,
+		Author:    r.toDomainUser(&c.User),
+		Body:      c.Body,
+		CreatedAt: c.CreatedAt,
+		WebURL:    c.HTMLURL,
+	}
+}
+
+type user struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+	Email string `json:"email"`
+}
+
+// toDomainUser converts a GitHub user and remembers its login. GitHub only
+// exposes user status through GraphQL, so Status is left empty.
+func (r *Repository) toDomainUser(u *user) *domain.User {
+	r.mu.Lock()
+	r.logins[u.ID] = u.Login
+	r.mu.Unlock()
+
+	return &domain.User{
+		ID:       u.ID,
+		Username: u.Login,
+		Email:    u.Email,
+	}
+}
+
+type repository struct {
+	ID            int    `json:"id"`
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+type pullRequest struct {
+	ID                 int        `json:"id"`
+	Number             int        `json:"number"`
+	Title              string     `json:"title"`
+	Body               string     `json:"body"`
+	HTMLURL            string     `json:"html_url"`
+	User               *user      `json:"user"`
+	Assignee           *user      `json:"assignee"`
+	RequestedReviewers []*user    `json:"requested_reviewers"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	Draft              bool       `json:"draft"`
+	State              string     `json:"state"`
+	MergedAt           *time.Time `json:"merged_at"`
+	MergeableState     string     `json:"mergeable_state"`
+	ChangedFiles       int        `json:"changed_files"`
+	Comments           int        `json:"comments"`
+	ReviewComments     int        `json:"review_comments"`
+	Milestone          *milestone `json:"milestone"`
+	Head               struct {
+		Ref  string      `json:"ref"`
+		SHA  string      `json:"sha"`
+		Repo *repository `json:"repo"`
+	} `json:"head"`
+	Base struct {
+		Ref  string     `json:"ref"`
+		Repo repository `json:"repo"`
+	} `json:"base"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+type milestone struct {
+	Title string `json:"title"`
+}
+
+// pullRequestState maps the "open" and "closed" states of pull requests to
+// the domain states, telling merged pull requests apart from closed ones.
+func pullRequestState(state string, merged bool) string {
+	switch {
+	case merged:
+		return domain.StateMerged
+	case state == "open":
+		return domain.StateOpened
+	default:
+		return domain.StateClosed
+	}
+}
+
+// mergeStatus maps the mergeable state of a pull request to the detailed
+// merge statuses of the domain, keeping the states without an equivalent.
+// GitHub reports "unknown" until it has computed the state.
+func mergeStatus(mergeableState string) string {
+	switch mergeableState {
+	case "unknown":
+		return ""
+	case "dirty":
+		return domain.MergeStatusConflict
+	case "behind":
+		return domain.MergeStatusNeedRebase
+	case "clean", "unstable", "has_hooks":
+		return domain.MergeStatusMergeable
+	default:
+		return mergeableState
+	}
+}
+
+func (r *Repository) toDomainMR(pr *pullRequest) *domain.MergeRequest {
+	r.rememberRepository(&pr.Base.Repo)
+
+	mr := &domain.MergeRequest{
+		ID:           pr.ID,
+		IID:          pr.Number,
+		Title:        pr.Title,
+		Description:  pr.Body,
+		WebURL:       pr.HTMLURL,
+		Reviewers:    make([]*domain.User, 0, len(pr.RequestedReviewers)),
+		CreatedAt:    pr.CreatedAt,
+		UpdatedAt:    pr.UpdatedAt,
+		ProjectID:    pr.Base.Repo.ID,
+		Draft:        pr.Draft,
+		SourceBranch: pr.Head.Ref,
+		TargetBranch: pr.Base.Ref,
+		State:        pullRequestState(pr.State, pr.MergedAt != nil),
+		HeadRef:      fmt.Sprintf("refs/pull/%d/head", pr.Number),
+		// The head repository is missing when the fork was deleted.
+		Fork:         pr.Head.Repo == nil || pr.Head.Repo.ID != pr.Base.Repo.ID,
+		ChangedFiles: pr.ChangedFiles,
+		CommentCount: pr.Comments + pr.ReviewComments,
+		// Only GetMergeRequest looks up the checks of the head commit.
+		PipelineUnknown: true,
+
+		DetailedMergeStatus: mergeStatus(pr.MergeableState),
+		HasConflicts:        pr.MergeableState == "dirty",
+	}
+
+	for _, label := range pr.Labels {
+		mr.Labels = append(mr.Labels, label.Name)
+	}
+
+	if pr.Milestone != nil {
+		mr.Milestone = pr.Milestone.Title
+	}
+
+	if pr.User != nil {
+		mr.Author = r.toDomainUser(pr.User)
+	}
+
+	if pr.Assignee != nil {
+		mr.Assignee = r.toDomainUser(pr.Assignee)
+	}
+
+	for _, reviewer := range pr.RequestedReviewers {
+		mr.Reviewers = append(mr.Reviewers, r.toDomainUser(reviewer))
+	}
+
+	return mr
+}
+
+type event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Repo      eventRepo       `json:"repo"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// eventRepo is the abbreviated repository in the events API, where Name holds
+// the owner/name path.
+type eventRepo struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type eventPayload struct {
+	Action  string `json:"action"`
+	Ref     string `json:"ref"`
+	RefType string `json:"ref_type"`
+	Size    int    `json:"size"`
+	Commits []struct {
+		Message string `json:"message"`
+	} `json:"commits"`
+	PullRequest *struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+	} `json:"pull_request"`
+	Issue *struct {
+		Number      int    `json:"number"`
+		Title       string `json:"title"`
+		HTMLURL     string `json:"html_url"`
+		PullRequest any    `json:"pull_request"`
+	} `json:"issue"`
+	Comment *struct {
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+	} `json:"comment"`
+	Review *struct {
+		State   string `json:"state"`
+		HTMLURL string `json:"html_url"`
+	} `json:"review"`
+}
+
+// toDomain maps a GitHub event onto the GitLab-shaped domain event so the
+// activity view renders it the same way. Unsupported event types yield nil.
+func (e *event) toDomain() *domain.Event {
+	var payload eventPayload
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return nil
+	}
+
+	id, _ := strconv.Atoi(e.ID)
+	domainEvent := &domain.Event{
+		ID:          id,
+		ProjectID:   e.Repo.ID,
+		ProjectPath: e.Repo.Name,
+		CreatedAt:   e.CreatedAt,
+	}
+
+	switch e.Type {
+	case "PushEvent":
+		domainEvent.Action = "pushed to"
+		domainEvent.PushRef = payload.Ref
+		domainEvent.PushAction = "pushed to"
+		domainEvent.CommitCount = payload.Size
+		if len(payload.Commits) > 0 {
+			domainEvent.CommitTitle = firstLine(payload.Commits[len(payload.Commits)-1].Message)
+		}
+	case "CreateEvent", "DeleteEvent":
+		if payload.RefType != "branch" && payload.RefType != "tag" {
+			return nil
+		}
+		domainEvent.Action = strings.ToLower(strings.TrimSuffix(e.Type, "Event")) + "d"
+		domainEvent.PushAction = domainEvent.Action
+		domainEvent.PushRef = "refs/heads/" + payload.Ref
+		if payload.RefType == "tag" {
+			domainEvent.PushRef = "refs/tags/" + payload.Ref
+		}
+	case "PullRequestEvent":
+		if payload.PullRequest == nil {
+			return nil
+		}
+		domainEvent.Action = payload.Action
+		domainEvent.TargetType = "MergeRequest"
+		domainEvent.TargetID = payload.PullRequest.Number
+		domainEvent.TargetTitle = payload.PullRequest.Title
+		domainEvent.WebURL = payload.PullRequest.HTMLURL
+	case "PullRequestReviewEvent":
+		if payload.PullRequest == nil || payload.Review == nil {
+			return nil
+		}
+		domainEvent.Action = "reviewed"
+		if strings.EqualFold(payload.Review.State, reviewStateApproved) {
+			domainEvent.Action = "approved"
+		}
+		domainEvent.TargetType = "MergeRequest"
+		domainEvent.TargetID = payload.PullRequest.Number
+		domainEvent.TargetTitle = payload.PullRequest.Title
+		domainEvent.WebURL = payload.Review.HTMLURL
+	case "IssueCommentEvent", "PullRequestReviewCommentEvent":
+		if payload.Comment == nil {
+			return nil
+		}
+		domainEvent.Action = "commented on"
+		domainEvent.TargetType = "Note"
+		domainEvent.NoteBody = payload.Comment.Body
+		domainEvent.WebURL = payload.Comment.HTMLURL
+		domainEvent.NoteableType = "MergeRequest"
+		switch {
+		case payload.PullRequest != nil:
+			domainEvent.TargetTitle = payload.PullRequest.Title
+		case payload.Issue != nil:
+			domainEvent.TargetTitle = payload.Issue.Title
+			if payload.Issue.PullRequest == nil {
+				domainEvent.NoteableType = "Issue"
+			}
+		}
+	case "IssuesEvent":
+		if payload.Issue == nil {
+			return nil
+		}
+		domainEvent.Action = payload.Action
+		domainEvent.TargetType = "Issue"
+		domainEvent.TargetID = payload.Issue.Number
+		domainEvent.TargetTitle = payload.Issue.Title
+		domainEvent.WebURL = payload.Issue.HTMLURL
+	default:
+		return nil
+	}
+
+	return domainEvent
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+
+	return line
+}
@@ -0,0 +1,550 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pullRequestBody = `{
+	"id": 1001, "number": 7, "title": "Add feature", "body": "Closes #1",
+	"html_url": "https://github.com/owner/repo/pull/7",
+	"user": {"id": 9, "login": "author"},
+	"assignee": {"id": 1, "login": "alice"},
+	"requested_reviewers": [{"id": 2, "login": "bob"}],
+	"created_at": "2025-01-01T10:00:00Z", "updated_at": "2025-01-02T10:00:00Z",
+	"draft": true, "comments": 2, "review_comments": 3, "head": {"ref": "feature"},
+	"base": {"repo": {"id": 42, "full_name": "owner/repo"}}
+}`
+
+func newTestRepository(t *testing.T, mux *http.ServeMux) *Repository {
+	t.Helper()
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	repo := NewRepository(server.Client(), "https://github.com", "token")
+	repo.apiURL = server.URL
+
+	return repo
+}
+
+func TestAPIURL(t *testing.T) {
+	assert.Equal(t, "https://api.github.com", apiURL("https://github.com"))
+	assert.Equal(t, "https://api.github.com", apiURL("https://github.com/"))
+	assert.Equal(t, "https://ghe.example.com/api/v3", apiURL("https://ghe.example.com"))
+}
+
+func TestRepository_ListMergeRequests_All(t *testing.T) {
+	var queries []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"id": 1, "login": "alice"}`))
+	})
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		_, _ = w.Write([]byte(`{"items": [{"number": 7, "repository_url": "https://api.github.com/repos/owner/repo"}]}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(pullRequestBody))
+	})
+
+	repo := newTestRepository(t, mux)
+	ctx := context.Background()
+	require.NoError(t, repo.PreloadUsersByUsernames(ctx, []string{"bob"}))
+
+	mrs, err := repo.ListMergeRequests(ctx, &domain.MergeRequestQuery{State: domain.StateOpened, Scope: domain.ScopeAll})
+	require.NoError(t, err)
+	require.Len(t, mrs, 1, "pull requests found by several queries must be deduplicated")
+
+	assert.Equal(t, []string{
+		"is:pr is:open (involves:alice OR review-requested:alice OR involves:bob OR review-requested:bob)",
+	}, queries, "users are combined in a single search")
+
+	mr := mrs[0]
+	assert.Equal(t, 7, mr.IID)
+	assert.Equal(t, 42, mr.ProjectID)
+	assert.Equal(t, "author", mr.Author.Username)
+	assert.Equal(t, "alice", mr.Assignee.Username)
+	require.Len(t, mr.Reviewers, 1)
+	assert.Equal(t, "bob", mr.Reviewers[0].Username)
+	assert.True(t, mr.Draft)
+	assert.Equal(t, "feature", mr.SourceBranch)
+	assert.True(t, mr.PipelineUnknown, "listings do not look up the checks")
+	assert.Equal(t, 5, mr.CommentCount, "conversation and review comments are counted")
+}
+
+func TestRepository_GetMergeRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkRuns  string
+		statuses   string
+		status     string
+		failedJobs []string
+	}{
+		{name: "no checks", checkRuns: `[]`, statuses: `[]`},
+		{
+			name:      "running check",
+			checkRuns: `[{"name": "lint", "status": "completed", "conclusion": "failure"}, {"name": "test", "status": "in_progress"}]`,
+			statuses:  `[]`,
+			status:    "running",
+		},
+		{
+			name:       "failed checks",
+			checkRuns:  `[{"name": "lint", "status": "completed", "conclusion": "failure"}]`,
+			statuses:   `[{"context": "ci/deploy", "state": "error"}, {"context": "ci/build", "state": "success"}]`,
+			status:     domain.PipelineFailed,
+			failedJobs: []string{"lint", "ci/deploy"},
+		},
+		{
+			name:      "passed checks",
+			checkRuns: `[{"name": "lint", "status": "completed", "conclusion": "skipped"}]`,
+			statuses:  `[{"context": "ci/build", "state": "success"}]`,
+			status:    domain.PipelineSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/repositories/42/pulls/7", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"number": 7, "head": {"ref": "feature", "sha": "abc"},
+					"base": {"ref": "main", "repo": {"id": 42, "full_name": "owner/repo"}}}`))
+			})
+			mux.HandleFunc("/repos/owner/repo/commits/abc/check-runs", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"check_runs": ` + tt.checkRuns + `}`))
+			})
+			mux.HandleFunc("/repos/owner/repo/commits/abc/status", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"statuses": ` + tt.statuses + `}`))
+			})
+
+			mr, err := newTestRepository(t, mux).GetMergeRequest(context.Background(), 42, 7)
+			require.NoError(t, err)
+			assert.False(t, mr.PipelineUnknown)
+			assert.Equal(t, tt.status, mr.PipelineStatus)
+			assert.Equal(t, tt.failedJobs, mr.FailedJobs)
+		})
+	}
+}
+
+func TestRepository_ListMergeRequests_Filtered(t *testing.T) {
+	var queries []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		_, _ = w.Write([]byte(`{"items": []}`))
+	})
+
+	repo := newTestRepository(t, mux)
+
+	draft := false
+	createdAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mrs, err := repo.ListMergeRequests(context.Background(), &domain.MergeRequestQuery{
+		State:        domain.StateOpened,
+		Scope:        domain.ScopeAll,
+		Author:       "alice",
+		Project:      "owner/repo",
+		Labels:       []string{"needs review"},
+		Milestone:    "v1",
+		Draft:        &draft,
+		SourceBranch: "feature",
+		TargetBranch: "main",
+		CreatedAfter: &createdAfter,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, mrs)
+
+	assert.Equal(t, []string{
+		`is:pr is:open author:alice repo:owner/repo label:"needs review" milestone:"v1" draft:false head:feature base:main ` +
+			`created:>=2025-01-01T00:00:00Z`,
+	}, queries, "filters must not be resolved against the team")
+}
+
+func TestRepository_ListMergeRequests_FilteredTeam(t *testing.T) {
+	var queries []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1, "login": "alice"}`))
+	})
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		_, _ = w.Write([]byte(`{"items": []}`))
+	})
+
+	repo := newTestRepository(t, mux)
+
+	_, err := repo.ListMergeRequests(context.Background(), &domain.MergeRequestQuery{
+		State:  domain.StateOpened,
+		Scope:  domain.ScopeAll,
+		Labels: []string{"bug"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`is:pr is:open (involves:alice OR review-requested:alice) label:"bug"`,
+	}, queries, "labels alone must not search every repository")
+}
+
+func TestRepository_ListMergeRequests_Pages(t *testing.T) {
+	var queries []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1, "login": "alice"}`))
+	})
+	mux.HandleFunc("/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		assert.Equal(t, "true", r.URL.Query().Get("advanced_search"))
+
+		// Every page but the last is full.
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		count := perPageLimit
+		if page == 2 {
+			count = 1
+		}
+		items := make([]string, 0, count)
+		for i := range count {
+			items = append(items, fmt.Sprintf(`{"number": %d, "repository_url": "https://api.github.com/repos/owner/repo"}`,
+				(page-1)*perPageLimit+i+1))
+		}
+		_, _ = fmt.Fprintf(w, `{"total_count": %d, "items": [%s]}`, perPageLimit+1, strings.Join(items, ","))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(pullRequestBody))
+	})
+
+	repo := newTestRepository(t, mux)
+	ctx := context.Background()
+	require.NoError(t, repo.PreloadUsersByUsernames(ctx, []string{"bob", "carol", "dave"}))
+
+	mrs, err := repo.ListMergeRequests(ctx, &domain.MergeRequestQuery{State: domain.StateOpened, Scope: domain.ScopeAll})
+	require.NoError(t, err)
+	assert.Len(t, mrs, perPageLimit+1, "every page is read")
+
+	assert.Equal(t, []string{
+		"is:pr is:open (involves:alice OR review-requested:alice OR involves:bob OR review-requested:bob OR " +
+			"involves:carol OR review-requested:carol)",
+		"is:pr is:open (involves:alice OR review-requested:alice OR involves:bob OR review-requested:bob OR " +
+			"involves:carol OR review-requested:carol)",
+		"is:pr is:open (involves:dave OR review-requested:dave)",
+		"is:pr is:open (involves:dave OR review-requested:dave)",
+	}, queries, "a query takes at most five operators")
+}
+
+func TestRepository_GetMergeRequestApprovals(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repositories/42/pulls/7/reviews", func(w http.ResponseWriter, r *http.Request) {
+		// The reviews come in two pages, linked from the first.
+		if r.URL.Query().Get("page") != "2" {
+			next := "http://" + r.Host + "/repositories/42/pulls/7/reviews?page=2&per_page=100"
+			w.Header().Set("Link", `<`+next+`>; rel="next", <`+next+`>; rel="last"`)
+			_, _ = w.Write([]byte(`[
+				{"user": {"id": 1, "login": "alice"}, "state": "APPROVED"},
+				{"user": {"id": 2, "login": "bob"}, "state": "APPROVED"}
+			]`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`[
+			{"user": {"id": 2, "login": "bob"}, "state": "CHANGES_REQUESTED"},
+			{"user": {"id": 1, "login": "alice"}, "state": "COMMENTED"}
+		]`))
+	})
+
+	repo := newTestRepository(t, mux)
+
+	approvals, err := repo.GetMergeRequestApprovals(context.Background(), 42, 7)
+	require.NoError(t, err)
+	require.Len(t, approvals.ApprovedBy, 1)
+	assert.Equal(t, "alice", approvals.ApprovedBy[0].Username, "reviews of every page count")
+	assert.Equal(t, 1, approvals.Left, "the default number of approvals applies")
+}
+
+func TestRepository_ListMergeRequestDiscussions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repositories/42/issues/7/comments", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 1, "user": {"id": 1, "login": "alice"}, "body": "Looks good"}]`))
+	})
+	mux.HandleFunc("/repositories/42/pulls/7/comments", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id": 2, "user": {"id": 2, "login": "bob"}, "body": "Rename this",
+				"html_url": "https://github.com/owner/repo/pull/7#discussion_r2"},
+			{"id": 3, "in_reply_to_id": 2, "user": {"id": 9, "login": "author"}, "body": "Done"},
+			{"id": 4, "user": {"id": 2, "login": "bob"}, "body": "Typo"}
+		]`))
+	})
+
+	discussions, err := newTestRepository(t, mux).ListMergeRequestDiscussions(context.Background(), 42, 7)
+	require.NoError(t, err)
+	require.Len(t, discussions, 3)
+
+	assert.Len(t, discussions[0].Notes, 1)
+	require.Len(t, discussions[1].Notes, 2, "replies belong to the thread of the comment they reply to")
+	assert.Equal(t, "author", discussions[1].LastNote().Author.Username)
+	assert.Equal(t, "https://github.com/owner/repo/pull/7#discussion_r2", discussions[1].Notes[0].WebURL)
+	assert.Equal(t, "Typo", discussions[2].Notes[0].Body)
+}
+
+func TestRepository_UpdateMergeRequest(t *testing.T) {
+	var assignees, reviewers map[string][]string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/user/1", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1, "login": "alice"}`))
+	})
+	mux.HandleFunc("/user/2", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 2, "login": "bob"}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/issues/7", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&assignees))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7/requested_reviewers", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&reviewers))
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	repo := newTestRepository(t, mux)
+	assigneeID := 1
+
+	require.NoError(t, repo.UpdateMergeRequest(context.Background(), 42, 7, &assigneeID, []int{2}))
+	assert.Equal(t, map[string][]string{"assignees": {"alice"}}, assignees)
+	assert.Equal(t, map[string][]string{"reviewers": {"bob"}}, reviewers)
+}
+
+func TestRepository_ApproveMergeRequest(t *testing.T) {
+	var review map[string]string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7/reviews", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	require.NoError(t, newTestRepository(t, mux).ApproveMergeRequest(context.Background(), 42, 7))
+	assert.Equal(t, map[string]string{"event": "APPROVE"}, review)
+}
+
+func TestRepository_UnapproveMergeRequest(t *testing.T) {
+	var dismissal map[string]string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 1, "login": "alice"}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7/reviews", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id": 10, "user": {"id": 1, "login": "alice"}, "state": "APPROVED"},
+			{"id": 11, "user": {"id": 2, "login": "bob"}, "state": "APPROVED"},
+			{"id": 12, "user": {"id": 1, "login": "alice"}, "state": "APPROVED"}
+		]`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7/reviews/12/dismissals", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&dismissal))
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	require.NoError(t, newTestRepository(t, mux).UnapproveMergeRequest(context.Background(), 42, 7))
+	assert.Equal(t, "DISMISS", dismissal["event"])
+}
+
+func TestRepository_MergeMergeRequest(t *testing.T) {
+	var (
+		merge         map[string]string
+		deletedBranch bool
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"number": 7, "head": {"ref": "feature", "repo": {"id": 42}},
+			"base": {"ref": "main", "repo": {"id": 42, "full_name": "owner/repo"}}}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/7/merge", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&merge))
+		_, _ = w.Write([]byte(`{"merged": true}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/git/refs/heads/feature", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deletedBranch = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	repo := newTestRepository(t, mux)
+	ctx := context.Background()
+
+	require.NoError(t, repo.MergeMergeRequest(ctx, 42, 7, &domain.MergeOptions{Squash: true, RemoveSourceBranch: true}))
+	assert.Equal(t, map[string]string{"merge_method": "squash"}, merge)
+	assert.True(t, deletedBranch)
+
+	err := repo.MergeMergeRequest(ctx, 42, 7, &domain.MergeOptions{WhenPipelineSucceeds: true})
+	assert.ErrorIs(t, err, ErrAutoMergeUnsupported)
+}
+
+func TestMergeStatus(t *testing.T) {
+	assert.Equal(t, domain.MergeStatusConflict, mergeStatus("dirty"))
+	assert.Equal(t, domain.MergeStatusNeedRebase, mergeStatus("behind"))
+	assert.Equal(t, domain.MergeStatusMergeable, mergeStatus("unstable"))
+	assert.Empty(t, mergeStatus("unknown"))
+}
+
+func TestRepository_RebaseMergeRequest(t *testing.T) {
+	err := newTestRepository(t, http.NewServeMux()).RebaseMergeRequest(context.Background(), 42, 7)
+	require.ErrorIs(t, err, ErrRebaseUnsupported)
+}
+
+func TestRepository_CreateMergeRequest(t *testing.T) {
+	var (
+		created map[string]any
+		labels  map[string][]string
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repositories/42", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"id": 42, "full_name": "owner/repo"}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		_, _ = w.Write([]byte(`{"id": 100, "number": 7, "title": "Add login",
+			"html_url": "https://github.com/owner/repo/pull/7",
+			"head": {"ref": "feature"}, "base": {"ref": "main", "repo": {"id": 42, "full_name": "owner/repo"}}}`))
+	})
+	mux.HandleFunc("/repos/owner/repo/issues/7/labels", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&labels))
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	mr, err := newTestRepository(t, mux).CreateMergeRequest(context.Background(), 42, &domain.NewMergeRequest{
+		Title:        "Add login",
+		SourceBranch: "feature",
+		TargetBranch: "main",
+		Draft:        true,
+		Labels:       []string{"backend"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"title": "Add login", "body": "", "head": "feature", "base": "main", "draft": true,
+	}, created)
+	assert.Equal(t, map[string][]string{"labels": {"backend"}}, labels)
+	assert.Equal(t, 7, mr.IID)
+	assert.Equal(t, "https://github.com/owner/repo/pull/7", mr.WebURL)
+}
+
+func TestRepository_GetTokenInfo(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+		w.Header().Set("GitHub-Authentication-Token-Expiration", "2025-06-30 12:00:00 UTC")
+		_, _ = w.Write([]byte(`{"id": 1, "login": "alice"}`))
+	})
+
+	info, err := newTestRepository(t, mux).GetTokenInfo(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "alice", info.User.Username)
+	assert.Equal(t, []string{"repo", "read:org"}, info.Scopes)
+	require.NotNil(t, info.ExpiresAt)
+	assert.Equal(t, time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC), info.ExpiresAt.UTC())
+}
+
+func TestRepository_APIError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message": "Bad credentials"}`))
+	})
+
+	repo := newTestRepository(t, mux)
+
+	_, err := repo.GetCurrentUser(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Bad credentials")
+}
+
+func TestEvent_ToDomain(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		event    event
+		validate func(*testing.T, *event)
+	}{
+		{
+			name: "push",
+			event: event{
+				ID: "1", Type: "PushEvent", CreatedAt: createdAt,
+				Repo:    eventRepo{ID: 42, Name: "owner/repo"},
+				Payload: json.RawMessage(`{"ref": "refs/heads/main", "size": 2, "commits": [{"message": "a"}, {"message": "b\n\nbody"}]}`),
+			},
+			validate: func(t *testing.T, e *event) {
+				got := e.toDomain()
+				require.NotNil(t, got)
+				assert.Equal(t, "owner/repo", got.ProjectPath)
+				assert.Equal(t, "refs/heads/main", got.PushRef)
+				assert.Equal(t, 2, got.CommitCount)
+				assert.Equal(t, "b", got.CommitTitle)
+			},
+		},
+		{
+			name: "approving review",
+			event: event{
+				ID: "2", Type: "PullRequestReviewEvent", CreatedAt: createdAt,
+				Payload: json.RawMessage(`{"review": {"state": "approved"}, "pull_request": {"number": 7, "title": "Add feature"}}`),
+			},
+			validate: func(t *testing.T, e *event) {
+				got := e.toDomain()
+				require.NotNil(t, got)
+				assert.Equal(t, "approved", got.Action)
+				assert.Equal(t, "MergeRequest", got.TargetType)
+				assert.Equal(t, 7, got.TargetID)
+			},
+		},
+		{
+			name:  "unsupported",
+			event: event{ID: "3", Type: "WatchEvent", Payload: json.RawMessage(`{}`)},
+			validate: func(t *testing.T, e *event) {
+				assert.Nil(t, e.toDomain())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.validate(t, &tt.event)
+		})
+	}
+}
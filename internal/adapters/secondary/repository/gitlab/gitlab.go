@@ -2,7 +2,9 @@ package gitlab
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,6 +17,8 @@ import (
 
 const (
 	perPageLimit = 100
+	// fetchLimit caps the requests made in parallel for the merge requests of a listing.
+	fetchLimit = 8
 
 	// Event target types.
 	targetTypeMergeRequest = "merge_request" // Standard format
@@ -99,6 +103,58 @@ func (r *Repository) FetchUsersByUsernames(ctx context.Context, usernames []stri
 	return domainUsers, nil
 }
 
+// ListGroupMembers lists the active members of a group or subgroup, including
+// members inherited from parent groups.
+func (r *Repository) ListGroupMembers(ctx context.Context, groupPath string) ([]*domain.User, error) {
+	members, _, err := r.client.Groups.ListAllGroupMembers(groupPath, &gitlab.ListGroupMembersOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: perPageLimit,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group members: %w", err)
+	}
+
+	var errg errgroup.Group
+	var mu sync.Mutex
+	domainUsers := make([]*domain.User, 0, len(members))
+
+	for _, member := range members {
+		if member.State != "active" {
+			continue
+		}
+
+		errg.Go(func() error {
+			domainUser := &domain.User{
+				ID:       member.ID,
+				Username: member.Username,
+				Email:    member.Email,
+			}
+			status, err := r.getUserStatus(ctx, member.ID)
+			if err != nil {
+				return err
+			}
+			domainUser.Status = status
+
+			mu.Lock()
+			domainUsers = append(domainUsers, domainUser)
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	if err := errg.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to list group members: %w", err)
+	}
+
+	sort.Slice(domainUsers, func(i, j int) bool {
+		return domainUsers[i].Username < domainUsers[j].Username
+	})
+
+	return domainUsers, nil
+}
+
 // getUserStatus gets a user's status from GitLab.
 func (r *Repository) getUserStatus(_ context.Context, userID int) (domain.UserStatus, error) {
 	status, _, err := r.client.Users.GetUserStatus(userID)
@@ -133,6 +189,31 @@ func (r *Repository) getUser(ctx context.Context, userID int) (*domain.User, err
 	return domainUser, nil
 }
 
+// GetTokenInfo gets the personal access token in use and its owner.
+func (r *Repository) GetTokenInfo(ctx context.Context) (*domain.TokenInfo, error) {
+	token, _, err := r.client.PersonalAccessTokens.GetSinglePersonalAccessToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal access token: %w", err)
+	}
+
+	user, err := r.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &domain.TokenInfo{
+		User:   user,
+		Name:   token.Name,
+		Scopes: token.Scopes,
+	}
+	if token.ExpiresAt != nil {
+		expiresAt := time.Time(*token.ExpiresAt)
+		info.ExpiresAt = &expiresAt
+	}
+
+	return info, nil
+}
+
 // GetUserByUsername gets a user by username.
 func (r *Repository) GetUserByUsername(_ context.Context, username string) (*domain.User, error) {
 	return nil, fmt.Errorf("user not found: %s (GitLab API doesn't support fetching by username)", username)
@@ -233,18 +314,61 @@ func (r *Repository) GetProject(_ context.Context, path string) (*domain.Project
 	}
 
 	return &domain.Project{
-		ID:   project.ID,
-		Path: projectPath,
+		ID:            project.ID,
+		Path:          projectPath,
+		DefaultBranch: project.DefaultBranch,
 	}, nil
 }
 
 // GetMergeRequest retrieves a merge request by project ID and MR ID.
 func (r *Repository) GetMergeRequest(ctx context.Context, projectID, mrID int) (*domain.MergeRequest, error) {
-	mr, _, err := r.client.MergeRequests.GetMergeRequest(projectID, mrID, nil)
+	mr, _, err := r.client.MergeRequests.GetMergeRequest(projectID, mrID, &gitlab.GetMergeRequestsOptions{
+		IncludeDivergedCommitsCount: pointerOf(true),
+		IncludeRebaseInProgress:     pointerOf(true),
+	}, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merge request: %w", err)
 	}
 
+	return r.toDomainMR(ctx, mr)
+}
+
+// CreateMergeRequest creates a merge request. Drafts are marked with the
+// "Draft:" title prefix.
+func (r *Repository) CreateMergeRequest(
+	ctx context.Context,
+	projectID int,
+	opts *domain.NewMergeRequest,
+) (*domain.MergeRequest, error) {
+	title := opts.Title
+	if opts.Draft {
+		title = "Draft: " + title
+	}
+
+	createOpts := &gitlab.CreateMergeRequestOptions{
+		Title:        &title,
+		Description:  &opts.Description,
+		SourceBranch: &opts.SourceBranch,
+		TargetBranch: &opts.TargetBranch,
+		AssigneeID:   opts.AssigneeID,
+	}
+	if len(opts.Labels) > 0 {
+		labels := gitlab.LabelOptions(opts.Labels)
+		createOpts.Labels = &labels
+	}
+	if len(opts.ReviewerIDs) > 0 {
+		createOpts.ReviewerIDs = &opts.ReviewerIDs
+	}
+
+	mr, _, err := r.client.MergeRequests.CreateMergeRequest(projectID, createOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+
+	return r.toDomainMR(ctx, mr)
+}
+
+func (r *Repository) toDomainMR(ctx context.Context, mr *gitlab.MergeRequest) (*domain.MergeRequest, error) {
 	userIDs := []int{mr.Author.ID}
 	if mr.Assignee != nil {
 		userIDs = append(userIDs, mr.Assignee.ID)
@@ -279,6 +403,19 @@ func (r *Repository) GetMergeRequest(ctx context.Context, projectID, mrID int) (
 		ProjectID:    mr.ProjectID,
 		Draft:        mr.Draft,
 		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		Labels:       mr.Labels,
+		State:        mr.State,
+		HeadRef:      fmt.Sprintf("refs/merge-requests/%d/head", mr.IID),
+		Fork:         mr.SourceProjectID != mr.TargetProjectID,
+		CommentCount: mr.UserNotesCount,
+		ChangedFiles: changedFiles(mr.ChangesCount),
+
+		DetailedMergeStatus: mr.DetailedMergeStatus,
+		HasConflicts:        mr.HasConflicts,
+		DivergedCommits:     mr.DivergedCommitsCount,
+		RebaseInProgress:    mr.RebaseInProgress,
+		MergeError:          mr.MergeError,
 	}
 
 	if mr.Assignee != nil {
@@ -286,28 +423,48 @@ func (r *Repository) GetMergeRequest(ctx context.Context, projectID, mrID int) (
 			domainMR.Assignee = assignee
 		}
 	}
+	if mr.Milestone != nil {
+		domainMR.Milestone = mr.Milestone.Title
+	}
+
+	if mr.HeadPipeline != nil {
+		domainMR.PipelineStatus = mr.HeadPipeline.Status
+		if err := r.setFailedJobs(ctx, domainMR, mr.HeadPipeline.ID); err != nil {
+			return nil, err
+		}
+	}
 
 	return domainMR, nil
 }
 
-// ListMergeRequests lists merge requests with the given state and scope.
+// ListMergeRequests lists the merge requests selected by query. All filters
+// are applied by GitLab, within a project or a group when the query names
+// one. Every page is read when the query names a project, a group or a user.
+// Sorting by size fetches each merge request, listings leave out the number
+// of changed files.
 func (r *Repository) ListMergeRequests(
 	ctx context.Context,
-	state string,
-	scope ...string,
+	query *domain.MergeRequestQuery,
 ) ([]*domain.MergeRequest, error) {
-	opts := &gitlab.ListMergeRequestsOptions{
-		ListOptions: gitlab.ListOptions{
-			PerPage: perPageLimit,
-		},
-		State: &state,
-	}
-	if len(scope) > 0 {
-		opts.Scope = &scope[0]
-	}
-	mrs, _, err := r.client.MergeRequests.ListMergeRequests(opts)
+	opts, err := r.listOptions(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list merge requests: %w", err)
+		return nil, err
+	}
+
+	var mrs []*gitlab.BasicMergeRequest
+	for {
+		page, resp, err := r.listMergeRequestsPage(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merge requests: %w", err)
+		}
+		mrs = append(mrs, page...)
+
+		// Without a project, a group or a user, the listing may span the
+		// whole instance, so it stops at the first page.
+		if resp.NextPage == 0 || !query.HasOwner() {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
 
 	userIDs := make(map[int]struct{})
@@ -331,11 +488,219 @@ func (r *Repository) ListMergeRequests(
 		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
 
-	return r.convertToDomainMRs(mrs, users), nil
+	domainMRs := r.convertToDomainMRs(mrs, users)
+	r.setPipelines(ctx, domainMRs)
+	if query.Sort == domain.SortSize {
+		if err := r.setChangedFiles(ctx, domainMRs); err != nil {
+			return nil, err
+		}
+	}
+
+	return domainMRs, nil
+}
+
+// listMergeRequestsPage reads a page of the merge requests of the project or
+// the group of the query, or of the instance when it names neither.
+func (r *Repository) listMergeRequestsPage(
+	ctx context.Context,
+	query *domain.MergeRequestQuery,
+	opts *gitlab.ListMergeRequestsOptions,
+) ([]*gitlab.BasicMergeRequest, *gitlab.Response, error) {
+	switch {
+	case query.Project != "":
+		return r.client.MergeRequests.ListProjectMergeRequests(query.Project, &gitlab.ListProjectMergeRequestsOptions{
+			ListOptions:      opts.ListOptions,
+			State:            opts.State,
+			Milestone:        opts.Milestone,
+			Labels:           opts.Labels,
+			CreatedAfter:     opts.CreatedAfter,
+			CreatedBefore:    opts.CreatedBefore,
+			Scope:            opts.Scope,
+			AuthorUsername:   opts.AuthorUsername,
+			AssigneeID:       opts.AssigneeID,
+			ReviewerUsername: opts.ReviewerUsername,
+			SourceBranch:     opts.SourceBranch,
+			TargetBranch:     opts.TargetBranch,
+			Draft:            opts.Draft,
+		}, gitlab.WithContext(ctx))
+	case query.Group != "":
+		return r.client.MergeRequests.ListGroupMergeRequests(query.Group, &gitlab.ListGroupMergeRequestsOptions{
+			ListOptions:      opts.ListOptions,
+			State:            opts.State,
+			Milestone:        opts.Milestone,
+			Labels:           opts.Labels,
+			CreatedAfter:     opts.CreatedAfter,
+			CreatedBefore:    opts.CreatedBefore,
+			Scope:            opts.Scope,
+			AuthorUsername:   opts.AuthorUsername,
+			AssigneeID:       opts.AssigneeID,
+			ReviewerUsername: opts.ReviewerUsername,
+			SourceBranch:     opts.SourceBranch,
+			TargetBranch:     opts.TargetBranch,
+			Draft:            opts.Draft,
+		}, gitlab.WithContext(ctx))
+	default:
+		return r.client.MergeRequests.ListMergeRequests(opts, gitlab.WithContext(ctx))
+	}
 }
 
-// GetMergeRequestApprovals retrieves approvals for a merge request.
-func (r *Repository) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) ([]*domain.User, error) {
+// listOptions converts a query to the options of the merge request listings.
+// Projects and groups list all of their merge requests unless scoped.
+func (r *Repository) listOptions(
+	ctx context.Context,
+	query *domain.MergeRequestQuery,
+) (*gitlab.ListMergeRequestsOptions, error) {
+	state := query.State
+	if state == "" {
+		state = domain.ScopeAll
+	}
+
+	opts := &gitlab.ListMergeRequestsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: perPageLimit,
+		},
+		State:         &state,
+		CreatedAfter:  query.CreatedAfter,
+		CreatedBefore: query.CreatedBefore,
+	}
+
+	switch {
+	case query.Scope == domain.ScopeAll && (query.Project != "" || query.Group != ""):
+	case query.Scope != "":
+		opts.Scope = &query.Scope
+	case query.Project != "" || query.Group != "":
+	case query.HasFilters():
+		opts.Scope = gitlab.Ptr(domain.ScopeAll)
+	}
+	if query.Author != "" {
+		opts.AuthorUsername = &query.Author
+	}
+	if query.Reviewer != "" {
+		opts.ReviewerUsername = &query.Reviewer
+	}
+	if query.Assignee != "" {
+		// Merge requests are only filtered by assignee ID.
+		users, _, err := r.client.Users.ListUsers(&gitlab.ListUsersOptions{Username: &query.Assignee},
+			gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user %s: %w", query.Assignee, err)
+		}
+		if len(users) == 0 {
+			return nil, fmt.Errorf("user not found: %s", query.Assignee)
+		}
+		opts.AssigneeID = gitlab.AssigneeID(users[0].ID)
+	}
+	if len(query.Labels) > 0 {
+		labels := gitlab.LabelOptions(query.Labels)
+		opts.Labels = &labels
+	}
+	if query.Milestone != "" {
+		opts.Milestone = &query.Milestone
+	}
+	if query.Draft != nil {
+		opts.Draft = query.Draft
+	}
+	if query.SourceBranch != "" {
+		opts.SourceBranch = &query.SourceBranch
+	}
+	if query.TargetBranch != "" {
+		opts.TargetBranch = &query.TargetBranch
+	}
+
+	return opts, nil
+}
+
+// setChangedFiles sets the number of files changed by each merge request,
+// which merge request listings leave out, a few requests at a time.
+func (r *Repository) setChangedFiles(ctx context.Context, mrs []*domain.MergeRequest) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(fetchLimit)
+
+	for _, mr := range mrs {
+		g.Go(func() error {
+			full, _, err := r.client.MergeRequests.GetMergeRequest(mr.ProjectID, mr.IID, nil,
+				gitlab.WithContext(ctx))
+			if err != nil {
+				return fmt.Errorf("failed to get %s: %w", mr.Key(), err)
+			}
+			mr.ChangedFiles = changedFiles(full.ChangesCount)
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("failed to fetch changed files: %w", err)
+	}
+
+	return nil
+}
+
+// changedFiles parses the number of changed files of a merge request, which
+// GitLab caps as in "1000+".
+func changedFiles(changesCount string) int {
+	n, _ := strconv.Atoi(strings.TrimSuffix(changesCount, "+"))
+
+	return n
+}
+
+// setPipelines sets the status of the latest pipeline of each merge request,
+// which merge request listings leave out, a few requests at a time. A failed
+// lookup leaves the status of that merge request empty rather than failing
+// the listing.
+func (r *Repository) setPipelines(ctx context.Context, mrs []*domain.MergeRequest) {
+	var g errgroup.Group
+	g.SetLimit(fetchLimit)
+
+	for _, mr := range mrs {
+		g.Go(func() error {
+			pipelines, _, err := r.client.MergeRequests.ListMergeRequestPipelines(mr.ProjectID, mr.IID,
+				gitlab.WithContext(ctx))
+			// Pipelines are listed newest first.
+			if err != nil || len(pipelines) == 0 {
+				//nolint: nilerr // Intentionally ignore errors to not fail the listing
+				return nil
+			}
+
+			mr.PipelineStatus = pipelines[0].Status
+			// Without the failed jobs, the status still tells that the pipeline failed.
+			_ = r.setFailedJobs(ctx, mr, pipelines[0].ID)
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+}
+
+// setFailedJobs sets the names of the jobs that failed the pipeline of a
+// merge request whose pipeline failed.
+func (r *Repository) setFailedJobs(ctx context.Context, mr *domain.MergeRequest, pipelineID int) error {
+	if !mr.HasFailedPipeline() {
+		return nil
+	}
+
+	jobs, _, err := r.client.Jobs.ListPipelineJobs(mr.ProjectID, pipelineID, &gitlab.ListJobsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: perPageLimit},
+		Scope:       &[]gitlab.BuildStateValue{gitlab.Failed},
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to list failed jobs of %s: %w", mr.Key(), err)
+	}
+
+	for _, job := range jobs {
+		if !job.AllowFailure {
+			mr.FailedJobs = append(mr.FailedJobs, job.Name)
+		}
+	}
+
+	return nil
+}
+
+// GetMergeRequestApprovals retrieves approvals for a merge request, with the
+// number of approvals required and left as computed by GitLab from the
+// approval rules.
+func (r *Repository) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) (*domain.Approvals, error) {
 	approvals, _, err := r.client.MergeRequests.GetMergeRequestApprovals(projectID, mrID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merge request approvals: %w", err)
@@ -350,7 +715,113 @@ func (r *Repository) GetMergeRequestApprovals(ctx context.Context, projectID, mr
 		users = append(users, user)
 	}
 
-	return users, nil
+	return &domain.Approvals{
+		ApprovedBy: users,
+		Required:   approvals.ApprovalsRequired,
+		Left:       approvals.ApprovalsLeft,
+	}, nil
+}
+
+// GetMergeRequestApprovalRules retrieves the approval rules of a merge
+// request. Approval rules are a GitLab Premium feature, none are returned
+// when the instance does not provide them.
+func (r *Repository) GetMergeRequestApprovalRules(
+	ctx context.Context,
+	projectID, mrID int,
+) ([]*domain.ApprovalRule, error) {
+	state, _, err := r.client.MergeRequestApprovals.GetApprovalState(projectID, mrID, gitlab.WithContext(ctx))
+	if errors.Is(err, gitlab.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request approval state: %w", err)
+	}
+
+	rules := make([]*domain.ApprovalRule, 0, len(state.Rules))
+	for _, rule := range state.Rules {
+		rules = append(rules, &domain.ApprovalRule{
+			Name:              rule.Name,
+			Type:              rule.RuleType,
+			Required:          rule.ApprovalsRequired,
+			Approved:          rule.Approved,
+			ApprovedBy:        toDomainBasicUsers(rule.ApprovedBy),
+			EligibleApprovers: toDomainBasicUsers(rule.EligibleApprovers),
+		})
+	}
+
+	return rules, nil
+}
+
+// toDomainBasicUsers converts users embedded in other resources, which only
+// carry their ID and username.
+func toDomainBasicUsers(users []*gitlab.BasicUser) []*domain.User {
+	domainUsers := make([]*domain.User, 0, len(users))
+	for _, user := range users {
+		domainUsers = append(domainUsers, &domain.User{ID: user.ID, Username: user.Username})
+	}
+
+	return domainUsers
+}
+
+// ListMergeRequestDiscussions retrieves the comment threads of a merge
+// request, leaving out system notes. Notes link to their anchor relative to
+// the merge request.
+func (r *Repository) ListMergeRequestDiscussions(
+	ctx context.Context,
+	projectID, mrID int,
+) ([]*domain.Discussion, error) {
+	opts := &gitlab.ListMergeRequestDiscussionsOptions{PerPage: perPageLimit}
+
+	var discussions []*gitlab.Discussion
+	for {
+		page, resp, err := r.client.Discussions.ListMergeRequestDiscussions(projectID, mrID, opts,
+			gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merge request discussions: %w", err)
+		}
+		discussions = append(discussions, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	domainDiscussions := make([]*domain.Discussion, 0, len(discussions))
+	for _, discussion := range discussions {
+		domainDiscussion := &domain.Discussion{ID: discussion.ID, Resolved: true}
+
+		for _, note := range discussion.Notes {
+			if note.System {
+				continue
+			}
+
+			// A thread is resolved once all of its resolvable notes are.
+			if note.Resolvable {
+				domainDiscussion.Resolvable = true
+				domainDiscussion.Resolved = domainDiscussion.Resolved && note.Resolved
+			}
+
+			domainNote := &domain.Note{
+				ID:     note.ID,
+				Author: &domain.User{ID: note.Author.ID, Username: note.Author.Username},
+				Body:   note.Body,
+				WebURL: "#note_" + strconv.Itoa(note.ID),
+			}
+			if note.CreatedAt != nil {
+				domainNote.CreatedAt = *note.CreatedAt
+			}
+			domainDiscussion.Notes = append(domainDiscussion.Notes, domainNote)
+		}
+
+		if len(domainDiscussion.Notes) == 0 {
+			continue
+		}
+		domainDiscussion.Resolved = domainDiscussion.Resolvable && domainDiscussion.Resolved
+		domainDiscussions = append(domainDiscussions, domainDiscussion)
+	}
+
+	return domainDiscussions, nil
 }
 
 // GetUser retrieves a user by ID (not part of Repository interface but used internally).
@@ -430,6 +901,52 @@ func (r *Repository) UpdateMergeRequest(
 	return nil
 }
 
+// ApproveMergeRequest approves a merge request as the current user.
+func (r *Repository) ApproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	_, _, err := r.client.MergeRequestApprovals.ApproveMergeRequest(projectID, mrID, &gitlab.ApproveMergeRequestOptions{},
+		gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to approve merge request: %w", err)
+	}
+
+	return nil
+}
+
+// UnapproveMergeRequest withdraws the approval of the current user.
+func (r *Repository) UnapproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	if _, err := r.client.MergeRequestApprovals.UnapproveMergeRequest(projectID, mrID,
+		gitlab.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to unapprove merge request: %w", err)
+	}
+
+	return nil
+}
+
+// MergeMergeRequest merges a merge request, or sets it to merge when its
+// pipeline succeeds.
+func (r *Repository) MergeMergeRequest(ctx context.Context, projectID, mrID int, opts *domain.MergeOptions) error {
+	_, _, err := r.client.MergeRequests.AcceptMergeRequest(projectID, mrID, &gitlab.AcceptMergeRequestOptions{
+		Squash:                    &opts.Squash,
+		ShouldRemoveSourceBranch:  &opts.RemoveSourceBranch,
+		MergeWhenPipelineSucceeds: &opts.WhenPipelineSucceeds,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to merge merge request: %w", err)
+	}
+
+	return nil
+}
+
+// RebaseMergeRequest starts rebasing the source branch of a merge request
+// onto its target branch on the server. GetMergeRequest tells when it is done.
+func (r *Repository) RebaseMergeRequest(ctx context.Context, projectID, mrID int) error {
+	if _, err := r.client.MergeRequests.RebaseMergeRequest(projectID, mrID, nil, gitlab.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to rebase merge request: %w", err)
+	}
+
+	return nil
+}
+
 // GetUserEvents retrieves user events within the specified time range.
 func (r *Repository) GetUserEvents(
 	ctx context.Context,
@@ -598,6 +1115,15 @@ func (r *Repository) convertToDomainMRs(
 			ProjectID:    mr.ProjectID,
 			Draft:        mr.Draft,
 			SourceBranch: mr.SourceBranch,
+			TargetBranch: mr.TargetBranch,
+			Labels:       mr.Labels,
+			State:        mr.State,
+			HeadRef:      fmt.Sprintf("refs/merge-requests/%d/head", mr.IID),
+			Fork:         mr.SourceProjectID != mr.TargetProjectID,
+			CommentCount: mr.UserNotesCount,
+
+			DetailedMergeStatus: mr.DetailedMergeStatus,
+			HasConflicts:        mr.HasConflicts,
 		}
 
 		if mr.Assignee != nil {
@@ -605,6 +1131,9 @@ func (r *Repository) convertToDomainMRs(
 				domainMR.Assignee = assignee
 			}
 		}
+		if mr.Milestone != nil {
+			domainMR.Milestone = mr.Milestone.Title
+		}
 
 		domainMRs = append(domainMRs, domainMR)
 	}
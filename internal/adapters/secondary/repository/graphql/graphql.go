@@ -0,0 +1,761 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	restrepo "github.com/denchenko/gg/internal/adapters/secondary/repository/gitlab"
+	"github.com/denchenko/gg/internal/core/domain"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	perPageLimit = 100
+
+	userFields     = `id username publicEmail status { message availability }`
+	pageInfoFields = `endCursor hasNextPage`
+
+	mergeRequestBaseFields = `
+		id iid title description webUrl createdAt updatedAt draft state sourceBranch targetBranch projectId sourceProjectId
+		userNotesCount
+		detailedMergeStatus conflicts rebaseInProgress mergeError
+		labels { nodes { title } }
+		milestone { title }
+		diffStatsSummary { fileCount }
+		author { ` + userFields + ` }
+		assignees { nodes { ` + userFields + ` } }
+		reviewers { nodes { ` + userFields + ` } }`
+
+	// mergeRequestListFields are queried for listings, which leave out the
+	// jobs of the head pipeline: they are costly for the server to resolve
+	// on every merge request of a page. Approvals are added where approval
+	// rules are supported, sparing a query per merge request.
+	mergeRequestListFields = mergeRequestBaseFields + `
+		headPipeline { status }`
+	mergeRequestFields = mergeRequestBaseFields + `
+		headPipeline { status jobs(statuses: [FAILED], retried: false) { nodes { name allowFailure } } }`
+
+	approvalFields = `approvedBy { nodes { ` + userFields + ` } }`
+
+	// approvalRuleFields are only available on GitLab Enterprise Edition,
+	// whatever the tier.
+	approvalRuleFields = ` approvalsRequired approvalsLeft`
+)
+
+// Repository implements the app.Repository interface on top of the GitLab
+// GraphQL API. Read paths that benefit from batching (merge requests together
+// with their reviewers and user statuses, approvals with their approvers'
+// statuses) use GraphQL; everything else, including all writes, is delegated
+// to the REST repository.
+type Repository struct {
+	*restrepo.Repository
+
+	client *gitlab.Client
+
+	mu        sync.Mutex
+	usernames []string
+	// approvalRules tells whether the server supports approvalRuleFields,
+	// nil until it has been asked.
+	approvalRules *bool
+}
+
+// NewRepository creates a new GraphQL repository instance.
+func NewRepository(client *gitlab.Client, rest *restrepo.Repository) *Repository {
+	return &Repository{
+		Repository: rest,
+		client:     client,
+	}
+}
+
+// PreloadUsersByUsernames remembers the usernames so that listing merge
+// requests with the "all" scope covers every team member.
+func (r *Repository) PreloadUsersByUsernames(_ context.Context, usernames []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.usernames = append([]string(nil), usernames...)
+
+	return nil
+}
+
+// FetchUsersByUsernames fetches users together with their status, a page of
+// users per query.
+func (r *Repository) FetchUsersByUsernames(ctx context.Context, usernames []string) ([]*domain.User, error) {
+	if len(usernames) == 0 {
+		return []*domain.User{}, nil
+	}
+
+	query := fmt.Sprintf(`query($usernames: [String!], $after: String) {
+		users(usernames: $usernames, first: %d, after: $after) { nodes { %s } pageInfo { %s } } }`,
+		perPageLimit, userFields, pageInfoFields)
+
+	users := make([]*domain.User, 0, len(usernames))
+	variables := map[string]any{"usernames": usernames}
+
+	for {
+		var data struct {
+			Users struct {
+				Nodes    []*user  `json:"nodes"`
+				PageInfo pageInfo `json:"pageInfo"`
+			} `json:"users"`
+		}
+
+		if err := r.do(ctx, query, variables, &data); err != nil {
+			return nil, fmt.Errorf("failed to fetch users: %w", err)
+		}
+
+		for _, u := range data.Users.Nodes {
+			users = append(users, u.toDomain())
+		}
+
+		if !data.Users.PageInfo.HasNextPage {
+			return users, nil
+		}
+		variables["after"] = data.Users.PageInfo.EndCursor
+	}
+}
+
+// GetUserByUsername gets a user by username.
+func (r *Repository) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	query := fmt.Sprintf(`query($username: String!) { user(username: $username) { %s } }`, userFields)
+
+	var data struct {
+		User *user `json:"user"`
+	}
+
+	if err := r.do(ctx, query, map[string]any{"username": username}, &data); err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if data.User == nil {
+		return nil, fmt.Errorf("user not found: %s", username)
+	}
+
+	return data.User.toDomain(), nil
+}
+
+// GetCurrentUser gets the current authenticated user.
+func (r *Repository) GetCurrentUser(ctx context.Context) (*domain.User, error) {
+	query := fmt.Sprintf(`query { currentUser { %s } }`, userFields)
+
+	var data struct {
+		CurrentUser *user `json:"currentUser"`
+	}
+
+	if err := r.do(ctx, query, nil, &data); err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	if data.CurrentUser == nil {
+		return nil, errors.New("failed to get current user: not authenticated")
+	}
+
+	return data.CurrentUser.toDomain(), nil
+}
+
+// GetMergeRequest retrieves a merge request by project ID and MR ID.
+func (r *Repository) GetMergeRequest(ctx context.Context, projectID, mrID int) (*domain.MergeRequest, error) {
+	mr, err := r.fetchMergeRequest(ctx, projectID, mrID, mergeRequestFields)
+	if err != nil {
+		return nil, err
+	}
+
+	return mr.toDomain(), nil
+}
+
+// GetMergeRequestApprovals retrieves approvals for a merge request, with the
+// approvers' statuses. Servers without approval rules in GraphQL, such as
+// GitLab Community Edition, are asked over REST.
+func (r *Repository) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) (*domain.Approvals, error) {
+	supported, err := r.supportsApprovalRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request approvals: %w", err)
+	}
+	if !supported {
+		approvals, err := r.Repository.GetMergeRequestApprovals(ctx, projectID, mrID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get merge request approvals over REST: %w", err)
+		}
+
+		return approvals, nil
+	}
+
+	mr, err := r.fetchMergeRequest(ctx, projectID, mrID, approvalFields+approvalRuleFields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request approvals: %w", err)
+	}
+
+	return mr.approvals(), nil
+}
+
+// ListMergeRequests lists the merge requests selected by query.
+//
+// GraphQL has no instance-wide merge request listing: merge requests are
+// listed from the project or group of the query, otherwise from its author,
+// assignee or reviewer. Failing those, the "all" scope is resolved to merge
+// requests authored by, assigned to or awaiting review from the current user
+// or any preloaded team member.
+func (r *Repository) ListMergeRequests(
+	ctx context.Context,
+	query *domain.MergeRequestQuery,
+) ([]*domain.MergeRequest, error) {
+	approvalRules, err := r.supportsApprovalRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge requests: %w", err)
+	}
+
+	fields := mergeRequestListFields
+	if approvalRules {
+		fields += " " + approvalFields + approvalRuleFields
+	}
+
+	owners, connections := r.listOwners(query)
+	args := listArgs(query)
+
+	g, gctx := errgroup.WithContext(ctx)
+	results := make([][]*mergeRequest, len(owners))
+
+	for i, owner := range owners {
+		g.Go(func() error {
+			mrs, err := r.listOwnerMergeRequests(gctx, owner, connections, args, fields)
+			if err != nil {
+				return err
+			}
+			results[i] = mrs
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to list merge requests: %w", err)
+	}
+
+	seen := make(map[domain.MergeRequestKey]struct{})
+	domainMRs := make([]*domain.MergeRequest, 0)
+
+	for _, mrs := range results {
+		for _, mr := range mrs {
+			domainMR := mr.toDomain()
+			if _, ok := seen[domainMR.Key()]; ok {
+				continue
+			}
+			seen[domainMR.Key()] = struct{}{}
+			if approvalRules {
+				domainMR.Approvals = mr.approvals()
+			}
+			domainMRs = append(domainMRs, domainMR)
+		}
+	}
+
+	return domainMRs, nil
+}
+
+// owner is a GraphQL field owning merge request connections, such as a
+// project or a user, selected by at most one variable.
+type owner struct {
+	field string
+	// variable is the declaration of $owner, empty for fields without one.
+	variable string
+	value    string
+}
+
+// listOwners returns the owners of the merge requests selected by query and
+// their merge request connections.
+func (r *Repository) listOwners(query *domain.MergeRequestQuery) (owners []owner, connections []string) {
+	switch {
+	case query.Project != "":
+		return []owner{projectOwner(query.Project)}, []string{"mergeRequests"}
+	case query.Group != "":
+		return []owner{groupOwner(query.Group)}, []string{"mergeRequests"}
+	case query.Author != "":
+		return []owner{userOwner(query.Author)}, []string{"authoredMergeRequests"}
+	case query.Assignee != "":
+		return []owner{userOwner(query.Assignee)}, []string{"assignedMergeRequests"}
+	case query.Reviewer != "":
+		return []owner{userOwner(query.Reviewer)}, []string{"reviewRequestedMergeRequests"}
+	}
+
+	switch query.Scope {
+	case domain.ScopeAll:
+		connections = []string{"authoredMergeRequests", "assignedMergeRequests", "reviewRequestedMergeRequests"}
+	case domain.ScopeAssignedToMe:
+		connections = []string{"assignedMergeRequests"}
+	default:
+		connections = []string{"authoredMergeRequests"}
+	}
+
+	owners = []owner{{field: "currentUser"}}
+	if query.Scope == domain.ScopeAll {
+		r.mu.Lock()
+		for _, username := range r.usernames {
+			owners = append(owners, userOwner(username))
+		}
+		r.mu.Unlock()
+	}
+
+	return owners, connections
+}
+
+func projectOwner(path string) owner {
+	return owner{field: "project(fullPath: $owner)", variable: "$owner: ID!", value: path}
+}
+
+func groupOwner(path string) owner {
+	return owner{field: "group(fullPath: $owner)", variable: "$owner: ID!", value: path}
+}
+
+func userOwner(username string) owner {
+	return owner{field: "user(username: $owner)", variable: "$owner: String!", value: username}
+}
+
+// arguments are the arguments of a GraphQL field passed as variables, so that
+// values never end up in the query text.
+type arguments struct {
+	// declarations declare the variables in the operation.
+	declarations []string
+	// args pass the variables to the field.
+	args   []string
+	values map[string]any
+}
+
+func (a *arguments) add(name, typ string, value any) {
+	a.declarations = append(a.declarations, "$"+name+": "+typ)
+	a.args = append(a.args, name+": $"+name)
+	a.values[name] = value
+}
+
+// listArgs converts a query to the arguments of merge request connections.
+// Usernames only filter the connections of projects and groups, the caller
+// filters the others.
+func listArgs(query *domain.MergeRequestQuery) *arguments {
+	args := &arguments{values: make(map[string]any)}
+	if query.State != "" && query.State != domain.ScopeAll {
+		args.add("state", "MergeRequestState", query.State)
+	}
+	if query.Project != "" || query.Group != "" {
+		if query.Author != "" {
+			args.add("authorUsername", "String", query.Author)
+		}
+		if query.Assignee != "" {
+			args.add("assigneeUsername", "String", query.Assignee)
+		}
+		if query.Reviewer != "" {
+			args.add("reviewerUsername", "String", query.Reviewer)
+		}
+	}
+	if len(query.Labels) > 0 {
+		args.add("labels", "[String!]", query.Labels)
+	}
+	if query.Milestone != "" {
+		args.add("milestoneTitle", "String", query.Milestone)
+	}
+	if query.Draft != nil {
+		args.add("draft", "Boolean", *query.Draft)
+	}
+	if query.SourceBranch != "" {
+		args.add("sourceBranches", "[String!]", []string{query.SourceBranch})
+	}
+	if query.TargetBranch != "" {
+		args.add("targetBranches", "[String!]", []string{query.TargetBranch})
+	}
+	if query.CreatedAfter != nil {
+		args.add("createdAfter", "Time", query.CreatedAfter.Format(time.RFC3339))
+	}
+	if query.CreatedBefore != nil {
+		args.add("createdBefore", "Time", query.CreatedBefore.Format(time.RFC3339))
+	}
+	if query.Group != "" {
+		args.add("includeSubgroups", "Boolean", true)
+	}
+
+	return args
+}
+
+// listOwnerMergeRequests lists the given fields of the merge requests of the
+// connections of an owner, following every connection to its last page. Each
+// query asks for the next page of the connections that have one.
+func (r *Repository) listOwnerMergeRequests(
+	ctx context.Context,
+	owner owner,
+	connections []string,
+	args *arguments,
+	fields string,
+) ([]*mergeRequest, error) {
+	cursors := make(map[string]string, len(connections))
+	pending := connections
+
+	var mrs []*mergeRequest
+	for len(pending) > 0 {
+		query, variables := ownerQuery(owner, pending, args, fields, cursors)
+
+		var data struct {
+			Owner map[string]struct {
+				Nodes    []*mergeRequest `json:"nodes"`
+				PageInfo pageInfo        `json:"pageInfo"`
+			} `json:"owner"`
+		}
+
+		if err := r.do(ctx, query, variables, &data); err != nil {
+			return nil, err
+		}
+
+		var next []string
+		for _, connection := range pending {
+			page := data.Owner[connection]
+			mrs = append(mrs, page.Nodes...)
+			if page.PageInfo.HasNextPage {
+				cursors[connection] = page.PageInfo.EndCursor
+				next = append(next, connection)
+			}
+		}
+		pending = next
+	}
+
+	return mrs, nil
+}
+
+// ownerQuery builds the query of a page of the connections of an owner, with
+// the cursors of the pages already read.
+func ownerQuery(
+	owner owner,
+	connections []string,
+	args *arguments,
+	fields string,
+	cursors map[string]string,
+) (string, map[string]any) {
+	declarations := append([]string(nil), args.declarations...)
+	variables := make(map[string]any, len(args.values)+len(connections)+1)
+	for name, value := range args.values {
+		variables[name] = value
+	}
+	if owner.variable != "" {
+		declarations = append(declarations, owner.variable)
+		variables["owner"] = owner.value
+	}
+
+	var b strings.Builder
+	for i, connection := range connections {
+		connectionArgs := append([]string{"first: " + strconv.Itoa(perPageLimit)}, args.args...)
+		if cursor, ok := cursors[connection]; ok {
+			name := "after" + strconv.Itoa(i)
+			declarations = append(declarations, "$"+name+": String")
+			connectionArgs = append(connectionArgs, "after: $"+name)
+			variables[name] = cursor
+		}
+		fmt.Fprintf(&b, " %s(%s) { nodes { %s } pageInfo { %s } }",
+			connection, strings.Join(connectionArgs, ", "), fields, pageInfoFields)
+	}
+
+	operation := "query"
+	if len(declarations) > 0 {
+		operation += "(" + strings.Join(declarations, ", ") + ")"
+	}
+
+	return operation + " { owner: " + owner.field + " {" + b.String() + " } }", variables
+}
+
+// fetchMergeRequest queries the given fields of a merge request.
+func (r *Repository) fetchMergeRequest(ctx context.Context, projectID, mrID int, fields string) (*mergeRequest, error) {
+	query := fmt.Sprintf(`query($ids: [ID!], $iid: String!) {
+		projects(ids: $ids) { nodes { mergeRequest(iid: $iid) { %s } } } }`, fields)
+	variables := map[string]any{"ids": []string{globalID("Project", projectID)}, "iid": strconv.Itoa(mrID)}
+
+	var data struct {
+		Projects struct {
+			Nodes []struct {
+				MergeRequest *mergeRequest `json:"mergeRequest"`
+			} `json:"nodes"`
+		} `json:"projects"`
+	}
+
+	if err := r.do(ctx, query, variables, &data); err != nil {
+		return nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	if len(data.Projects.Nodes) == 0 || data.Projects.Nodes[0].MergeRequest == nil {
+		return nil, fmt.Errorf("failed to get merge request: %d!%d not found", projectID, mrID)
+	}
+
+	return data.Projects.Nodes[0].MergeRequest, nil
+}
+
+// supportsApprovalRules tells whether merge requests have approvalRuleFields,
+// asking the server the first time.
+func (r *Repository) supportsApprovalRules(ctx context.Context) (bool, error) {
+	r.mu.Lock()
+	supported := r.approvalRules
+	r.mu.Unlock()
+
+	if supported != nil {
+		return *supported, nil
+	}
+
+	var data struct {
+		Type *struct {
+			Fields []struct {
+				Name string `json:"name"`
+			} `json:"fields"`
+		} `json:"__type"`
+	}
+
+	if err := r.do(ctx, `query { __type(name: "MergeRequest") { fields { name } } }`, nil, &data); err != nil {
+		return false, fmt.Errorf("failed to inspect merge request fields: %w", err)
+	}
+
+	found := false
+	if data.Type != nil {
+		for _, field := range data.Type.Fields {
+			if field.Name == "approvalsLeft" {
+				found = true
+
+				break
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.approvalRules = &found
+	r.mu.Unlock()
+
+	return found, nil
+}
+
+// graphQLRequest is the body of a GraphQL request. The GraphQL service of
+// the client cannot send variables, so do posts the request itself.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// do executes a GraphQL query with the given variables and decodes its data
+// into out.
+func (r *Repository) do(ctx context.Context, query string, variables map[string]any, out any) error {
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	req, err := r.client.NewRequest(http.MethodPost, "", graphQLRequest{Query: query, Variables: variables},
+		[]gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return fmt.Errorf("failed to create query: %w", err)
+	}
+	// The request is built for the REST API, like in the GraphQL service.
+	req.URL.Path = gitlab.GraphQLAPIEndpoint
+
+	if _, err := r.client.Do(req, &resp); err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+
+		return fmt.Errorf("query returned errors: %s", strings.Join(messages, "; "))
+	}
+
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+type user struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	PublicEmail string `json:"publicEmail"`
+	Status      *struct {
+		Message      string `json:"message"`
+		Availability string `json:"availability"`
+	} `json:"status"`
+}
+
+func (u *user) toDomain() *domain.User {
+	domainUser := &domain.User{
+		ID:       parseGlobalID(u.ID),
+		Username: u.Username,
+		Email:    u.PublicEmail,
+	}
+
+	if u.Status != nil {
+		domainUser.Status = domain.UserStatus{
+			Message:      u.Status.Message,
+			Availability: strings.ToLower(u.Status.Availability),
+		}
+	}
+
+	return domainUser
+}
+
+type pageInfo struct {
+	EndCursor   string `json:"endCursor"`
+	HasNextPage bool   `json:"hasNextPage"`
+}
+
+type userConnection struct {
+	Nodes []*user `json:"nodes"`
+}
+
+type labelConnection struct {
+	Nodes []struct {
+		Title string `json:"title"`
+	} `json:"nodes"`
+}
+
+type mergeRequest struct {
+	ID           string    `json:"id"`
+	IID          string    `json:"iid"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	WebURL       string    `json:"webUrl"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	Draft        bool      `json:"draft"`
+	SourceBranch string    `json:"sourceBranch"`
+	TargetBranch string    `json:"targetBranch"`
+	ProjectID    int       `json:"projectId"`
+	// SourceProjectID is nil when the fork was deleted.
+	SourceProjectID *int            `json:"sourceProjectId"`
+	Labels          labelConnection `json:"labels"`
+	Milestone       *milestone      `json:"milestone"`
+	DiffStats       diffStats       `json:"diffStatsSummary"`
+	NotesCount      int             `json:"userNotesCount"`
+	State           string          `json:"state"`
+	HeadPipeline    *pipeline       `json:"headPipeline"`
+	MergeStatus     string          `json:"detailedMergeStatus"`
+	Conflicts       bool            `json:"conflicts"`
+	Rebasing        bool            `json:"rebaseInProgress"`
+	MergeError      string          `json:"mergeError"`
+	Author          *user           `json:"author"`
+	Assignees       userConnection  `json:"assignees"`
+	Reviewers       userConnection  `json:"reviewers"`
+	ApprovedBy      userConnection  `json:"approvedBy"`
+
+	// ApprovalsRequired and ApprovalsLeft are only queried on GitLab
+	// Enterprise Edition, see approvalRuleFields.
+	ApprovalsRequired int `json:"approvalsRequired"`
+	ApprovalsLeft     int `json:"approvalsLeft"`
+}
+
+type milestone struct {
+	Title string `json:"title"`
+}
+
+type diffStats struct {
+	FileCount int `json:"fileCount"`
+}
+
+type pipeline struct {
+	Status string `json:"status"`
+	Jobs   struct {
+		Nodes []struct {
+			Name         string `json:"name"`
+			AllowFailure bool   `json:"allowFailure"`
+		} `json:"nodes"`
+	} `json:"jobs"`
+}
+
+// approvals returns the approval state of the merge request, which needs
+// approvalFields and approvalRuleFields.
+func (mr *mergeRequest) approvals() *domain.Approvals {
+	approvedBy := make([]*domain.User, 0, len(mr.ApprovedBy.Nodes))
+	for _, u := range mr.ApprovedBy.Nodes {
+		approvedBy = append(approvedBy, u.toDomain())
+	}
+
+	return &domain.Approvals{
+		ApprovedBy: approvedBy,
+		Required:   mr.ApprovalsRequired,
+		Left:       mr.ApprovalsLeft,
+	}
+}
+
+func (mr *mergeRequest) toDomain() *domain.MergeRequest {
+	iid, _ := strconv.Atoi(mr.IID)
+
+	domainMR := &domain.MergeRequest{
+		ID:           parseGlobalID(mr.ID),
+		IID:          iid,
+		Title:        mr.Title,
+		Description:  mr.Description,
+		WebURL:       mr.WebURL,
+		Reviewers:    make([]*domain.User, 0, len(mr.Reviewers.Nodes)),
+		CreatedAt:    mr.CreatedAt,
+		UpdatedAt:    mr.UpdatedAt,
+		ProjectID:    mr.ProjectID,
+		Draft:        mr.Draft,
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		State:        mr.State,
+		HeadRef:      fmt.Sprintf("refs/merge-requests/%d/head", iid),
+		Fork:         mr.SourceProjectID == nil || *mr.SourceProjectID != mr.ProjectID,
+		CommentCount: mr.NotesCount,
+		ChangedFiles: mr.DiffStats.FileCount,
+		// GraphQL enums are upper case, unlike the statuses of the REST API.
+		DetailedMergeStatus: strings.ToLower(mr.MergeStatus),
+		HasConflicts:        mr.Conflicts,
+		RebaseInProgress:    mr.Rebasing,
+		MergeError:          mr.MergeError,
+	}
+
+	// Jobs failing while the pipeline still runs may be retried, so they
+	// only count once it failed.
+	if mr.HeadPipeline != nil {
+		domainMR.PipelineStatus = strings.ToLower(mr.HeadPipeline.Status)
+	}
+	if domainMR.HasFailedPipeline() {
+		for _, job := range mr.HeadPipeline.Jobs.Nodes {
+			if !job.AllowFailure {
+				domainMR.FailedJobs = append(domainMR.FailedJobs, job.Name)
+			}
+		}
+	}
+
+	for _, label := range mr.Labels.Nodes {
+		domainMR.Labels = append(domainMR.Labels, label.Title)
+	}
+
+	if mr.Milestone != nil {
+		domainMR.Milestone = mr.Milestone.Title
+	}
+
+	if mr.Author != nil {
+		domainMR.Author = mr.Author.toDomain()
+	}
+
+	if len(mr.Assignees.Nodes) > 0 {
+		domainMR.Assignee = mr.Assignees.Nodes[0].toDomain()
+	}
+
+	for _, reviewer := range mr.Reviewers.Nodes {
+		domainMR.Reviewers = append(domainMR.Reviewers, reviewer.toDomain())
+	}
+
+	return domainMR
+}
+
+// globalID builds a GitLab global ID such as gid://gitlab/Project/1.
+func globalID(kind string, id int) string {
+	return "gid://gitlab/" + kind + "/" + strconv.Itoa(id)
+}
+
+// parseGlobalID extracts the numeric ID from a GitLab global ID.
+func parseGlobalID(gid string) int {
+	id, _ := strconv.Atoi(gid[strings.LastIndex(gid, "/")+1:])
+
+	return id
+}
@@ -0,0 +1,293 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/denchenko/gg/internal/adapters/secondary/cache"
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/cached"
+	restrepo "github.com/denchenko/gg/internal/adapters/secondary/repository/gitlab"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+const mrNode = `{
+	"id": "gid://gitlab/MergeRequest/%[1]d", "iid": "%[2]d", "title": "MR %[1]d",
+	"webUrl": "https://gitlab.example.com/group/p%[3]d/-/merge_requests/%[2]d",
+	"createdAt": "2025-01-01T10:00:00Z", "updatedAt": "2025-01-02T10:00:00Z",
+	"draft": false, "sourceBranch": "feature", "projectId": %[3]d, "sourceProjectId": %[3]d,
+	"headPipeline": {"status": "FAILED", "jobs": {"nodes": [
+		{"name": "lint", "allowFailure": false}, {"name": "audit", "allowFailure": true}]}},
+	"author": {"id": "gid://gitlab/User/9", "username": "author"},
+	"assignees": {"nodes": [{"id": "gid://gitlab/User/1", "username": "alice",
+		"status": {"message": "", "availability": "BUSY"}}]},
+	"reviewers": {"nodes": []},
+	"approvedBy": {"nodes": [{"id": "gid://gitlab/User/%[3]d", "username": "approver"}]},
+	"approvalsRequired": 1, "approvalsLeft": 0
+}`
+
+// newTestRepository serves the queries of a test server backed by handler,
+// as GitLab Enterprise Edition unless ee is false.
+func newTestRepository(t *testing.T, handler http.HandlerFunc) *Repository {
+	t.Helper()
+
+	return newEditionTestRepository(t, true, handler)
+}
+
+func newEditionTestRepository(t *testing.T, ee bool, handler http.HandlerFunc) *Repository {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		if strings.Contains(string(body), "__type") {
+			fields := `{"name": "iid"}`
+			if ee {
+				fields += `, {"name": "approvalsLeft"}`
+			}
+			_, _ = w.Write([]byte(`{"data": {"__type": {"fields": [` + fields + `]}}}`))
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := gitlab.NewClient("token", gitlab.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	return NewRepository(client, restrepo.NewRepository(client, server.URL))
+}
+
+func TestRepository_ListMergeRequests_All(t *testing.T) {
+	var requests atomic.Int32
+
+	repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var body graphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.NotContains(t, body.Query, "jobs", "listings leave out the jobs of pipelines")
+
+		// Both owners see MR 1!42; only the team member sees 2!42.
+		nodes := `[` + fmt.Sprintf(mrNode, 1, 42, 1) + `]`
+		if body.Variables["owner"] == "bob" {
+			nodes = `[` + fmt.Sprintf(mrNode, 1, 42, 1) + `,` + fmt.Sprintf(mrNode, 2, 42, 2) + `]`
+		}
+
+		_, _ = w.Write([]byte(`{"data": {"owner": {
+			"authoredMergeRequests": {"nodes": []},
+			"assignedMergeRequests": {"nodes": ` + nodes + `},
+			"reviewRequestedMergeRequests": {"nodes": []}
+		}}}`))
+	})
+
+	ctx := context.Background()
+	require.NoError(t, repo.PreloadUsersByUsernames(ctx, []string{"bob"}))
+
+	mrs, err := repo.ListMergeRequests(ctx, &domain.MergeRequestQuery{State: domain.StateOpened, Scope: domain.ScopeAll})
+	require.NoError(t, err)
+	require.Len(t, mrs, 2, "same IID in different projects must not be deduplicated")
+	assert.Equal(t, int32(2), requests.Load())
+
+	assert.Equal(t, 42, mrs[0].IID)
+	assert.Equal(t, 1, mrs[0].Assignee.ID)
+	assert.Equal(t, "busy", mrs[0].Assignee.Status.Availability)
+	assert.Equal(t, "failed", mrs[0].PipelineStatus)
+	assert.False(t, mrs[0].Fork)
+}
+
+func TestRepository_GetMergeRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		failedJobs []string
+	}{
+		{name: "failed pipeline", status: "FAILED", failedJobs: []string{"lint"}},
+		{name: "running pipeline", status: "RUNNING"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+				var body graphQLRequest
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Contains(t, body.Query, "jobs(statuses: [FAILED], retried: false)")
+
+				node := strings.Replace(fmt.Sprintf(mrNode, 2, 42, 2), `"FAILED"`, `"`+tt.status+`"`, 1)
+				_, _ = w.Write([]byte(`{"data": {"projects": {"nodes": [{"mergeRequest": ` + node + `}]}}}`))
+			})
+
+			mr, err := repo.GetMergeRequest(context.Background(), 2, 42)
+			require.NoError(t, err)
+			assert.Equal(t, strings.ToLower(tt.status), mr.PipelineStatus)
+			assert.Equal(t, tt.failedJobs, mr.FailedJobs, "jobs allowed to fail, or of a running pipeline, are left out")
+		})
+	}
+}
+
+func TestRepository_GetMergeRequestApprovals(t *testing.T) {
+	var body graphQLRequest
+
+	repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		_, _ = w.Write([]byte(`{"data": {"projects": {"nodes": [{"mergeRequest": ` +
+			fmt.Sprintf(mrNode, 2, 42, 2) + `}]}}}`))
+	})
+
+	approvals, err := repo.GetMergeRequestApprovals(context.Background(), 2, 42)
+	require.NoError(t, err)
+	require.Len(t, approvals.ApprovedBy, 1)
+	assert.Equal(t, 2, approvals.ApprovedBy[0].ID)
+	assert.Equal(t, 1, approvals.Required)
+	assert.Zero(t, approvals.Left)
+
+	assert.Contains(t, body.Query, "projects(ids: $ids)")
+	assert.Equal(t, map[string]any{"ids": []any{"gid://gitlab/Project/2"}, "iid": "42"}, body.Variables)
+	assert.Contains(t, body.Query, "approvalsLeft")
+	assert.NotContains(t, body.Query, "headPipeline", "only approvals are queried")
+}
+
+func TestRepository_ListMergeRequests_Approvals(t *testing.T) {
+	var queries []string
+
+	repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, body.Query)
+
+		_, _ = w.Write([]byte(`{"data": {"owner": {"mergeRequests": {"nodes": [` +
+			fmt.Sprintf(mrNode, 1, 41, 1) + `,` + fmt.Sprintf(mrNode, 2, 42, 1) + `]}}}}`))
+	})
+	cachedRepo := cached.NewCachedRepository(repo, cache.NewInMemoryCache())
+
+	ctx := context.Background()
+	mrs, err := cachedRepo.ListMergeRequests(ctx, &domain.MergeRequestQuery{Project: "group/p1"})
+	require.NoError(t, err)
+	require.Len(t, mrs, 2)
+
+	for _, mr := range mrs {
+		approvals, err := cachedRepo.GetMergeRequestApprovals(ctx, mr.ProjectID, mr.IID)
+		require.NoError(t, err)
+		require.Len(t, approvals.ApprovedBy, 1)
+		assert.Equal(t, "approver", approvals.ApprovedBy[0].Username)
+		assert.Equal(t, 1, approvals.Required)
+		assert.Zero(t, approvals.Left)
+	}
+
+	require.Len(t, queries, 1, "approvals come with the listing")
+	assert.Contains(t, queries[0], "approvalsLeft")
+}
+
+func TestRepository_ListMergeRequests_Group(t *testing.T) {
+	var requests []graphQLRequest
+
+	repo := newTestRepository(t, func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+
+		// The first page links to a second one.
+		pageInfo := `{"endCursor": "page2", "hasNextPage": true}`
+		if len(requests) > 1 {
+			pageInfo = `{"endCursor": "", "hasNextPage": false}`
+		}
+
+		_, _ = w.Write([]byte(`{"data": {"owner": {"mergeRequests": {"nodes": [` +
+			fmt.Sprintf(mrNode, len(requests), 40+len(requests), 1) + `], "pageInfo": ` + pageInfo + `}}}}`))
+	})
+
+	draft := true
+	mrs, err := repo.ListMergeRequests(context.Background(), &domain.MergeRequestQuery{
+		State:    domain.StateOpened,
+		Scope:    domain.ScopeAll,
+		Group:    "group",
+		Reviewer: "bob",
+		Labels:   []string{"bug"},
+		Draft:    &draft,
+	})
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	require.Len(t, mrs, 2, "every page is read")
+
+	query := requests[0].Query
+	assert.Contains(t, query, "owner: group(fullPath: $owner)")
+	assert.Contains(t, query, "mergeRequests(first: 100, state: $state, reviewerUsername: $reviewerUsername, "+
+		"labels: $labels, draft: $draft, includeSubgroups: $includeSubgroups)")
+	assert.Equal(t, map[string]any{
+		"owner":            "group",
+		"state":            "opened",
+		"reviewerUsername": "bob",
+		"labels":           []any{"bug"},
+		"draft":            true,
+		"includeSubgroups": true,
+	}, requests[0].Variables, "values are passed as variables")
+
+	assert.Contains(t, requests[1].Query, "after: $after0")
+	assert.Equal(t, "page2", requests[1].Variables["after0"])
+}
+
+func TestRepository_CommunityEdition(t *testing.T) {
+	var queries []string
+
+	repo := newEditionTestRepository(t, false, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/projects/1/merge_requests/42/approvals" {
+			_, _ = w.Write([]byte(`{"approvals_required": 0, "approvals_left": 0,
+				"approved_by": [{"user": {"id": 3, "username": "carol"}}]}`))
+
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/api/v4/users/3") {
+			_, _ = w.Write([]byte(`{"id": 3, "username": "carol"}`))
+
+			return
+		}
+
+		var body graphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, body.Query)
+
+		_, _ = w.Write([]byte(`{"data": {"owner": {"mergeRequests": {"nodes": [` + fmt.Sprintf(mrNode, 1, 42, 1) + `]}}}}`))
+	})
+
+	ctx := context.Background()
+	_, err := repo.ListMergeRequests(ctx, &domain.MergeRequestQuery{Project: "group/p1"})
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	assert.NotContains(t, queries[0], "approvalsLeft", "approval rules are Enterprise Edition only")
+
+	approvals, err := repo.GetMergeRequestApprovals(ctx, 1, 42)
+	require.NoError(t, err)
+	require.Len(t, approvals.ApprovedBy, 1)
+	assert.Equal(t, "carol", approvals.ApprovedBy[0].Username)
+}
+
+func TestRepository_QueryErrors(t *testing.T) {
+	repo := newTestRepository(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data": null, "errors": [{"message": "boom"}]}`))
+	})
+
+	_, err := repo.GetCurrentUser(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestParseGlobalID(t *testing.T) {
+	assert.Equal(t, 123, parseGlobalID("gid://gitlab/User/123"))
+	assert.Equal(t, 0, parseGlobalID("invalid"))
+	assert.Equal(t, "gid://gitlab/Project/7", globalID("Project", 7))
+}
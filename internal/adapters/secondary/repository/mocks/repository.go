@@ -26,15 +26,9 @@ func (m *MockRepository) GetProject(ctx context.Context, path string) (*domain.P
 // ListMergeRequests mocks the ListMergeRequests method.
 func (m *MockRepository) ListMergeRequests(
 	ctx context.Context,
-	state string,
-	scope ...string,
+	query *domain.MergeRequestQuery,
 ) ([]*domain.MergeRequest, error) {
-	// Convert variadic to slice for mock matching
-	scopeSlice := []string{}
-	if len(scope) > 0 {
-		scopeSlice = scope
-	}
-	args := m.Called(ctx, state, scopeSlice)
+	args := m.Called(ctx, query)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -43,13 +37,39 @@ func (m *MockRepository) ListMergeRequests(
 }
 
 // GetMergeRequestApprovals mocks the GetMergeRequestApprovals method.
-func (m *MockRepository) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) ([]*domain.User, error) {
+func (m *MockRepository) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) (*domain.Approvals, error) {
 	args := m.Called(ctx, projectID, mrID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 
-	return args.Get(0).([]*domain.User), args.Error(1)
+	return args.Get(0).(*domain.Approvals), args.Error(1)
+}
+
+// GetMergeRequestApprovalRules mocks the GetMergeRequestApprovalRules method.
+func (m *MockRepository) GetMergeRequestApprovalRules(
+	ctx context.Context,
+	projectID, mrID int,
+) ([]*domain.ApprovalRule, error) {
+	args := m.Called(ctx, projectID, mrID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*domain.ApprovalRule), args.Error(1)
+}
+
+// ListMergeRequestDiscussions mocks the ListMergeRequestDiscussions method.
+func (m *MockRepository) ListMergeRequestDiscussions(
+	ctx context.Context,
+	projectID, mrID int,
+) ([]*domain.Discussion, error) {
+	args := m.Called(ctx, projectID, mrID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*domain.Discussion), args.Error(1)
 }
 
 // GetMergeRequest mocks the GetMergeRequest method.
@@ -89,6 +109,16 @@ func (m *MockRepository) GetUserByUsername(ctx context.Context, username string)
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+// ListGroupMembers mocks the ListGroupMembers method.
+func (m *MockRepository) ListGroupMembers(ctx context.Context, groupPath string) ([]*domain.User, error) {
+	args := m.Called(ctx, groupPath)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
 // GetCurrentUser mocks the GetCurrentUser method.
 func (m *MockRepository) GetCurrentUser(ctx context.Context) (*domain.User, error) {
 	args := m.Called(ctx)
@@ -99,6 +129,16 @@ func (m *MockRepository) GetCurrentUser(ctx context.Context) (*domain.User, erro
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+// GetTokenInfo mocks the GetTokenInfo method.
+func (m *MockRepository) GetTokenInfo(ctx context.Context) (*domain.TokenInfo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.TokenInfo), args.Error(1)
+}
+
 // ListCommits mocks the ListCommits method.
 func (m *MockRepository) ListCommits(ctx context.Context, projectID int) ([]*domain.Commit, error) {
 	args := m.Called(ctx, projectID)
@@ -121,6 +161,52 @@ func (m *MockRepository) UpdateMergeRequest(
 	return args.Error(0)
 }
 
+// CreateMergeRequest mocks the CreateMergeRequest method.
+func (m *MockRepository) CreateMergeRequest(
+	ctx context.Context,
+	projectID int,
+	opts *domain.NewMergeRequest,
+) (*domain.MergeRequest, error) {
+	args := m.Called(ctx, projectID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain.MergeRequest), args.Error(1)
+}
+
+// ApproveMergeRequest mocks the ApproveMergeRequest method.
+func (m *MockRepository) ApproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	args := m.Called(ctx, projectID, mrID)
+
+	return args.Error(0)
+}
+
+// UnapproveMergeRequest mocks the UnapproveMergeRequest method.
+func (m *MockRepository) UnapproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	args := m.Called(ctx, projectID, mrID)
+
+	return args.Error(0)
+}
+
+// RebaseMergeRequest mocks the RebaseMergeRequest method.
+func (m *MockRepository) RebaseMergeRequest(ctx context.Context, projectID, mrID int) error {
+	args := m.Called(ctx, projectID, mrID)
+
+	return args.Error(0)
+}
+
+// MergeMergeRequest mocks the MergeMergeRequest method.
+func (m *MockRepository) MergeMergeRequest(
+	ctx context.Context,
+	projectID, mrID int,
+	opts *domain.MergeOptions,
+) error {
+	args := m.Called(ctx, projectID, mrID, opts)
+
+	return args.Error(0)
+}
+
 // GetUserEvents mocks the GetUserEvents method.
 func (m *MockRepository) GetUserEvents(
 	ctx context.Context,
@@ -1,11 +1,52 @@
 package config
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 
+	"github.com/denchenko/gg/internal/credentials"
+	"github.com/denchenko/gg/internal/git"
 	do "github.com/samber/do/v2"
+	"github.com/spf13/pflag"
+)
+
+// Supported APIs for reading GitLab data.
+const (
+	APIREST    = "rest"
+	APIGraphQL = "graphql"
+)
+
+// Supported backends.
+const (
+	BackendGitLab = "gitlab"
+	BackendGitHub = "github"
+	BackendGitea  = "gitea"
+)
+
+// Supported strategies for picking assignees and reviewers.
+const (
+	// StrategyExpertise prefers users with many commits in the project and few active merge requests.
+	StrategyExpertise = "expertise"
+	// StrategyWorkload prefers users with the fewest active merge requests.
+	StrategyWorkload = "workload"
+)
+
+const (
+	defaultGitLabURL = "https://gitlab.com"
+	defaultGitHubURL = "https://github.com"
+	githubHost       = "github.com"
+
+	envPrefix        = "GG_"
+	profileEnvPrefix = "GG_PROFILE_"
+	profileFlag      = "profile"
 )
 
 var Package = do.Package(
@@ -14,55 +55,520 @@ var Package = do.Package(
 
 // Config holds the application configuration.
 type Config struct {
-	BaseURL          string
-	Token            string
-	TeamUsers        []string
+	BaseURL       string
+	Token         string
+	OAuthClientID string
+	// TeamUsers is the default team. Entries starting with "@" name a group
+	// or subgroup whose members belong to the team.
+	TeamUsers []string
+	// Teams maps team names to members, given the same way as TeamUsers.
+	Teams map[string][]string
+	// ProjectTeams maps project paths to the name of their default team.
+	ProjectTeams map[string]string
+	// RouletteRules customize automatic assignment by the webhook server.
+	RouletteRules    RouletteRules
 	WebhookAddress   string
 	IssueURLTemplate string
+	// TemplateDir holds user templates overriding the embedded output templates.
+	TemplateDir      string
+	API              string
+	Backend          string
+	StalledAfterDays int
+	AssigneeStrategy string
+	ReviewerStrategy string
+
+	// Profile is the name of the active profile, empty when none is used.
+	Profile string
+
+	remoteHost string
+	layers     *layers
+	values     settings
+	lookup     tokenLookup
+}
+
+// tokenLookup finds a stored token for an instance. It returns an empty value
+// when none is stored.
+type tokenLookup func(baseURL string) (value, error)
+
+// NewConfig creates a new configuration (for DI), with the command line flags
+// of the injector, if any.
+func NewConfig(i do.Injector) (*Config, error) {
+	flags, _ := do.Invoke[*pflag.FlagSet](i)
+
+	return New(flags)
+}
+
+// New creates a new configuration by layering, from lowest to highest
+// precedence: defaults, the user config file, the repository .gg.yaml file,
+// environment variables and command line flags.
+//
+// The profile is taken from the --profile flag or GG_PROFILE; without one,
+// the profile whose base URL matches the origin remote of the current git
+// repository is used. When no backend is configured, it is detected from that
+// remote as well.
+//
+// Flags are read from the flag set cobra parsed, where only those set on the
+// command line count. It may be nil.
+func New(flags *pflag.FlagSet) (*Config, error) {
+	remoteHost, profile, files := discover(flags)
+
+	return load(remoteHost, profile, storedToken, flagSettings(flags), files...)
+}
+
+// Connection resolves only the settings needed to reach the server: backend,
+// base URL, API and, if available, token. It is meant for commands such as
+// "gg auth login" that must work before the rest is configured.
+func Connection(flags *pflag.FlagSet) (*Config, error) {
+	remoteHost, _, s, err := selected(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err = resolveToken(derive(s, remoteHost), storedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return connection(s)
+}
+
+// selected resolves the settings of the profile selected for the current
+// invocation, without derived values and stored tokens.
+func selected(flags *pflag.FlagSet) (remoteHost, profile string, s settings, err error) {
+	remoteHost, profile, files := discover(flags)
+
+	l, err := readLayers(flagSettings(flags), files...)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	// Profile names are lowercased when parsed, so they match in any case.
+	profile = strings.ToLower(profile)
+	if profile == "" {
+		profile = matchProfile(l.profiles(), remoteHost)
+	}
+
+	s, err = l.resolve(profile)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return remoteHost, profile, s, nil
+}
+
+// storedToken looks the token up in the credential store used by "gg auth login".
+func storedToken(baseURL string) (value, error) {
+	store, err := credentials.Default()
+	if err != nil {
+		return value{}, fmt.Errorf("failed to open credential store: %w", err)
+	}
+
+	token, err := store.Get(baseURL)
+	if errors.Is(err, credentials.ErrNotFound) {
+		return value{}, nil
+	}
+	if err != nil {
+		return value{}, fmt.Errorf("failed to read token from %s: %w", store.Name(), err)
+	}
+
+	return value{Raw: token, Source: Source{Name: store.Name()}}, nil
+}
+
+// discover finds the git remote host, the requested profile and the config
+// files, in increasing order of precedence, for the current invocation.
+func discover(flags *pflag.FlagSet) (remoteHost, profile string, files []string) {
+	ctx := context.Background()
+
+	if remote, err := git.CurrentRemote(ctx); err == nil {
+		remoteHost = remote.Host
+	}
+
+	if userFile, err := UserFile(); err == nil {
+		files = append(files, userFile)
+	}
+	if topLevel, err := git.TopLevel(ctx); err == nil {
+		files = append(files, filepath.Join(topLevel, RepoFileName))
+	}
+
+	profile = flagValue(flags, profileFlag)
+	if profile == "" {
+		profile = os.Getenv("GG_PROFILE")
+	}
+
+	return remoteHost, profile, files
+}
+
+// load resolves the configuration from config files, given in increasing
+// order of precedence, the environment and flags. Missing tokens are looked
+// up with lookup unless it is nil.
+func load(remoteHost, profile string, lookup tokenLookup, flags settings, files ...string) (*Config, error) {
+	l, err := readLayers(flags, files...)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == "" {
+		profile = matchProfile(l.profiles(), remoteHost)
+	}
+
+	cfg := &Config{
+		remoteHost: remoteHost,
+		layers:     l,
+		lookup:     lookup,
+	}
+
+	return cfg.ForProfile(profile)
+}
+
+// layers holds the settings of every source apart, so that the settings of a
+// profile can be merged in between.
+type layers struct {
+	// files holds the defaults and the config files, env the environment.
+	files settings
+	env   settings
+	// profileFiles and profileEnv hold the settings of every profile from
+	// the same sources.
+	profileFiles map[string]settings
+	profileEnv   map[string]settings
+	// flags holds the command line flags, which apply to every profile.
+	flags settings
+}
+
+// readLayers reads defaults, config files and the environment, and layers
+// the settings of the command line flags on top.
+func readLayers(flags settings, files ...string) (*layers, error) {
+	l := &layers{
+		files:        defaultSettings(),
+		env:          envSettings(envPrefix),
+		profileFiles: make(map[string]settings),
+		profileEnv:   envProfiles(),
+		flags:        flags,
+	}
+
+	for _, path := range files {
+		f, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		l.files = l.files.merge(f.settings)
+		for name, s := range f.profiles {
+			l.profileFiles[name] = l.profileFiles[name].merge(s)
+		}
+	}
+
+	return l, nil
+}
+
+// profiles returns the settings every profile sets itself, by profile name.
+func (l *layers) profiles() map[string]settings {
+	profiles := make(map[string]settings)
+	for name, s := range l.profileFiles {
+		profiles[name] = s
+	}
+	for name, s := range l.profileEnv {
+		profiles[name] = profiles[name].merge(s)
+	}
+
+	return profiles
+}
+
+// resolve merges the layers for a profile, or for the top-level
+// configuration when name is empty. The environment overrides config files
+// and flags override both; within each source the profile overrides the top
+// level.
+func (l *layers) resolve(name string) (settings, error) {
+	if name == "" {
+		return l.files.merge(l.env).merge(l.flags), nil
+	}
+
+	if _, ok := l.profiles()[name]; !ok {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+
+	return l.files.merge(l.profileFiles[name]).merge(l.env).merge(l.profileEnv[name]).merge(l.flags), nil
 }
 
-// NewConfig creates a new configuration from environment variables (for DI).
-func NewConfig(_ do.Injector) (*Config, error) {
-	return New()
+// Profiles returns the names of all configured profiles in alphabetical order.
+func (c *Config) Profiles() []string {
+	profiles := c.layers.profiles()
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
 }
 
-// New creates a new configuration from environment variables.
-func New() (*Config, error) {
-	gitServiceURL := os.Getenv("GG_BASE_URL")
-	if gitServiceURL == "" {
-		gitServiceURL = "https://gitlab.com"
+// ForProfile returns the configuration for the named profile. Values the
+// profile does not set are inherited from the top-level configuration. An
+// empty name selects the top-level configuration itself. Names are not case
+// sensitive.
+func (c *Config) ForProfile(name string) (*Config, error) {
+	name = strings.ToLower(name)
+
+	s, err := c.layers.resolve(name)
+	if err != nil {
+		return nil, err
 	}
 
-	webhookAddress := os.Getenv("GG_WEBHOOK_ADDRESS")
-	if webhookAddress == "" {
-		webhookAddress = ":8080"
+	cfg, err := build(s, c.remoteHost, c.lookup)
+	if err != nil {
+		if name != "" {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+
+		return nil, err
+	}
+
+	cfg.Profile = name
+	cfg.remoteHost = c.remoteHost
+	cfg.layers = c.layers
+	cfg.lookup = c.lookup
+
+	return cfg, nil
+}
+
+// Source returns where the effective value of a setting came from.
+func (c *Config) Source(key string) Source {
+	return c.values[key].Source
+}
+
+// derive fills in settings whose defaults depend on the git remote or on other settings.
+func derive(s settings, remoteHost string) settings {
+	derived := settings{}
+
+	backend := s["backend"].Raw
+	if backend == "" {
+		backend = detectBackend(remoteHost)
+		derived["backend"] = value{Raw: backend, Source: Source{Name: "git remote"}}
 	}
 
-	privateToken := os.Getenv("GG_TOKEN")
-	if privateToken == "" {
-		return nil, errors.New("GG_TOKEN environment variable is required")
+	if s["base_url"].Raw == "" {
+		switch backend {
+		case BackendGitHub:
+			derived["base_url"] = value{Raw: defaultGitHubURL, Source: Source{Name: "default"}}
+		case BackendGitLab:
+			derived["base_url"] = value{Raw: defaultGitLabURL, Source: Source{Name: "default"}}
+		}
 	}
 
-	teamUsersStr := os.Getenv("GG_TEAM")
-	if teamUsersStr == "" {
-		return nil, errors.New("GG_TEAM environment variable is required")
+	return s.merge(derived)
+}
+
+// resolveToken fills in the token from the credential store when no other
+// source sets it.
+func resolveToken(s settings, lookup tokenLookup) (settings, error) {
+	if s["token"].Raw != "" || s["base_url"].Raw == "" || lookup == nil {
+		return s, nil
 	}
 
-	teamUsers := strings.Split(teamUsersStr, ",")
-	for i, user := range teamUsers {
-		teamUsers[i] = strings.TrimSpace(user)
+	token, err := lookup(s["base_url"].Raw)
+	if err != nil {
+		return nil, err
+	}
+	if token.Raw == "" {
+		return s, nil
 	}
 
-	issueURLTemplate := os.Getenv("GG_ISSUE_URL_TEMPLATE")
-	if issueURLTemplate != "" && !strings.Contains(issueURLTemplate, "{{.Issue}}") {
-		return nil, errors.New("GG_ISSUE_URL_TEMPLATE must contain {{.Issue}} placeholder")
+	return s.merge(settings{"token": token}), nil
+}
+
+// connection validates the settings needed to reach the server.
+func connection(s settings) (*Config, error) {
+	backend := s["backend"]
+	switch backend.Raw {
+	case BackendGitLab, BackendGitHub, BackendGitea:
+	default:
+		return nil, fmt.Errorf("%s: backend must be %q, %q or %q, got %q",
+			backend.Source, BackendGitLab, BackendGitHub, BackendGitea, backend.Raw)
+	}
+
+	if s["base_url"].Raw == "" {
+		// Gitea and Forgejo are always self-hosted.
+		return nil, fmt.Errorf("base_url is required for the %s backend: %s", backend.Raw, howToSet("base_url"))
+	}
+
+	api := s["api"]
+	if api.Raw != APIREST && api.Raw != APIGraphQL {
+		return nil, fmt.Errorf("%s: api must be %q or %q, got %q", api.Source, APIREST, APIGraphQL, api.Raw)
 	}
 
 	return &Config{
-		BaseURL:          gitServiceURL,
-		Token:            privateToken,
-		TeamUsers:        teamUsers,
-		WebhookAddress:   webhookAddress,
-		IssueURLTemplate: issueURLTemplate,
+		BaseURL:       s["base_url"].Raw,
+		Token:         s["token"].Raw,
+		OAuthClientID: s["oauth.client_id"].Raw,
+		API:           api.Raw,
+		Backend:       backend.Raw,
+		values:        s,
 	}, nil
 }
+
+//nolint:funlen // validation of every setting in one place
+func build(s settings, remoteHost string, lookup tokenLookup) (*Config, error) {
+	s, err := resolveToken(derive(s, remoteHost), lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := connection(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("token is required: run gg auth login or %s", howToSet("token"))
+	}
+
+	teamUsers := splitList(s["team"].Raw)
+
+	teams := make(map[string][]string)
+	for name, members := range s.mapEntries("teams") {
+		teams[name] = splitList(members.Raw)
+		if len(teams[name]) == 0 {
+			return nil, fmt.Errorf("%s: team %q has no members", members.Source, name)
+		}
+	}
+	if len(teamUsers) == 0 && len(teams) == 0 {
+		return nil, fmt.Errorf("team is required: %s, or define named teams", howToSet("team"))
+	}
+
+	projectTeams := make(map[string]string)
+	for project, team := range s.mapEntries("project_teams") {
+		if _, ok := teams[team.Raw]; !ok {
+			return nil, fmt.Errorf("%s: project %s refers to unknown team %q", team.Source, project, team.Raw)
+		}
+		projectTeams[project] = team.Raw
+	}
+
+	issueURLTemplate := s["templates.issue_url"]
+	if issueURLTemplate.Raw != "" {
+		if !strings.Contains(issueURLTemplate.Raw, "{{.Issue}}") {
+			return nil, fmt.Errorf("%s: templates.issue_url must contain {{.Issue}} placeholder", issueURLTemplate.Source)
+		}
+		if _, err := template.New("issueURL").Parse(issueURLTemplate.Raw); err != nil {
+			return nil, fmt.Errorf("%s: templates.issue_url is not a valid template: %w", issueURLTemplate.Source, err)
+		}
+	}
+
+	stalled := s["thresholds.stalled_after_days"]
+	stalledAfterDays, err := strconv.Atoi(stalled.Raw)
+	if err != nil || stalledAfterDays <= 0 {
+		return nil, fmt.Errorf("%s: thresholds.stalled_after_days must be a positive number, got %q",
+			stalled.Source, stalled.Raw)
+	}
+
+	for _, key := range []string{"strategies.assignee", "strategies.reviewer"} {
+		strategy := s[key]
+		if strategy.Raw != StrategyExpertise && strategy.Raw != StrategyWorkload {
+			return nil, fmt.Errorf("%s: %s must be %q or %q, got %q",
+				strategy.Source, key, StrategyExpertise, StrategyWorkload, strategy.Raw)
+		}
+	}
+
+	rules, err := rouletteRules(s, teams)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.TeamUsers = teamUsers
+	cfg.Teams = teams
+	cfg.ProjectTeams = projectTeams
+	cfg.RouletteRules = rules
+	cfg.WebhookAddress = s["webhook_address"].Raw
+	cfg.IssueURLTemplate = issueURLTemplate.Raw
+	cfg.TemplateDir = resolvePath(s["templates.dir"])
+	cfg.StalledAfterDays = stalledAfterDays
+	cfg.AssigneeStrategy = s["strategies.assignee"].Raw
+	cfg.ReviewerStrategy = s["strategies.reviewer"].Raw
+
+	return cfg, nil
+}
+
+// resolvePath resolves a path setting. "~/" stands for the home directory and
+// relative paths from config files are relative to the file's directory.
+func resolvePath(v value) string {
+	path := v.Raw
+	if path == "" {
+		return ""
+	}
+
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+
+	if !filepath.IsAbs(path) && v.Source.Line > 0 {
+		return filepath.Join(filepath.Dir(v.Source.Name), path)
+	}
+
+	return path
+}
+
+// splitList splits a comma-separated list value, dropping empty items.
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// howToSet describes how a missing setting can be provided.
+func howToSet(key string) string {
+	def, _ := lookupSetting(key)
+
+	return fmt.Sprintf("set %s%s or %s in the config file", envPrefix, def.Env, key)
+}
+
+// matchProfile returns the profile whose base URL host equals the git remote
+// host, or an empty string if none or more than one does.
+func matchProfile(profiles map[string]settings, remoteHost string) string {
+	if remoteHost == "" {
+		return ""
+	}
+
+	var match string
+	for name, profile := range profiles {
+		u, err := url.Parse(profile["base_url"].Raw)
+		if err != nil || u.Hostname() != remoteHost {
+			continue
+		}
+		if match != "" {
+			return ""
+		}
+		match = name
+	}
+
+	return match
+}
+
+// flagValue returns the value of the named flag when it was set on the
+// command line, and an empty string otherwise or when flags is nil.
+func flagValue(flags *pflag.FlagSet, name string) string {
+	if flags == nil {
+		return ""
+	}
+
+	flag := flags.Lookup(name)
+	if flag == nil || !flag.Changed {
+		return ""
+	}
+
+	return flag.Value.String()
+}
+
+// detectBackend picks the backend matching a git remote host.
+func detectBackend(remoteHost string) string {
+	if remoteHost == githubHost {
+		return BackendGitHub
+	}
+
+	return BackendGitLab
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -47,6 +48,7 @@ func TestNew(t *testing.T) {
 
 	tests := []struct {
 		name        string
+		remoteHost  string
 		setupEnv    func()
 		expectError bool
 		validate    func(*testing.T, *Config)
@@ -65,8 +67,69 @@ func TestNew(t *testing.T) {
 				assert.Equal(t, "https://gitlab.com", cfg.BaseURL)
 				assert.Equal(t, []string{"user1", "user2", "user3"}, cfg.TeamUsers)
 				assert.Equal(t, ":8080", cfg.WebhookAddress)
+				assert.Equal(t, APIREST, cfg.API)
+				assert.Equal(t, BackendGitLab, cfg.Backend)
 			},
 		},
+		{
+			name:       "github backend detected from remote",
+			remoteHost: "github.com",
+			setupEnv: func() {
+				_ = os.Setenv("GG_TOKEN", "test-token")
+				_ = os.Setenv("GG_TEAM", "user1")
+			},
+			expectError: false,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, BackendGitHub, cfg.Backend)
+				assert.Equal(t, "https://github.com", cfg.BaseURL)
+			},
+		},
+		{
+			name:       "explicit backend overrides remote",
+			remoteHost: "github.com",
+			setupEnv: func() {
+				_ = os.Setenv("GG_TOKEN", "test-token")
+				_ = os.Setenv("GG_TEAM", "user1")
+				_ = os.Setenv("GG_BACKEND", "gitlab")
+			},
+			expectError: false,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, BackendGitLab, cfg.Backend)
+				assert.Equal(t, "https://gitlab.com", cfg.BaseURL)
+			},
+		},
+		{
+			name: "gitea backend",
+			setupEnv: func() {
+				_ = os.Setenv("GG_TOKEN", "test-token")
+				_ = os.Setenv("GG_TEAM", "user1")
+				_ = os.Setenv("GG_BACKEND", "gitea")
+				_ = os.Setenv("GG_BASE_URL", "https://forgejo.example.com")
+			},
+			expectError: false,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, BackendGitea, cfg.Backend)
+				assert.Equal(t, "https://forgejo.example.com", cfg.BaseURL)
+			},
+		},
+		{
+			name: "gitea backend without base URL",
+			setupEnv: func() {
+				_ = os.Setenv("GG_TOKEN", "test-token")
+				_ = os.Setenv("GG_TEAM", "user1")
+				_ = os.Setenv("GG_BACKEND", "gitea")
+			},
+			expectError: true,
+		},
+		{
+			name: "unknown backend",
+			setupEnv: func() {
+				_ = os.Setenv("GG_TOKEN", "test-token")
+				_ = os.Setenv("GG_TEAM", "user1")
+				_ = os.Setenv("GG_BACKEND", "svn")
+			},
+			expectError: true,
+		},
 		{
 			name: "custom base URL",
 			setupEnv: func() {
@@ -140,6 +203,27 @@ func TestNew(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "graphql API",
+			setupEnv: func() {
+				_ = os.Setenv("GG_TOKEN", "test-token")
+				_ = os.Setenv("GG_TEAM", "user1")
+				_ = os.Setenv("GG_API", "graphql")
+			},
+			expectError: false,
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, APIGraphQL, cfg.API)
+			},
+		},
+		{
+			name: "unknown API",
+			setupEnv: func() {
+				_ = os.Setenv("GG_TOKEN", "test-token")
+				_ = os.Setenv("GG_TEAM", "user1")
+				_ = os.Setenv("GG_API", "soap")
+			},
+			expectError: true,
+		},
 		{
 			name: "empty issue URL template",
 			setupEnv: func() {
@@ -162,10 +246,12 @@ func TestNew(t *testing.T) {
 			_ = os.Unsetenv("GG_BASE_URL")
 			_ = os.Unsetenv("GG_WEBHOOK_ADDRESS")
 			_ = os.Unsetenv("GG_ISSUE_URL_TEMPLATE")
+			_ = os.Unsetenv("GG_API")
+			_ = os.Unsetenv("GG_BACKEND")
 
 			tt.setupEnv()
 
-			cfg, err := New()
+			cfg, err := load(tt.remoteHost, "", nil, nil)
 
 			if tt.expectError {
 				require.Error(t, err)
@@ -181,6 +267,168 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestLoad_Profiles(t *testing.T) {
+	for _, key := range []string{"BASE_URL", "WEBHOOK_ADDRESS", "ISSUE_URL_TEMPLATE", "API", "BACKEND"} {
+		t.Setenv("GG_"+key, "")
+	}
+	t.Setenv("GG_TOKEN", "default-token")
+	t.Setenv("GG_TEAM", "alice,bob")
+	t.Setenv("GG_PROFILE_WORK_BASE_URL", "https://gitlab.example.com")
+	t.Setenv("GG_PROFILE_WORK_TOKEN", "work-token")
+	t.Setenv("GG_PROFILE_OSS_TEAM", "carol")
+	t.Setenv("GG_PROFILE_BROKEN_API", "soap")
+
+	tests := []struct {
+		name        string
+		remoteHost  string
+		profile     string
+		expectError bool
+		validate    func(*testing.T, *Config)
+	}{
+		{
+			name: "no profile selected",
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Empty(t, cfg.Profile)
+				assert.Equal(t, "default-token", cfg.Token)
+				assert.Equal(t, "https://gitlab.com", cfg.BaseURL)
+			},
+		},
+		{
+			name:    "explicit profile inherits unset values",
+			profile: "work",
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "work", cfg.Profile)
+				assert.Equal(t, "work-token", cfg.Token)
+				assert.Equal(t, "https://gitlab.example.com", cfg.BaseURL)
+				assert.Equal(t, []string{"alice", "bob"}, cfg.TeamUsers)
+			},
+		},
+		{
+			name:       "profile matched by remote host",
+			remoteHost: "gitlab.example.com",
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "work", cfg.Profile)
+				assert.Equal(t, "work-token", cfg.Token)
+			},
+		},
+		{
+			name:       "explicit profile wins over remote host",
+			remoteHost: "gitlab.example.com",
+			profile:    "oss",
+			validate: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "oss", cfg.Profile)
+				assert.Equal(t, []string{"carol"}, cfg.TeamUsers)
+			},
+		},
+		{
+			name:        "unknown profile",
+			profile:     "missing",
+			expectError: true,
+		},
+		{
+			name:        "invalid profile",
+			profile:     "broken",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := load(tt.remoteHost, tt.profile, nil, nil)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, cfg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, []string{"broken", "oss", "work"}, cfg.Profiles())
+				tt.validate(t, cfg)
+			}
+		})
+	}
+}
+
+func TestConfig_ForProfile(t *testing.T) {
+	t.Setenv("GG_TOKEN", "default-token")
+	t.Setenv("GG_TEAM", "alice")
+	t.Setenv("GG_PROFILE_WORK_TOKEN", "work-token")
+
+	cfg, err := load("", "", nil, nil)
+	require.NoError(t, err)
+
+	work, err := cfg.ForProfile("work")
+	require.NoError(t, err)
+	assert.Equal(t, "work-token", work.Token)
+
+	again, err := work.ForProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, "default-token", again.Token)
+}
+
+// parseFlags declares the flags of the root command and parses args with
+// them, as cobra does.
+func parseFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+
+	flags := pflag.NewFlagSet("gg", pflag.ContinueOnError)
+	flags.String(profileFlag, "", "")
+	for _, flag := range Flags() {
+		flags.String(flag.Name, "", flag.Usage)
+	}
+	require.NoError(t, flags.Parse(args))
+
+	return flags
+}
+
+func TestFlagValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "no flag", args: []string{"my", "mr"}, expected: ""},
+		{name: "separate value", args: []string{"my", "mr", "--profile", "work"}, expected: "work"},
+		{name: "inline value", args: []string{"--profile=oss", "team", "review"}, expected: "oss"},
+		{name: "after terminator", args: []string{"mr", "--", "--profile", "work"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, flagValue(parseFlags(t, tt.args...), profileFlag))
+		})
+	}
+
+	assert.Empty(t, flagValue(nil, profileFlag), "no flags were parsed")
+}
+
+func TestFlagSettings(t *testing.T) {
+	s := flagSettings(parseFlags(t,
+		"my", "mr", "--base-url", "https://gitlab.example.com", "--default-team-members=alice,bob",
+		"--api=rest", "--api", "graphql", "--", "--issue-url", "https://jira.example.com/{{.Issue}}",
+	))
+
+	assert.Equal(t, settings{
+		"base_url": {Raw: "https://gitlab.example.com", Source: Source{Name: "--base-url"}},
+		"team":     {Raw: "alice,bob", Source: Source{Name: "--default-team-members"}},
+		"api":      {Raw: APIGraphQL, Source: Source{Name: "--api"}},
+	}, s)
+}
+
+func TestLoad_Flags(t *testing.T) {
+	t.Setenv("GG_TOKEN", "default-token")
+	t.Setenv("GG_TEAM", "alice")
+	t.Setenv("GG_BASE_URL", "https://gitlab.example.com")
+	t.Setenv("GG_PROFILE_WORK_BASE_URL", "https://gitlab.work.com")
+
+	flags := parseFlags(t, "--base-url", "https://gitlab.flag.com", "--default-team-members", "bob,carol")
+
+	cfg, err := load("", "work", nil, flagSettings(flags))
+	require.NoError(t, err)
+	assert.Equal(t, "https://gitlab.flag.com", cfg.BaseURL, "flags override profile environment")
+	assert.Equal(t, "--base-url", cfg.Source("base_url").String())
+	assert.Equal(t, []string{"bob", "carol"}, cfg.TeamUsers)
+}
+
 func TestNewConfig(t *testing.T) {
 	// Test the DI version
 	cfg, err := NewConfig(nil)
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	userFileDirPerm  = 0o700
+	userFilePerm     = 0o600
+	yamlIndentSpaces = 2
+)
+
+// Entry is the effective value of a setting together with its source. Unset
+// settings have an empty value and source.
+type Entry struct {
+	Key    string
+	Value  string
+	Source Source
+}
+
+// Inspect resolves the settings of the selected profile the same way New
+// does, but without validating them, so that incomplete or broken
+// configurations can be examined. It returns the profile name and an entry
+// for every supported setting.
+func Inspect(flags *pflag.FlagSet) (string, []Entry, error) {
+	remoteHost, profile, s, err := selected(flags)
+	if err != nil {
+		return "", nil, err
+	}
+
+	s, err = resolveToken(derive(s, remoteHost), storedToken)
+	if err != nil {
+		return "", nil, err
+	}
+
+	entries := make([]Entry, 0, len(settingDefs))
+	for _, def := range settingDefs {
+		entries = append(entries, Entry{Key: def.Key, Value: s[def.Key].Raw, Source: s[def.Key].Source})
+	}
+
+	var mapKeys []string
+	for key := range s {
+		if _, ok := splitMapKey(key); ok {
+			mapKeys = append(mapKeys, key)
+		}
+	}
+	sort.Strings(mapKeys)
+
+	for _, key := range mapKeys {
+		entries = append(entries, Entry{Key: key, Value: s[key].Raw, Source: s[key].Source})
+	}
+
+	return profile, entries, nil
+}
+
+// SetUserValue writes a setting to the user config file, under the given
+// profile unless it is empty, and returns the path of the file. List settings
+// take comma-separated values.
+func SetUserValue(profile, key, raw string) (string, error) {
+	def, ok := lookupSetting(key)
+	if !ok {
+		return "", fmt.Errorf("unknown setting %q", key)
+	}
+
+	return editUserFile(func(root *yaml.Node) error {
+		node := root
+		for _, name := range settingPath(profile, key) {
+			node = mappingChild(node, name)
+		}
+
+		*node = *valueNode(def, raw)
+
+		return nil
+	})
+}
+
+// UnsetUserValue removes a setting from the user config file, under the given
+// profile unless it is empty, and returns the path of the file.
+func UnsetUserValue(profile, key string) (string, error) {
+	if _, ok := lookupSetting(key); !ok {
+		return "", fmt.Errorf("unknown setting %q", key)
+	}
+
+	return editUserFile(func(root *yaml.Node) error {
+		if !removePath(root, settingPath(profile, key)) {
+			return fmt.Errorf("%s is not set", key)
+		}
+
+		return nil
+	})
+}
+
+// editUserFile applies edit to the user config file, preserving comments and
+// the order of other settings, and refuses to write a file that would not load.
+func editUserFile(edit func(root *yaml.Node) error) (string, error) {
+	path, err := UserFile()
+	if err != nil {
+		return "", err
+	}
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return "", fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("%s:%d: expected a mapping of settings", path, root.Line)
+	}
+
+	if err := edit(root); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(yamlIndentSpaces)
+	if err := encoder.Encode(&doc); err != nil {
+		return "", fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode config file: %w", err)
+	}
+
+	if _, err := parseFile(path, buf.Bytes()); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), userFileDirPerm); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// The file may hold tokens, so keep it private to the user.
+	if err := os.WriteFile(path, buf.Bytes(), userFilePerm); err != nil {
+		return "", fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return path, nil
+}
+
+// settingPath returns the mapping keys leading to a setting in a config file.
+func settingPath(profile, key string) []string {
+	path := strings.Split(key, ".")
+	if mk, ok := splitMapKey(key); ok {
+		path = append(strings.Split(mk.section.Key, "."), mk.name)
+		if mk.field.Key != "" {
+			path = append(path, mk.field.Key)
+		}
+	}
+	if profile != "" {
+		path = append([]string{profilesKey, profile}, path...)
+	}
+
+	return path
+}
+
+// mappingChild returns the value node for name in a mapping node, adding an
+// empty mapping if it is missing.
+func mappingChild(node *yaml.Node, name string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		*node = yaml.Node{Kind: yaml.MappingNode}
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1]
+		}
+	}
+
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, child)
+
+	return child
+}
+
+// removePath removes the value at path and any mappings left empty by that.
+// It reports whether the value was present.
+func removePath(node *yaml.Node, path []string) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != path[0] {
+			continue
+		}
+
+		child := node.Content[i+1]
+		if len(path) > 1 {
+			if !removePath(child, path[1:]) {
+				return false
+			}
+			if len(child.Content) > 0 {
+				return true
+			}
+		}
+
+		node.Content = append(node.Content[:i], node.Content[i+2:]...)
+
+		return true
+	}
+
+	return false
+}
+
+func valueNode(def setting, raw string) *yaml.Node {
+	if !def.List {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: raw}
+	}
+
+	node := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+		}
+	}
+
+	return node
+}
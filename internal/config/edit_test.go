@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetUserValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gg", "config.yaml")
+	t.Setenv("GG_CONFIG", path)
+
+	written, err := SetUserValue("", "token", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, path, written)
+
+	_, err = SetUserValue("", "team", "alice, bob")
+	require.NoError(t, err)
+	_, err = SetUserValue("", "thresholds.stalled_after_days", "5")
+	require.NoError(t, err)
+	_, err = SetUserValue("work", "base_url", "https://gitlab.example.com")
+	require.NoError(t, err)
+	_, err = SetUserValue("", "token", "rotated")
+	require.NoError(t, err)
+	_, err = SetUserValue("", "teams.backend", "alice,@acme/backend")
+	require.NoError(t, err)
+	_, err = SetUserValue("", "project_teams.acme/api.v2", "backend")
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	f, err := readFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", f.settings["token"].Raw)
+	assert.Equal(t, "alice,bob", f.settings["team"].Raw)
+	assert.Equal(t, "5", f.settings["thresholds.stalled_after_days"].Raw)
+	assert.Equal(t, "https://gitlab.example.com", f.profiles["work"]["base_url"].Raw)
+	assert.Equal(t, "alice,@acme/backend", f.settings["teams.backend"].Raw)
+	assert.Equal(t, "backend", f.settings["project_teams.acme/api.v2"].Raw)
+
+	_, err = SetUserValue("", "teams", "alice")
+	require.Error(t, err)
+}
+
+func TestUnsetUserValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv("GG_CONFIG", path)
+	require.NoError(t, os.WriteFile(path, []byte(`# personal settings
+token: secret # keep private
+thresholds:
+  stalled_after_days: 5
+profiles:
+  work:
+    token: work
+`), 0o600))
+
+	_, err := UnsetUserValue("", "thresholds.stalled_after_days")
+	require.NoError(t, err)
+	_, err = UnsetUserValue("work", "token")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# personal settings\ntoken: secret # keep private\n", string(data),
+		"empty sections are removed and comments kept")
+
+	_, err = UnsetUserValue("", "api")
+	require.Error(t, err)
+}
+
+func TestInspect(t *testing.T) {
+	for _, def := range settingDefs {
+		t.Setenv(envPrefix+def.Env, "")
+	}
+	t.Setenv("GG_PROFILE", "")
+
+	path := writeFile(t, "config.yaml", "api: graphql\n")
+	t.Setenv("GG_CONFIG", path)
+	t.Setenv("GG_TEAM", "alice")
+
+	profile, entries, err := Inspect(parseFlags(t, "--base-url", "https://gitlab.flag.com"))
+	require.NoError(t, err)
+	assert.Empty(t, profile)
+
+	values := make(map[string]Entry, len(entries))
+	for _, entry := range entries {
+		values[entry.Key] = entry
+	}
+
+	assert.Len(t, entries, len(settingDefs))
+	assert.Equal(t, "graphql", values["api"].Value)
+	assert.Equal(t, path+":1", values["api"].Source.String())
+	assert.Equal(t, "GG_TEAM", values["team"].Source.String())
+	assert.Equal(t, "--base-url", values["base_url"].Source.String())
+	assert.Empty(t, values["token"].Value, "missing settings are reported instead of failing")
+}
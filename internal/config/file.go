@@ -0,0 +1,178 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// RepoFileName is the name of the repository-level config file, looked up
+	// in the top-level directory of the current git repository.
+	RepoFileName = ".gg.yaml"
+
+	userFileDir  = "gg"
+	userFileName = "config.yaml"
+	profilesKey  = "profiles"
+)
+
+// UserFile returns the path of the user-level config file. GG_CONFIG
+// overrides the default location in the user config directory
+// ($XDG_CONFIG_HOME/gg/config.yaml on Linux).
+func UserFile() (string, error) {
+	if path := os.Getenv("GG_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+
+	return filepath.Join(dir, userFileDir, userFileName), nil
+}
+
+// file is a parsed config file layer.
+type file struct {
+	settings settings
+	profiles map[string]settings
+}
+
+// readFile reads and validates a config file. A missing file yields an empty layer.
+func readFile(path string) (*file, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &file{settings: settings{}, profiles: map[string]settings{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return parseFile(path, data)
+}
+
+func parseFile(path string, data []byte) (*file, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	f := &file{settings: settings{}, profiles: map[string]settings{}}
+	if len(doc.Content) == 0 {
+		return f, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s:%d: expected a mapping of settings", path, root.Line)
+	}
+
+	p := parser{path: path, repo: filepath.Base(path) == RepoFileName}
+	if err := p.parseMapping(root, "", f.settings, f.profiles); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+type parser struct {
+	path string
+	// repo restricts the file to the settings a repository may set.
+	repo bool
+}
+
+func (p parser) errorf(node *yaml.Node, format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", p.path, node.Line, fmt.Sprintf(format, args...))
+}
+
+// parseMapping collects the settings of a mapping node into s. Profiles are
+// only allowed at the top level, which is signalled by a non-nil profiles map.
+func (p parser) parseMapping(node *yaml.Node, prefix string, s settings, profiles map[string]settings) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := prefix + keyNode.Value
+
+		switch {
+		case key == profilesKey && profiles != nil:
+			if p.repo {
+				return p.errorf(keyNode, "%s cannot be set in %s, only in the user config file", profilesKey, RepoFileName)
+			}
+			if err := p.parseProfiles(valueNode, profiles); err != nil {
+				return err
+			}
+		case isSection(key) || (isMapEntry(key) && !isMapEntry(strings.TrimSuffix(prefix, "."))):
+			if valueNode.Kind != yaml.MappingNode {
+				return p.errorf(valueNode, "%s must be a mapping", key)
+			}
+			if err := p.parseMapping(valueNode, key+".", s, nil); err != nil {
+				return err
+			}
+		default:
+			def, ok := lookupSetting(key)
+			if !ok {
+				return p.errorf(keyNode, "unknown setting %q", key)
+			}
+			if p.repo && !def.Repo {
+				return p.errorf(keyNode, "%s cannot be set in %s, only in the user config file or the environment",
+					key, RepoFileName)
+			}
+
+			raw, err := p.parseValue(def, valueNode)
+			if err != nil {
+				return err
+			}
+
+			s[key] = value{Raw: raw, Source: Source{Name: p.path, Line: keyNode.Line}}
+		}
+	}
+
+	return nil
+}
+
+func (p parser) parseProfiles(node *yaml.Node, profiles map[string]settings) error {
+	if node.Kind != yaml.MappingNode {
+		return p.errorf(node, "%s must be a mapping of profile names to settings", profilesKey)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		nameNode, profileNode := node.Content[i], node.Content[i+1]
+		if profileNode.Kind != yaml.MappingNode {
+			return p.errorf(profileNode, "profile %q must be a mapping", nameNode.Value)
+		}
+
+		profile := settings{}
+		if err := p.parseMapping(profileNode, "", profile, nil); err != nil {
+			return err
+		}
+
+		profiles[strings.ToLower(nameNode.Value)] = profile
+	}
+
+	return nil
+}
+
+func (p parser) parseValue(def setting, node *yaml.Node) (string, error) {
+	switch {
+	case node.Kind == yaml.ScalarNode:
+		return node.Value, nil
+	case node.Kind == yaml.SequenceNode && def.List:
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return "", p.errorf(item, "%s must be a list of strings", def.Key)
+			}
+			items = append(items, item.Value)
+		}
+
+		return strings.Join(items, ","), nil
+	case def.List:
+		return "", p.errorf(node, "%s must be a string or a list of strings", def.Key)
+	default:
+		return "", p.errorf(node, "%s must be a string", def.Key)
+	}
+}
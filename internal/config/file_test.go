@@ -0,0 +1,328 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestParseFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectError string
+		validate    func(*testing.T, *file)
+	}{
+		{
+			name: "structured sections",
+			content: `base_url: https://gitlab.example.com
+team:
+  - alice
+  - bob
+templates:
+  issue_url: https://jira.com/browse/{{.Issue}}
+thresholds:
+  stalled_after_days: 5
+strategies:
+  reviewer: expertise
+profiles:
+  Work:
+    token: work-token
+`,
+			validate: func(t *testing.T, f *file) {
+				assert.Equal(t, "https://gitlab.example.com", f.settings["base_url"].Raw)
+				assert.Equal(t, "alice,bob", f.settings["team"].Raw)
+				assert.Equal(t, 2, f.settings["team"].Source.Line)
+				assert.Equal(t, "https://jira.com/browse/{{.Issue}}", f.settings["templates.issue_url"].Raw)
+				assert.Equal(t, "5", f.settings["thresholds.stalled_after_days"].Raw)
+				assert.Equal(t, 8, f.settings["thresholds.stalled_after_days"].Source.Line)
+				assert.Equal(t, "expertise", f.settings["strategies.reviewer"].Raw)
+				assert.Equal(t, "work-token", f.profiles["work"]["token"].Raw)
+			},
+		},
+		{
+			name:    "empty file",
+			content: "",
+			validate: func(t *testing.T, f *file) {
+				assert.Empty(t, f.settings)
+			},
+		},
+		{
+			name:        "unknown setting",
+			content:     "base_url: https://gitlab.com\nreviewers: [alice]\n",
+			expectError: `config.yaml:2: unknown setting "reviewers"`,
+		},
+		{
+			name: "named teams",
+			content: `teams:
+  backend: [alice, "@acme/backend"]
+project_teams:
+  acme/api.v2: backend
+`,
+			validate: func(t *testing.T, f *file) {
+				assert.Equal(t, "alice,@acme/backend", f.settings["teams.backend"].Raw)
+				assert.Equal(t, "backend", f.settings["project_teams.acme/api.v2"].Raw)
+			},
+		},
+		{
+			name:        "list of teams",
+			content:     "teams: [alice]\n",
+			expectError: "config.yaml:1: teams must be a mapping",
+		},
+		{
+			name:        "unknown nested setting",
+			content:     "thresholds:\n  stale: 3\n",
+			expectError: `config.yaml:2: unknown setting "thresholds.stale"`,
+		},
+		{
+			name:        "list for scalar setting",
+			content:     "token:\n  - a\n",
+			expectError: "config.yaml:2: token must be a string",
+		},
+		{
+			name:        "nested profiles",
+			content:     "profiles:\n  work:\n    profiles: {}\n",
+			expectError: `config.yaml:3: unknown setting "profiles"`,
+		},
+		{
+			name:        "invalid YAML",
+			content:     "team: [alice\n",
+			expectError: "config.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseFile("config.yaml", []byte(tt.content))
+
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			tt.validate(t, f)
+		})
+	}
+}
+
+func TestLoad_Layers(t *testing.T) {
+	for _, def := range settingDefs {
+		t.Setenv(envPrefix+def.Env, "")
+	}
+
+	userFile := writeFile(t, "config.yaml", `token: user-token
+team: [alice, bob]
+api: graphql
+thresholds:
+  stalled_after_days: 5
+`)
+	repoFile := writeFile(t, ".gg.yaml", `team: [carol]
+strategies:
+  assignee: nope
+`)
+	t.Setenv("GG_API", "rest")
+	t.Setenv("GG_ASSIGNEE_STRATEGY", "workload")
+
+	cfg, err := load("", "", nil, nil, userFile, repoFile, filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "user-token", cfg.Token)
+	assert.Equal(t, []string{"carol"}, cfg.TeamUsers, "repo file overrides user file")
+	assert.Equal(t, APIREST, cfg.API, "environment overrides files")
+	assert.Equal(t, 5, cfg.StalledAfterDays)
+	assert.Equal(t, StrategyWorkload, cfg.AssigneeStrategy)
+	assert.Equal(t, StrategyWorkload, cfg.ReviewerStrategy)
+
+	assert.Equal(t, userFile+":1", cfg.Source("token").String())
+	assert.Equal(t, repoFile+":1", cfg.Source("team").String())
+	assert.Equal(t, "GG_API", cfg.Source("api").String())
+	assert.Equal(t, "default", cfg.Source("strategies.reviewer").String())
+	assert.Equal(t, "default", cfg.Source("base_url").String())
+
+	t.Setenv("GG_ASSIGNEE_STRATEGY", "")
+
+	_, err = load("", "", nil, nil, userFile, repoFile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), repoFile+":3: strategies.assignee")
+}
+
+func TestLoad_ProfileCase(t *testing.T) {
+	for _, def := range settingDefs {
+		t.Setenv(envPrefix+def.Env, "")
+	}
+
+	userFile := writeFile(t, "config.yaml", `token: user-token
+team: [alice]
+profiles:
+  Work:
+    token: work-token
+`)
+
+	cfg, err := load("", "Work", nil, nil, userFile)
+	require.NoError(t, err, "profiles are selected in any case")
+	assert.Equal(t, "work", cfg.Profile)
+	assert.Equal(t, "work-token", cfg.Token)
+
+	work, err := cfg.ForProfile("WORK")
+	require.NoError(t, err)
+	assert.Equal(t, "work-token", work.Token)
+}
+
+func TestLoad_ProfileEnv(t *testing.T) {
+	for _, def := range settingDefs {
+		t.Setenv(envPrefix+def.Env, "")
+	}
+
+	userFile := writeFile(t, "config.yaml", `token: user-token
+team: [alice]
+profiles:
+  work:
+    token: work-token
+    api: graphql
+    thresholds:
+      stalled_after_days: 5
+`)
+	t.Setenv("GG_API", "rest")
+	t.Setenv("GG_STALLED_AFTER_DAYS", "4")
+	t.Setenv("GG_PROFILE_WORK_STALLED_AFTER_DAYS", "7")
+
+	cfg, err := load("", "work", nil, nil, userFile)
+	require.NoError(t, err)
+	assert.Equal(t, "work-token", cfg.Token)
+	assert.Equal(t, APIREST, cfg.API, "environment overrides profile files")
+	assert.Equal(t, "GG_API", cfg.Source("api").String())
+	assert.Equal(t, 7, cfg.StalledAfterDays, "profile environment overrides the environment")
+}
+
+func TestLoad_RepoFile(t *testing.T) {
+	for _, def := range settingDefs {
+		t.Setenv(envPrefix+def.Env, "")
+	}
+	t.Setenv("GG_TOKEN", "token")
+
+	userFile := writeFile(t, "config.yaml", "team: [alice]\n")
+
+	tests := []struct {
+		name        string
+		content     string
+		expectError string
+	}{
+		{
+			name:        "base url",
+			content:     "team: [bob]\nbase_url: https://evil.example.com\n",
+			expectError: ":2: base_url cannot be set in .gg.yaml",
+		},
+		{
+			name:        "token",
+			content:     "token: stolen\n",
+			expectError: ":1: token cannot be set in .gg.yaml",
+		},
+		{
+			name:        "nested setting",
+			content:     "templates:\n  dir: /tmp/templates\n",
+			expectError: ":2: templates.dir cannot be set in .gg.yaml",
+		},
+		{
+			name:        "profiles",
+			content:     "profiles:\n  work:\n    base_url: https://evil.example.com\n",
+			expectError: ":1: profiles cannot be set in .gg.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoFile := writeFile(t, RepoFileName, tt.content)
+
+			_, err := load("", "", nil, nil, userFile, repoFile)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), repoFile+tt.expectError)
+		})
+	}
+
+	repoFile := writeFile(t, RepoFileName, `team: [bob]
+teams:
+  backend: [carol]
+project_teams:
+  acme/api: backend
+rules:
+  acme/*:
+    team: backend
+templates:
+  issue_url: https://jira.example.com/browse/{{.Issue}}
+thresholds:
+  stalled_after_days: 3
+strategies:
+  reviewer: workload
+`)
+
+	cfg, err := load("", "", nil, nil, userFile, repoFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, cfg.TeamUsers)
+	assert.Equal(t, 3, cfg.StalledAfterDays)
+}
+
+func TestLoad_Teams(t *testing.T) {
+	for _, def := range settingDefs {
+		t.Setenv(envPrefix+def.Env, "")
+	}
+	t.Setenv("GG_TOKEN", "token")
+
+	path := writeFile(t, "config.yaml", `teams:
+  backend: [alice, "@acme/backend"]
+  frontend: [bob]
+project_teams:
+  acme/api: backend
+`)
+
+	cfg, err := load("", "", nil, nil, path)
+	require.NoError(t, err)
+
+	assert.Empty(t, cfg.TeamUsers, "named teams make the default team optional")
+	assert.Equal(t, map[string][]string{
+		"backend":  {"alice", "@acme/backend"},
+		"frontend": {"bob"},
+	}, cfg.Teams)
+	assert.Equal(t, map[string]string{"acme/api": "backend"}, cfg.ProjectTeams)
+
+	broken := writeFile(t, ".gg.yaml", "project_teams:\n  acme/web: web\n")
+
+	_, err = load("", "", nil, nil, path, broken)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), broken+`:2: project acme/web refers to unknown team "web"`)
+}
+
+func TestLoad_TemplateDir(t *testing.T) {
+	for _, def := range settingDefs {
+		t.Setenv(envPrefix+def.Env, "")
+	}
+	t.Setenv("GG_TOKEN", "token")
+	t.Setenv("GG_TEAM", "alice")
+
+	path := writeFile(t, "config.yaml", "templates:\n  dir: .gg/templates\n")
+
+	cfg, err := load("", "", nil, nil, path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), ".gg/templates"), cfg.TemplateDir,
+		"relative to the config file")
+
+	t.Setenv("GG_TEMPLATE_DIR", "templates")
+
+	cfg, err = load("", "", nil, nil, path)
+	require.NoError(t, err)
+	assert.Equal(t, "templates", cfg.TemplateDir, "environment values are kept as given")
+}
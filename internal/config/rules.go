@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// anyDepthSuffix ends patterns that match projects at any depth below a group.
+const anyDepthSuffix = "/**"
+
+// RouletteRule customizes automatic assignment by the webhook server for the
+// projects matching Pattern.
+type RouletteRule struct {
+	// Pattern is a project path, a glob such as "acme/*", or a group path
+	// followed by "/**", which matches every project below the group.
+	Pattern string
+	// Team is the named team to draw from, empty for the default team of
+	// the project.
+	Team string
+	// Reviewers is the number of reviewers to request.
+	Reviewers int
+	// Assignee tells whether an assignee is set.
+	Assignee bool
+	// SkipLabels lists labels that disable automatic assignment.
+	SkipLabels []string
+	// ExtraReviewerBranches lists target branch globs that require one more reviewer.
+	ExtraReviewerBranches []string
+}
+
+// Matches reports whether the rule applies to the project at projectPath.
+func (r *RouletteRule) Matches(projectPath string) bool {
+	if group, ok := strings.CutSuffix(r.Pattern, anyDepthSuffix); ok {
+		return strings.HasPrefix(projectPath, group+"/")
+	}
+
+	matched, _ := path.Match(r.Pattern, projectPath)
+
+	return matched
+}
+
+// Skips reports whether any of labels disables automatic assignment.
+func (r *RouletteRule) Skips(labels []string) bool {
+	for _, label := range labels {
+		for _, skip := range r.SkipLabels {
+			if strings.EqualFold(label, skip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ReviewersFor returns the number of reviewers to request for a merge request
+// into targetBranch.
+func (r *RouletteRule) ReviewersFor(targetBranch string) int {
+	for _, pattern := range r.ExtraReviewerBranches {
+		if matched, _ := path.Match(pattern, targetBranch); matched {
+			return r.Reviewers + 1
+		}
+	}
+
+	return r.Reviewers
+}
+
+// DefaultRouletteRule is applied to projects no rule matches: one assignee and
+// one reviewer from the default team of the project.
+func DefaultRouletteRule() *RouletteRule {
+	return &RouletteRule{Reviewers: 1, Assignee: true}
+}
+
+// RouletteRules are ordered from the most to the least specific pattern.
+type RouletteRules []*RouletteRule
+
+// Match returns the most specific rule for the project at projectPath, or
+// the default rule when none matches.
+func (r RouletteRules) Match(projectPath string) *RouletteRule {
+	for _, rule := range r {
+		if rule.Matches(projectPath) {
+			return rule
+		}
+	}
+
+	return DefaultRouletteRule()
+}
+
+// rouletteRules builds and validates the rules section.
+func rouletteRules(s settings, teams map[string][]string) (RouletteRules, error) {
+	var rules RouletteRules
+	for pattern, fields := range s.mapFields("rules") {
+		rule := DefaultRouletteRule()
+		rule.Pattern = pattern
+
+		if _, err := path.Match(strings.TrimSuffix(pattern, anyDepthSuffix), ""); err != nil {
+			return nil, fmt.Errorf("rule %q: invalid pattern: %w", pattern, err)
+		}
+
+		if team, ok := fields["team"]; ok {
+			if _, exists := teams[team.Raw]; !exists {
+				return nil, fmt.Errorf("%s: rule %q refers to unknown team %q", team.Source, pattern, team.Raw)
+			}
+			rule.Team = team.Raw
+		}
+
+		if reviewers, ok := fields["reviewers"]; ok {
+			n, err := strconv.Atoi(reviewers.Raw)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%s: reviewers must be zero or a positive number, got %q",
+					reviewers.Source, reviewers.Raw)
+			}
+			rule.Reviewers = n
+		}
+
+		if assignee, ok := fields["assignee"]; ok {
+			set, err := strconv.ParseBool(assignee.Raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: assignee must be true or false, got %q", assignee.Source, assignee.Raw)
+			}
+			rule.Assignee = set
+		}
+
+		rule.SkipLabels = splitList(fields["skip_labels"].Raw)
+		rule.ExtraReviewerBranches = splitList(fields["extra_reviewer_branches"].Raw)
+		for _, branch := range rule.ExtraReviewerBranches {
+			if _, err := path.Match(branch, ""); err != nil {
+				return nil, fmt.Errorf("rule %q: invalid branch pattern %q: %w", pattern, branch, err)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return moreSpecific(rules[i].Pattern, rules[j].Pattern)
+	})
+
+	return rules, nil
+}
+
+// moreSpecific orders patterns: exact project paths first, then longer
+// patterns before shorter ones.
+func moreSpecific(a, b string) bool {
+	exactA, exactB := !strings.ContainsAny(a, "*?["), !strings.ContainsAny(b, "*?[")
+	if exactA != exactB {
+		return exactA
+	}
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+
+	return a < b
+}
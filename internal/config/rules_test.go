@@ -0,0 +1,104 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_RouletteRules(t *testing.T) {
+	for _, def := range settingDefs {
+		t.Setenv(envPrefix+def.Env, "")
+	}
+	t.Setenv("GG_TOKEN", "token")
+
+	path := writeFile(t, "config.yaml", `team: [alice]
+teams:
+  web: [bob, carol]
+rules:
+  acme/**:
+    reviewers: 2
+  acme/web/*:
+    team: web
+    assignee: false
+    skip_labels: [no-roulette]
+    extra_reviewer_branches: [main, release/*]
+  acme/web/app.v2:
+    reviewers: 0
+`)
+
+	cfg, err := load("", "", nil, nil, path)
+	require.NoError(t, err)
+
+	patterns := make([]string, 0, len(cfg.RouletteRules))
+	for _, rule := range cfg.RouletteRules {
+		patterns = append(patterns, rule.Pattern)
+	}
+	assert.Equal(t, []string{"acme/web/app.v2", "acme/web/*", "acme/**"}, patterns, "most specific first")
+
+	web := cfg.RouletteRules.Match("acme/web/site")
+	assert.Equal(t, "acme/web/*", web.Pattern)
+	assert.Equal(t, "web", web.Team)
+	assert.False(t, web.Assignee)
+	assert.Equal(t, 1, web.ReviewersFor("feature"))
+	assert.Equal(t, 2, web.ReviewersFor("release/1.0"))
+	assert.True(t, web.Skips([]string{"bug", "No-Roulette"}))
+	assert.False(t, web.Skips([]string{"bug"}))
+
+	nested := cfg.RouletteRules.Match("acme/infra/terraform/modules")
+	assert.Equal(t, "acme/**", nested.Pattern)
+	assert.Equal(t, 2, nested.Reviewers)
+	assert.True(t, nested.Assignee)
+
+	assert.Equal(t, 0, cfg.RouletteRules.Match("acme/web/app.v2").Reviewers)
+	assert.Equal(t, DefaultRouletteRule(), cfg.RouletteRules.Match("other/project"))
+}
+
+func TestLoad_RouletteRulesInvalid(t *testing.T) {
+	for _, def := range settingDefs {
+		t.Setenv(envPrefix+def.Env, "")
+	}
+	t.Setenv("GG_TOKEN", "token")
+	t.Setenv("GG_TEAM", "alice")
+
+	tests := []struct {
+		name        string
+		content     string
+		expectError string
+	}{
+		{
+			name:        "unknown team",
+			content:     "rules:\n  acme/*:\n    team: web\n",
+			expectError: `config.yaml:3: rule "acme/*" refers to unknown team "web"`,
+		},
+		{
+			name:        "negative reviewers",
+			content:     "rules:\n  acme/*:\n    reviewers: -1\n",
+			expectError: "config.yaml:3: reviewers must be zero or a positive number",
+		},
+		{
+			name:        "invalid assignee",
+			content:     "rules:\n  acme/*:\n    assignee: maybe\n",
+			expectError: "config.yaml:3: assignee must be true or false",
+		},
+		{
+			name:        "invalid pattern",
+			content:     "rules:\n  acme/[:\n    reviewers: 1\n",
+			expectError: `rule "acme/[": invalid pattern`,
+		},
+		{
+			name:        "unknown field",
+			content:     "rules:\n  acme/*:\n    approvers: 1\n",
+			expectError: `config.yaml:3: unknown setting "rules.acme/*.approvers"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load("", "", nil, nil, writeFile(t, "config.yaml", tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectError)
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// setting describes a configuration value that can be set in a config file,
+// through the environment or, for some, with a command line flag.
+type setting struct {
+	// Key is the dotted path of the setting in a config file.
+	Key string
+	// Env is the environment variable suffix after GG_ or GG_PROFILE_<NAME>_.
+	Env string
+	// Flag is the name of the command line flag setting it, with its usage.
+	Flag      string
+	FlagUsage string
+	// List marks settings that accept a YAML sequence in config files.
+	List bool
+	// Fields lists the settings of every entry of a map section.
+	Fields []setting
+	// Repo marks settings that a repository config file may set. The others
+	// could point the token at another server or leak it, so only the user
+	// config file and the environment can set them.
+	Repo bool
+}
+
+// settingDefs lists every supported setting.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var settingDefs = []setting{
+	{Key: "base_url", Env: "BASE_URL", Flag: "base-url", FlagUsage: "Base URL of the instance (overrides GG_BASE_URL)"},
+	{Key: "token", Env: "TOKEN"},
+	{Key: "oauth.client_id", Env: "OAUTH_CLIENT_ID"},
+	{
+		Key: "backend", Env: "BACKEND",
+		Flag: "backend", FlagUsage: "Backend: gitlab, github or gitea (overrides GG_BACKEND)",
+	},
+	{Key: "api", Env: "API", Flag: "api", FlagUsage: "GitLab API: rest or graphql (overrides GG_API)"},
+	{Key: "webhook_address", Env: "WEBHOOK_ADDRESS"},
+	{
+		Key: "team", Env: "TEAM", List: true, Repo: true,
+		Flag:      "default-team-members",
+		FlagUsage: "Comma-separated members of the default team, used without --team (overrides GG_TEAM)",
+	},
+	{
+		Key: "templates.issue_url", Env: "ISSUE_URL_TEMPLATE", Repo: true,
+		Flag: "issue-url", FlagUsage: "Issue URL template with {{.Issue}} (overrides GG_ISSUE_URL_TEMPLATE)",
+	},
+	{Key: "templates.dir", Env: "TEMPLATE_DIR"},
+	{Key: "thresholds.stalled_after_days", Env: "STALLED_AFTER_DAYS", Repo: true},
+	{Key: "strategies.assignee", Env: "ASSIGNEE_STRATEGY", Repo: true},
+	{Key: "strategies.reviewer", Env: "REVIEWER_STRATEGY", Repo: true},
+}
+
+// mapSettingDefs lists sections whose entries are named by the user, such as
+// "teams.backend". The key of a definition is the section; entries are only
+// read from config files. Entries of sections with Fields are mappings of
+// those fields, such as "rules.acme/*.team".
+//
+//nolint:gochecknoglobals // read-only lookup table
+var mapSettingDefs = []setting{
+	{Key: "teams", List: true, Repo: true},
+	{Key: "project_teams", Repo: true},
+	{Key: "rules", Repo: true, Fields: []setting{
+		{Key: "team"},
+		{Key: "reviewers"},
+		{Key: "assignee"},
+		{Key: "skip_labels", List: true},
+		{Key: "extra_reviewer_branches", List: true},
+	}},
+}
+
+// mapKey is the key of a map section entry split into its parts.
+type mapKey struct {
+	section setting
+	// name is the entry name, which may itself contain dots, like a project path.
+	name string
+	// field is set for sections with Fields.
+	field setting
+}
+
+// lookupSetting finds a setting by its key. For entries of a map section, the
+// returned definition carries the full key.
+func lookupSetting(key string) (setting, bool) {
+	for _, def := range settingDefs {
+		if def.Key == key {
+			return def, true
+		}
+	}
+
+	if mk, ok := splitMapKey(key); ok {
+		def := mk.section
+		if len(def.Fields) > 0 {
+			def = mk.field
+		}
+		def.Key = key
+		def.Repo = mk.section.Repo
+
+		return def, true
+	}
+
+	return setting{}, false
+}
+
+// splitMapKey splits the key of a map section entry into its parts.
+func splitMapKey(key string) (mapKey, bool) {
+	for _, def := range mapSettingDefs {
+		name, ok := strings.CutPrefix(key, def.Key+".")
+		if !ok || name == "" {
+			continue
+		}
+
+		if len(def.Fields) == 0 {
+			return mapKey{section: def, name: name}, true
+		}
+
+		i := strings.LastIndex(name, ".")
+		if i <= 0 {
+			continue
+		}
+		for _, field := range def.Fields {
+			if field.Key == name[i+1:] {
+				return mapKey{section: def, name: name[:i], field: field}, true
+			}
+		}
+	}
+
+	return mapKey{}, false
+}
+
+// isSection reports whether key is a prefix of nested settings, like
+// "templates" or "teams".
+func isSection(key string) bool {
+	for _, def := range settingDefs {
+		if strings.HasPrefix(def.Key, key+".") {
+			return true
+		}
+	}
+
+	for _, def := range mapSettingDefs {
+		if def.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isMapEntry reports whether key names an entry of a map section with fields,
+// like "rules.acme/*".
+func isMapEntry(key string) bool {
+	for _, def := range mapSettingDefs {
+		if name, ok := strings.CutPrefix(key, def.Key+"."); ok && name != "" && len(def.Fields) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mapEntries returns the entries of a map section in s keyed by their names.
+func (s settings) mapEntries(section string) map[string]value {
+	entries := make(map[string]value)
+	for key, v := range s {
+		if mk, ok := splitMapKey(key); ok && mk.section.Key == section {
+			entries[mk.name] = v
+		}
+	}
+
+	return entries
+}
+
+// mapFields returns the entries of a map section with fields in s, keyed by
+// entry name and then by field.
+func (s settings) mapFields(section string) map[string]settings {
+	entries := make(map[string]settings)
+	for key, v := range s {
+		mk, ok := splitMapKey(key)
+		if !ok || mk.section.Key != section {
+			continue
+		}
+		if entries[mk.name] == nil {
+			entries[mk.name] = settings{}
+		}
+		entries[mk.name][mk.field.Key] = v
+	}
+
+	return entries
+}
+
+// Source describes where a configuration value came from.
+type Source struct {
+	// Name is a file path, an environment variable name, a command line flag
+	// or "default".
+	Name string
+	// Line is the line within the file, zero for other sources.
+	Line int
+}
+
+func (s Source) String() string {
+	if s.Line > 0 {
+		return fmt.Sprintf("%s:%d", s.Name, s.Line)
+	}
+
+	return s.Name
+}
+
+// value is a raw configuration value with its source. List values are kept
+// comma-separated, the same way they are written in environment variables.
+type value struct {
+	Raw    string
+	Source Source
+}
+
+// settings maps setting keys to raw values of a single configuration layer.
+type settings map[string]value
+
+// merge returns a copy of s overridden by every value present in other.
+func (s settings) merge(other settings) settings {
+	merged := make(settings, len(s)+len(other))
+	for key, v := range s {
+		merged[key] = v
+	}
+	for key, v := range other {
+		merged[key] = v
+	}
+
+	return merged
+}
+
+// defaultSettings holds the defaults that do not depend on other settings.
+func defaultSettings() settings {
+	defaultSource := Source{Name: "default"}
+
+	return settings{
+		"webhook_address":               {Raw: ":8080", Source: defaultSource},
+		"api":                           {Raw: APIREST, Source: defaultSource},
+		"thresholds.stalled_after_days": {Raw: "3", Source: defaultSource},
+		"strategies.assignee":           {Raw: StrategyExpertise, Source: defaultSource},
+		"strategies.reviewer":           {Raw: StrategyWorkload, Source: defaultSource},
+	}
+}
+
+// envSettings reads settings from environment variables with the given prefix.
+func envSettings(prefix string) settings {
+	s := make(settings)
+	for _, def := range settingDefs {
+		name := prefix + def.Env
+		if raw := os.Getenv(name); raw != "" {
+			s[def.Key] = value{Raw: raw, Source: Source{Name: name}}
+		}
+	}
+
+	return s
+}
+
+// envProfiles discovers profiles from GG_PROFILE_<NAME>_<SETTING> environment
+// variables, e.g. GG_PROFILE_WORK_TOKEN. Profile names are lowercased.
+func envProfiles() map[string]settings {
+	profiles := make(map[string]settings)
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		rest, ok := strings.CutPrefix(name, profileEnvPrefix)
+		if !ok {
+			continue
+		}
+
+		for _, def := range settingDefs {
+			profile, ok := strings.CutSuffix(rest, "_"+def.Env)
+			if !ok || profile == "" {
+				continue
+			}
+
+			profile = strings.ToLower(profile)
+			if _, exists := profiles[profile]; !exists {
+				profiles[profile] = envSettings(profileEnvPrefix + strings.ToUpper(profile) + "_")
+			}
+
+			break
+		}
+	}
+
+	return profiles
+}
+
+// Flag is a command line flag that sets a setting.
+type Flag struct {
+	Name  string
+	Usage string
+}
+
+// Flags returns the command line flags that set settings. They have to be
+// declared on the flag set given to New.
+func Flags() []Flag {
+	var flags []Flag
+	for _, def := range settingDefs {
+		if def.Flag != "" {
+			flags = append(flags, Flag{Name: def.Flag, Usage: def.FlagUsage})
+		}
+	}
+
+	return flags
+}
+
+// flagSettings reads settings from the command line flags set in flags.
+func flagSettings(flags *pflag.FlagSet) settings {
+	s := make(settings)
+	for _, def := range settingDefs {
+		if def.Flag == "" {
+			continue
+		}
+		if raw := flagValue(flags, def.Flag); raw != "" {
+			s[def.Key] = value{Raw: raw, Source: Source{Name: "--" + def.Flag}}
+		}
+	}
+
+	return s
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os/exec"
 	"sort"
 	"strings"
 	"sync"
@@ -12,46 +11,78 @@ import (
 
 	"github.com/denchenko/gg/internal/config"
 	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/git"
+	"github.com/denchenko/gg/internal/log"
 	"golang.org/x/sync/errgroup"
 )
 
-const workingDaysThreshold = 3
+const (
+	workingDaysThreshold = 3
+	// fetchLimit caps the requests made in parallel for the merge requests of a listing.
+	fetchLimit = 8
+)
 
 // Repository defines the interface for data persistence operations (port).
 type Repository interface {
 	GetProject(ctx context.Context, path string) (*domain.Project, error)
-	ListMergeRequests(ctx context.Context, state string, scope ...string) ([]*domain.MergeRequest, error)
-	GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) ([]*domain.User, error)
+	ListMergeRequests(ctx context.Context, query *domain.MergeRequestQuery) ([]*domain.MergeRequest, error)
+	GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) (*domain.Approvals, error)
+	GetMergeRequestApprovalRules(ctx context.Context, projectID, mrID int) ([]*domain.ApprovalRule, error)
+	ListMergeRequestDiscussions(ctx context.Context, projectID, mrID int) ([]*domain.Discussion, error)
 	GetMergeRequest(ctx context.Context, projectID, mrID int) (*domain.MergeRequest, error)
 	PreloadUsersByUsernames(ctx context.Context, usernames []string) error
 	GetAllUsers(ctx context.Context) ([]*domain.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
+	ListGroupMembers(ctx context.Context, groupPath string) ([]*domain.User, error)
 	GetCurrentUser(ctx context.Context) (*domain.User, error)
+	GetTokenInfo(ctx context.Context) (*domain.TokenInfo, error)
 	ListCommits(ctx context.Context, projectID int) ([]*domain.Commit, error)
 	UpdateMergeRequest(ctx context.Context, projectID, mrID int, assigneeID *int, reviewerIDs []int) error
+	CreateMergeRequest(ctx context.Context, projectID int, opts *domain.NewMergeRequest) (*domain.MergeRequest, error)
+	ApproveMergeRequest(ctx context.Context, projectID, mrID int) error
+	UnapproveMergeRequest(ctx context.Context, projectID, mrID int) error
+	MergeMergeRequest(ctx context.Context, projectID, mrID int, opts *domain.MergeOptions) error
+	RebaseMergeRequest(ctx context.Context, projectID, mrID int) error
 	GetUserEvents(ctx context.Context, userID int, since time.Time, till *time.Time) ([]*domain.Event, error)
 }
 
 // App represents the core application with all business logic.
 type App struct {
-	repo      Repository
-	teamUsers []string
+	repo             Repository
+	teamUsers        []string
+	teams            map[string][]string
+	projectTeams     map[string]string
+	directory        *teamDirectory
+	stalledAfterDays int
+	assigneeStrategy string
+	reviewerStrategy string
 }
 
 // NewApp creates a new application instance.
 func NewApp(cfg *config.Config, repo Repository) (*App, error) {
 	ctx := context.Background()
 
-	if err := repo.PreloadUsersByUsernames(ctx, cfg.TeamUsers); err != nil {
-		fmt.Printf("Warning: failed to preload users: %v\n", err)
+	directory := &teamDirectory{}
+	if err := directory.preload(ctx, repo, plainUsernames(cfg.TeamUsers, cfg.Teams)); err != nil {
+		log.Warnf("%v", err)
 	}
 
 	return &App{
-		repo:      repo,
-		teamUsers: cfg.TeamUsers,
+		repo:             repo,
+		teamUsers:        cfg.TeamUsers,
+		teams:            cfg.Teams,
+		projectTeams:     cfg.ProjectTeams,
+		directory:        directory,
+		stalledAfterDays: cfg.StalledAfterDays,
+		assigneeStrategy: cfg.AssigneeStrategy,
+		reviewerStrategy: cfg.ReviewerStrategy,
 	}, nil
 }
 
+// Factory creates an application instance for a configuration other than the
+// active one, e.g. another profile.
+type Factory func(cfg *config.Config) (*App, error)
+
 // AnalyzeWorkload analyzes the workload for team members.
 func (a *App) AnalyzeWorkload(ctx context.Context, projectID int) ([]*domain.UserWorkload, error) {
 	emailToUserID, err := a.buildEmailToUserIDMap(ctx)
@@ -64,18 +95,18 @@ func (a *App) AnalyzeWorkload(ctx context.Context, projectID int) ([]*domain.Use
 		return nil, err
 	}
 
-	mrs, err := a.repo.ListMergeRequests(ctx, "opened", "all")
+	members, err := a.teamMembers(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get merge requests: %w", err)
+		return nil, err
 	}
 
-	workloads := make([]*domain.UserWorkload, 0, len(a.teamUsers))
-	for _, username := range a.teamUsers {
-		user, err := a.repo.GetUserByUsername(ctx, username)
-		if err != nil {
-			continue
-		}
+	mrs, err := a.repo.ListMergeRequests(ctx, openedMergeRequests(domain.ScopeAll))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge requests: %w", err)
+	}
 
+	workloads := make([]*domain.UserWorkload, 0, len(members))
+	for _, user := range members {
 		activeMRCount := 0
 		for _, mr := range mrs {
 			if !isUserInvolvedInMR(mr, user.ID) {
@@ -87,7 +118,7 @@ func (a *App) AnalyzeWorkload(ctx context.Context, projectID int) ([]*domain.Use
 				continue
 			}
 
-			if !hasUserApprovedMR(approvals, user.ID) {
+			if !hasUserApprovedMR(approvals.ApprovedBy, user.ID) {
 				activeMRCount++
 			}
 		}
@@ -104,18 +135,18 @@ func (a *App) AnalyzeWorkload(ctx context.Context, projectID int) ([]*domain.Use
 
 // AnalyzeActiveMRs analyzes active merge requests for team members.
 func (a *App) AnalyzeActiveMRs(ctx context.Context) ([]*domain.UserWorkload, error) {
-	mrs, err := a.repo.ListMergeRequests(ctx, "opened", "all")
+	members, err := a.teamMembers(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get merge requests: %w", err)
+		return nil, err
 	}
 
-	workloads := make([]*domain.UserWorkload, 0, len(a.teamUsers))
-	for _, username := range a.teamUsers {
-		user, err := a.repo.GetUserByUsername(ctx, username)
-		if err != nil {
-			continue
-		}
+	mrs, err := a.repo.ListMergeRequests(ctx, openedMergeRequests(domain.ScopeAll))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge requests: %w", err)
+	}
 
+	workloads := make([]*domain.UserWorkload, 0, len(members))
+	for _, user := range members {
 		var relevantMRs []*domain.MergeRequest
 		for _, mr := range mrs {
 			if isUserInvolvedInMR(mr, user.ID) {
@@ -131,7 +162,7 @@ func (a *App) AnalyzeActiveMRs(ctx context.Context) ([]*domain.UserWorkload, err
 		activeMRCount := 0
 		var activeMRs []*domain.MergeRequest
 		for _, mr := range relevantMRs {
-			if !hasUserApprovedMR(approvalsMap[mr.IID], user.ID) {
+			if !hasUserApprovedMR(approvalsMap[mr.Key()], user.ID) {
 				activeMRCount++
 				activeMRs = append(activeMRs, mr)
 			}
@@ -147,7 +178,8 @@ func (a *App) AnalyzeActiveMRs(ctx context.Context) ([]*domain.UserWorkload, err
 	return workloads, nil
 }
 
-func (a *App) getCurrentUser(ctx context.Context) (*domain.User, error) {
+// GetCurrentUser returns the user the configured token belongs to.
+func (a *App) GetCurrentUser(ctx context.Context) (*domain.User, error) {
 	user, err := a.repo.GetCurrentUser(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current user: %w", err)
@@ -156,12 +188,100 @@ func (a *App) getCurrentUser(ctx context.Context) (*domain.User, error) {
 	return user, nil
 }
 
+// GetTokenInfo returns the owner, scopes and expiry of the configured token.
+func (a *App) GetTokenInfo(ctx context.Context) (*domain.TokenInfo, error) {
+	info, err := a.repo.GetTokenInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token info: %w", err)
+	}
+
+	return info, nil
+}
+
+// TeamMemberCheck is the result of resolving a configured team member.
+type TeamMemberCheck struct {
+	// Team is the name of the team, empty for the default team.
+	Team string
+	// Username is a username or a group reference starting with "@".
+	Username string
+	// User is nil for groups.
+	User *domain.User
+	Err  error
+}
+
+// CheckTeamMembers resolves every member of the default team and all named
+// teams. Groups are checked by listing their members.
+func (a *App) CheckTeamMembers(ctx context.Context) []*TeamMemberCheck {
+	checks := make([]*TeamMemberCheck, 0, len(a.teamUsers))
+	check := func(team string, entries []string) {
+		for _, entry := range entries {
+			if group, ok := strings.CutPrefix(entry, groupPrefix); ok {
+				users, err := a.repo.ListGroupMembers(ctx, group)
+				if err == nil && len(users) == 0 {
+					err = errors.New("group has no members")
+				}
+				if err != nil {
+					err = fmt.Errorf("failed to list group members: %w", err)
+				}
+
+				checks = append(checks, &TeamMemberCheck{Team: team, Username: entry, Err: err})
+
+				continue
+			}
+
+			user, err := a.repo.GetUserByUsername(ctx, entry)
+			if err != nil {
+				err = fmt.Errorf("failed to get user: %w", err)
+			}
+
+			checks = append(checks, &TeamMemberCheck{Team: team, Username: entry, User: user, Err: err})
+		}
+	}
+
+	check("", a.teamUsers)
+	for _, name := range a.Teams() {
+		check(name, a.teams[name])
+	}
+
+	return checks
+}
+
 // SuggestAssigneeAndReviewer suggests an assignee and reviewer for a merge request.
 func (a *App) SuggestAssigneeAndReviewer(
-	_ context.Context,
+	ctx context.Context,
 	mr *domain.MergeRequest,
 	workloads []*domain.UserWorkload,
 ) (*domain.User, *domain.User, error) {
+	assignee, reviewers, err := a.SuggestAssignment(ctx, mr, workloads, RouletteOptions{Reviewers: 1})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reviewer *domain.User
+	if len(reviewers) > 0 {
+		reviewer = reviewers[0]
+	}
+
+	return assignee, reviewer, nil
+}
+
+// RouletteOptions controls who SuggestAssignment picks.
+type RouletteOptions struct {
+	// SkipAssignee leaves the assignee out, so that every candidate can review.
+	SkipAssignee bool
+	// Reviewers is the number of reviewers to pick.
+	Reviewers int
+}
+
+// SuggestAssignment suggests an assignee and up to opts.Reviewers distinct
+// reviewers for a merge request. Fewer reviewers are returned when the team
+// has not enough available members.
+func (a *App) SuggestAssignment(
+	_ context.Context,
+	mr *domain.MergeRequest,
+	workloads []*domain.UserWorkload,
+	opts RouletteOptions,
+) (*domain.User, []*domain.User, error) {
 	if len(workloads) == 0 {
 		return nil, nil, errors.New("no team members available")
 	}
@@ -177,37 +297,36 @@ func (a *App) SuggestAssigneeAndReviewer(
 		return nil, nil, errors.New("no available team members")
 	}
 
-	sort.Slice(availableWorkloads, func(i, j int) bool {
-		scoreI := calculateAssigneeScore(availableWorkloads[i].Commits, availableWorkloads[i].MRCount)
-		scoreJ := calculateAssigneeScore(availableWorkloads[j].Commits, availableWorkloads[j].MRCount)
-
-		return scoreI > scoreJ
-	})
+	isAuthor := func(user *domain.User) bool {
+		return mr.Author != nil && user.ID == mr.Author.ID
+	}
 
 	var suggestedAssignee *domain.User
-	for _, workload := range availableWorkloads {
-		if mr.Author == nil || workload.User.ID != mr.Author.ID {
-			suggestedAssignee = workload.User
+	if !opts.SkipAssignee {
+		sortWorkloads(availableWorkloads, a.assigneeStrategy, config.StrategyExpertise)
 
-			break
+		for _, workload := range availableWorkloads {
+			if !isAuthor(workload.User) {
+				suggestedAssignee = workload.User
+
+				break
+			}
 		}
 	}
 
-	sort.Slice(availableWorkloads, func(i, j int) bool {
-		return availableWorkloads[i].MRCount < availableWorkloads[j].MRCount
-	})
+	sortWorkloads(availableWorkloads, a.reviewerStrategy, config.StrategyWorkload)
 
-	var suggestedReviewer *domain.User
+	suggestedReviewers := make([]*domain.User, 0, opts.Reviewers)
 	for _, workload := range availableWorkloads {
-		if (mr.Author == nil || workload.User.ID != mr.Author.ID) &&
-			(suggestedAssignee == nil || workload.User.ID != suggestedAssignee.ID) {
-			suggestedReviewer = workload.User
-
+		if len(suggestedReviewers) == opts.Reviewers {
 			break
 		}
+		if !isAuthor(workload.User) && (suggestedAssignee == nil || workload.User.ID != suggestedAssignee.ID) {
+			suggestedReviewers = append(suggestedReviewers, workload.User)
+		}
 	}
 
-	return suggestedAssignee, suggestedReviewer, nil
+	return suggestedAssignee, suggestedReviewers, nil
 }
 
 // GetProject retrieves a project by path.
@@ -230,16 +349,24 @@ func (a *App) GetMergeRequest(ctx context.Context, projectID, mrID int) (*domain
 	return mr, nil
 }
 
-// GetMergeRequestByBranch retrieves a merge request by project ID and source branch.
-func (a *App) GetMergeRequestByBranch(ctx context.Context, projectID int, branch string) (*domain.MergeRequest, error) {
-	mrs, err := a.repo.ListMergeRequests(ctx, "opened", "all")
+// GetMergeRequestByBranch retrieves the open merge request of a project with
+// the given source branch.
+func (a *App) GetMergeRequestByBranch(
+	ctx context.Context,
+	project *domain.Project,
+	branch string,
+) (*domain.MergeRequest, error) {
+	query := openedMergeRequests(domain.ScopeAll)
+	query.Project, query.SourceBranch = project.Path, branch
+
+	mrs, err := a.repo.ListMergeRequests(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list merge requests: %w", err)
 	}
 
 	var matchingMRs []*domain.MergeRequest
 	for _, mr := range mrs {
-		if mr.ProjectID == projectID && mr.SourceBranch == branch {
+		if mr.ProjectID == project.ID && mr.SourceBranch == branch {
 			matchingMRs = append(matchingMRs, mr)
 		}
 	}
@@ -259,7 +386,7 @@ func (a *App) GetMergeRequestByBranch(ctx context.Context, projectID int, branch
 }
 
 // GetMergeRequestApprovals retrieves approvals for a merge request.
-func (a *App) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) ([]*domain.User, error) {
+func (a *App) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int) (*domain.Approvals, error) {
 	approvals, err := a.repo.GetMergeRequestApprovals(ctx, projectID, mrID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merge request approvals: %w", err)
@@ -268,51 +395,54 @@ func (a *App) GetMergeRequestApprovals(ctx context.Context, projectID, mrID int)
 	return approvals, nil
 }
 
+// GetMergeRequestApprovalRules retrieves the approval rules of a merge
+// request, which are empty for backends without approval rules.
+func (a *App) GetMergeRequestApprovalRules(
+	ctx context.Context,
+	projectID, mrID int,
+) ([]*domain.ApprovalRule, error) {
+	rules, err := a.repo.GetMergeRequestApprovalRules(ctx, projectID, mrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request approval rules: %w", err)
+	}
+
+	return rules, nil
+}
+
 // GetCurrentProjectInfo retrieves information about the current project from git.
 func (a *App) GetCurrentProjectInfo(ctx context.Context) (*domain.Project, string, error) {
-	cmd := exec.CommandContext(ctx, "git", "remote", "get-url", "origin")
-	output, err := cmd.Output()
+	remote, err := git.CurrentRemote(ctx)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get remote URL: %w", err)
+		return nil, "", fmt.Errorf("failed to get remote: %w", err)
 	}
 
-	remoteURL := strings.TrimSpace(string(output))
-
-	// Get current branch
-	cmd = exec.CommandContext(ctx, "git", "branch", "--show-current")
-	output, err = cmd.Output()
+	branch, err := git.CurrentBranch(ctx)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	branch := strings.TrimSpace(string(output))
-	if branch == "" {
-		return nil, "", errors.New("failed to get current branch")
+	project, err := a.repo.GetProject(ctx, remote.ProjectPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get project: %w", err)
 	}
 
-	const urlPartsCount = 2
+	return project, branch, nil
+}
 
-	var projectPath string
-	if strings.HasPrefix(remoteURL, "git@") {
-		parts := strings.Split(strings.TrimSuffix(remoteURL, ".git"), ":")
-		if len(parts) != urlPartsCount {
-			return nil, "", errors.New("invalid SSH remote URL format")
-		}
-		projectPath = parts[1]
-	} else {
-		parts := strings.Split(strings.TrimSuffix(remoteURL, ".git"), "/")
-		if len(parts) < urlPartsCount {
-			return nil, "", errors.New("invalid HTTPS remote URL format")
-		}
-		projectPath = strings.Join(parts[len(parts)-2:], "/")
+// LocalBranch returns the local branch to check the merge request out to in
+// the current repository: its source branch, unless the merge request comes
+// from a fork or a local branch of that name tracks another branch, in which
+// case fast-forwarding it would mix unrelated work.
+func (a *App) LocalBranch(ctx context.Context, mr *domain.MergeRequest) string {
+	if mr.Fork {
+		return mr.CheckoutBranch()
 	}
 
-	project, err := a.repo.GetProject(ctx, projectPath)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to get project: %w", err)
+	if upstream := git.Upstream(ctx, mr.SourceBranch); upstream != "" && upstream != "origin/"+mr.SourceBranch {
+		return mr.CheckoutBranch()
 	}
 
-	return project, branch, nil
+	return mr.SourceBranch
 }
 
 // SortMergeRequestsByPriority sorts merge requests by priority.
@@ -347,7 +477,7 @@ func (a *App) SortMergeRequestsByPriority(
 
 // GetMergeRequestsWithStatus retrieves merge requests with enhanced status information.
 func (a *App) GetMergeRequestsWithStatus(ctx context.Context) ([]*domain.MergeRequestWithStatus, error) {
-	mrs, err := a.repo.ListMergeRequests(ctx, "opened")
+	mrs, err := a.repo.ListMergeRequests(ctx, openedMergeRequests(domain.ScopeCreatedByMe))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merge requests: %w", err)
 	}
@@ -362,29 +492,18 @@ func (a *App) GetMergeRequestsWithStatus(ctx context.Context) ([]*domain.MergeRe
 
 	mrsWithStatus := make([]*domain.MergeRequestWithStatus, 0, len(mrs))
 	now := time.Now()
-	threeWorkingDaysAgo := subtractWorkingDays(now, workingDaysThreshold)
+	stalledSince := subtractWorkingDays(now, a.stalledAfter())
 
 	for _, mr := range mrs {
 		approvals, err := a.repo.GetMergeRequestApprovals(ctx, mr.ProjectID, mr.IID)
 		if err != nil {
-			approvals = []*domain.User{}
-		}
-
-		isStalled := mr.UpdatedAt.Before(threeWorkingDaysAgo)
-		isCurrentBranch := currentBranch != "" && mr.SourceBranch == currentBranch
-		isCurrentProject := currentProjectID != 0 && mr.ProjectID == currentProjectID
-
-		mrWithStatus := &domain.MergeRequestWithStatus{
-			MergeRequest:     mr,
-			Approvals:        approvals,
-			ApprovalCount:    len(approvals),
-			IsStalled:        isStalled,
-			IsCurrentBranch:  isCurrentBranch,
-			IsCurrentProject: isCurrentProject,
+			approvals = nil
 		}
 
+		mrWithStatus := a.createMRWithStatus(mr, approvals, currentProjectID, currentBranch, stalledSince)
 		mrsWithStatus = append(mrsWithStatus, mrWithStatus)
 	}
+	a.addAllDiscussions(ctx, mrsWithStatus)
 
 	return a.SortMergeRequestsByPriority(mrsWithStatus, currentProjectID, currentBranch), nil
 }
@@ -398,24 +517,66 @@ func (a *App) UpdateMergeRequest(ctx context.Context, projectID, mrID int, assig
 	return nil
 }
 
+// CreateMergeRequest creates a merge request in a project.
+func (a *App) CreateMergeRequest(
+	ctx context.Context,
+	projectID int,
+	opts *domain.NewMergeRequest,
+) (*domain.MergeRequest, error) {
+	mr, err := a.repo.CreateMergeRequest(ctx, projectID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge request: %w", err)
+	}
+
+	return mr, nil
+}
+
+// ApproveMergeRequest approves a merge request as the current user.
+func (a *App) ApproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	if err := a.repo.ApproveMergeRequest(ctx, projectID, mrID); err != nil {
+		return fmt.Errorf("failed to approve merge request: %w", err)
+	}
+
+	return nil
+}
+
+// UnapproveMergeRequest withdraws the approval of the current user.
+func (a *App) UnapproveMergeRequest(ctx context.Context, projectID, mrID int) error {
+	if err := a.repo.UnapproveMergeRequest(ctx, projectID, mrID); err != nil {
+		return fmt.Errorf("failed to unapprove merge request: %w", err)
+	}
+
+	return nil
+}
+
+// GetMergeRequestDiscussions retrieves the comment threads of a merge request.
+func (a *App) GetMergeRequestDiscussions(ctx context.Context, projectID, mrID int) ([]*domain.Discussion, error) {
+	discussions, err := a.repo.ListMergeRequestDiscussions(ctx, projectID, mrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request discussions: %w", err)
+	}
+
+	return discussions, nil
+}
+
 // GetMyReviewWorkloadWithStatus retrieves merge requests with enhanced status information
 // for current user's review workload.
 func (a *App) GetMyReviewWorkloadWithStatus(ctx context.Context) ([]*domain.MergeRequestWithStatus, error) {
-	currentUser, err := a.getCurrentUser(ctx)
+	currentUser, err := a.GetCurrentUser(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current user: %w", err)
 	}
 
-	mrs, err := a.repo.ListMergeRequests(ctx, "opened", "all")
+	mrs, err := a.repo.ListMergeRequests(ctx, openedMergeRequests(domain.ScopeAll))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get merge requests: %w", err)
 	}
 
 	currentProjectID, currentBranch := a.getCurrentProjectInfoSafe(ctx)
-	threeWorkingDaysAgo := subtractWorkingDays(time.Now(), workingDaysThreshold)
+	stalledSince := subtractWorkingDays(time.Now(), a.stalledAfter())
 
 	mrsWithStatus := a.filterAndEnrichMRsForReview(
-		ctx, mrs, currentUser, currentProjectID, currentBranch, threeWorkingDaysAgo,
+		ctx, mrs, currentUser, currentProjectID, currentBranch, stalledSince,
 	)
 
 	return a.SortMergeRequestsByPriority(mrsWithStatus, currentProjectID, currentBranch), nil
@@ -423,7 +584,7 @@ func (a *App) GetMyReviewWorkloadWithStatus(ctx context.Context) ([]*domain.Merg
 
 // GetMyActivity retrieves the current user's activity events within the specified time range.
 func (a *App) GetMyActivity(ctx context.Context, since time.Time, till *time.Time) ([]*domain.Event, error) {
-	currentUser, err := a.getCurrentUser(ctx)
+	currentUser, err := a.GetCurrentUser(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current user: %w", err)
 	}
@@ -451,7 +612,7 @@ func (a *App) filterAndEnrichMRsForReview(
 	currentUser *domain.User,
 	currentProjectID int,
 	currentBranch string,
-	threeWorkingDaysAgo time.Time,
+	stalledSince time.Time,
 ) []*domain.MergeRequestWithStatus {
 	mrsWithStatus := make([]*domain.MergeRequestWithStatus, 0)
 
@@ -460,15 +621,23 @@ func (a *App) filterAndEnrichMRsForReview(
 			continue
 		}
 
-		approvals, _ := a.repo.GetMergeRequestApprovals(ctx, mr.ProjectID, mr.IID)
-		if hasUserApprovedMR(approvals, currentUser.ID) {
+		approvals, err := a.repo.GetMergeRequestApprovals(ctx, mr.ProjectID, mr.IID)
+		if err != nil {
+			approvals = nil
+		}
+		if approvals != nil && hasUserApprovedMR(approvals.ApprovedBy, currentUser.ID) {
 			continue
 		}
 
-		mrWithStatus := a.createMRWithStatus(mr, approvals, currentProjectID, currentBranch, threeWorkingDaysAgo)
+		mrWithStatus := a.createMRWithStatus(mr, approvals, currentProjectID, currentBranch, stalledSince)
 		mrsWithStatus = append(mrsWithStatus, mrWithStatus)
 	}
 
+	a.addAllDiscussions(ctx, mrsWithStatus)
+	for _, mrWithStatus := range mrsWithStatus {
+		mrWithStatus.IsAnswered = isAnsweredFor(mrWithStatus.Discussions, currentUser)
+	}
+
 	return mrsWithStatus
 }
 
@@ -494,25 +663,107 @@ func (a *App) isMRRelevantForReview(mr *domain.MergeRequest, currentUser *domain
 	return isAssignee || isReviewer
 }
 
+// createMRWithStatus returns the status of mr with its approvals, which are
+// nil when they could not be fetched.
 func (a *App) createMRWithStatus(
 	mr *domain.MergeRequest,
-	approvals []*domain.User,
+	approvals *domain.Approvals,
 	currentProjectID int,
 	currentBranch string,
-	threeWorkingDaysAgo time.Time,
+	stalledSince time.Time,
 ) *domain.MergeRequestWithStatus {
-	isStalled := mr.UpdatedAt.Before(threeWorkingDaysAgo)
+	isStalled := mr.UpdatedAt.Before(stalledSince)
 	isCurrentBranch := currentBranch != "" && mr.SourceBranch == currentBranch
 	isCurrentProject := currentProjectID != 0 && mr.ProjectID == currentProjectID
 
-	return &domain.MergeRequestWithStatus{
+	mrWithStatus := &domain.MergeRequestWithStatus{
 		MergeRequest:     mr,
-		Approvals:        approvals,
-		ApprovalCount:    len(approvals),
 		IsStalled:        isStalled,
 		IsCurrentBranch:  isCurrentBranch,
 		IsCurrentProject: isCurrentProject,
 	}
+	if approvals == nil {
+		approvals = domain.NewApprovals(nil)
+		mrWithStatus.ApprovalsUnknown = true
+	}
+	mrWithStatus.SetApprovals(approvals)
+
+	return mrWithStatus
+}
+
+// addDiscussions sets the discussions of a merge request. Like approvals,
+// they are left out when they cannot be fetched, which DiscussionsUnknown
+// records.
+func (a *App) addDiscussions(ctx context.Context, mr *domain.MergeRequestWithStatus) {
+	discussions, err := a.repo.ListMergeRequestDiscussions(ctx, mr.ProjectID, mr.IID)
+	if err != nil {
+		mr.DiscussionsUnknown = true
+
+		return
+	}
+
+	mr.SetDiscussions(discussions)
+}
+
+// addAllDiscussions sets the discussions of the merge requests, fetching them
+// a few at a time.
+func (a *App) addAllDiscussions(ctx context.Context, mrs []*domain.MergeRequestWithStatus) {
+	var g errgroup.Group
+	g.SetLimit(fetchLimit)
+
+	for _, mr := range mrs {
+		g.Go(func() error {
+			a.addDiscussions(ctx, mr)
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+}
+
+// isAnsweredFor reports whether someone replied to user in any discussion.
+func isAnsweredFor(discussions []*domain.Discussion, user *domain.User) bool {
+	for _, d := range discussions {
+		if d.IsAnsweredFor(user) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stalledAfter returns the number of working days without updates after which
+// a merge request is considered stalled.
+func (a *App) stalledAfter() int {
+	if a.stalledAfterDays > 0 {
+		return a.stalledAfterDays
+	}
+
+	return workingDaysThreshold
+}
+
+// sortWorkloads orders workloads from the most to the least preferred
+// candidate according to strategy, or fallback when strategy is not set.
+func sortWorkloads(workloads []*domain.UserWorkload, strategy, fallback string) {
+	if strategy == "" {
+		strategy = fallback
+	}
+
+	if strategy == config.StrategyWorkload {
+		sort.SliceStable(workloads, func(i, j int) bool {
+			return workloads[i].MRCount < workloads[j].MRCount
+		})
+
+		return
+	}
+
+	sort.SliceStable(workloads, func(i, j int) bool {
+		scoreI := calculateAssigneeScore(workloads[i].Commits, workloads[i].MRCount)
+		scoreJ := calculateAssigneeScore(workloads[j].Commits, workloads[j].MRCount)
+
+		return scoreI > scoreJ
+	})
 }
 
 func calculateAssigneeScore(commits, mrCount int) float64 {
@@ -587,19 +838,22 @@ func isUserInvolvedInMR(mr *domain.MergeRequest, userID int) bool {
 	return isAssignee || isReviewer
 }
 
-func (a *App) fetchMRApprovals(ctx context.Context, mrs []*domain.MergeRequest) (map[int][]*domain.User, error) {
+func (a *App) fetchMRApprovals(
+	ctx context.Context,
+	mrs []*domain.MergeRequest,
+) (map[domain.MergeRequestKey][]*domain.User, error) {
 	g, ctx := errgroup.WithContext(ctx)
-	approvalsMap := make(map[int][]*domain.User, len(mrs))
+	approvalsMap := make(map[domain.MergeRequestKey][]*domain.User, len(mrs))
 	var mu sync.Mutex
 
 	for _, mr := range mrs {
 		g.Go(func() error {
 			approvals, err := a.repo.GetMergeRequestApprovals(ctx, mr.ProjectID, mr.IID)
 			if err != nil {
-				return fmt.Errorf("failed to get approvals for MR %d: %w", mr.IID, err)
+				return fmt.Errorf("failed to get approvals for MR %s: %w", mr.Key(), err)
 			}
 			mu.Lock()
-			approvalsMap[mr.IID] = approvals
+			approvalsMap[mr.Key()] = approvals.ApprovedBy
 			mu.Unlock()
 
 			return nil
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"errors"
+	"os/exec"
 	"testing"
 	"time"
 
@@ -105,6 +106,67 @@ func TestApp_GetMergeRequest(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestApp_GetMergeRequestByBranch(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.MockRepository{}
+	app := &App{repo: repo, teamUsers: []string{}}
+
+	older := &domain.MergeRequest{IID: 1, ProjectID: 42, SourceBranch: "feature", UpdatedAt: time.Now().Add(-time.Hour)}
+	newer := &domain.MergeRequest{IID: 2, ProjectID: 42, SourceBranch: "feature", UpdatedAt: time.Now()}
+
+	query := &domain.MergeRequestQuery{
+		State:        domain.StateOpened,
+		Scope:        domain.ScopeAll,
+		Project:      "group/project",
+		SourceBranch: "feature",
+	}
+	repo.On("ListMergeRequests", ctx, query).Return([]*domain.MergeRequest{older, newer}, nil)
+
+	mr, err := app.GetMergeRequestByBranch(ctx, &domain.Project{ID: 42, Path: "group/project"}, "feature")
+
+	require.NoError(t, err)
+	assert.Equal(t, newer, mr)
+	repo.AssertExpectations(t)
+}
+
+func TestApp_LocalBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// The clone has main tracking origin/main and feature tracking origin/main.
+	origin, clone := t.TempDir(), t.TempDir()
+	for _, args := range [][]string{
+		{"-C", origin, "init", "-q", "-b", "main"},
+		{"-C", origin, "-c", "user.name=gg", "-c", "user.email=gg@example.com",
+			"commit", "-q", "--allow-empty", "-m", "initial"},
+		{"-C", clone, "clone", "-q", origin, "."},
+		{"-C", clone, "branch", "--track", "feature", "origin/main"},
+	} {
+		output, err := exec.CommandContext(context.Background(), "git", args...).CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	t.Chdir(clone)
+
+	tests := []struct {
+		name     string
+		mr       *domain.MergeRequest
+		expected string
+	}{
+		{name: "same project", mr: &domain.MergeRequest{IID: 42, SourceBranch: "main"}, expected: "main"},
+		{name: "new branch", mr: &domain.MergeRequest{IID: 42, SourceBranch: "topic"}, expected: "topic"},
+		{name: "fork", mr: &domain.MergeRequest{IID: 42, SourceBranch: "main", Fork: true}, expected: "mr/42"},
+		{name: "other upstream", mr: &domain.MergeRequest{IID: 42, SourceBranch: "feature"}, expected: "mr/42"},
+	}
+
+	appInstance := &App{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, appInstance.LocalBranch(context.Background(), tt.mr))
+		})
+	}
+}
+
 func TestApp_UpdateMergeRequest(t *testing.T) {
 	ctx := context.Background()
 	repo := &mocks.MockRepository{}
@@ -121,6 +183,26 @@ func TestApp_UpdateMergeRequest(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestApp_CreateMergeRequest(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.MockRepository{}
+	app := &App{repo: repo, teamUsers: []string{}}
+
+	opts := &domain.NewMergeRequest{Title: "Add login", SourceBranch: "feature", TargetBranch: "main"}
+	created := &domain.MergeRequest{ID: 10, IID: 3, ProjectID: 1, Title: "Add login"}
+
+	repo.On("CreateMergeRequest", ctx, 1, opts).Return(created, nil).Once()
+	repo.On("CreateMergeRequest", ctx, 1, opts).Return(nil, errors.New("conflict")).Once()
+
+	mr, err := app.CreateMergeRequest(ctx, 1, opts)
+	require.NoError(t, err)
+	assert.Equal(t, created, mr)
+
+	_, err = app.CreateMergeRequest(ctx, 1, opts)
+	require.ErrorContains(t, err, "failed to create merge request: conflict")
+	repo.AssertExpectations(t)
+}
+
 func TestApp_AnalyzeWorkload(t *testing.T) {
 	ctx := context.Background()
 
@@ -143,7 +225,7 @@ func TestApp_AnalyzeWorkload(t *testing.T) {
 					{AuthorEmail: "user1@example.com"},
 					{AuthorEmail: "user1@example.com"},
 				}, nil)
-				m.On("ListMergeRequests", ctx, "opened", []string{"all"}).Return([]*domain.MergeRequest{
+				m.On("ListMergeRequests", ctx, openedMergeRequests(domain.ScopeAll)).Return([]*domain.MergeRequest{
 					{
 						ID: 1, IID: 1, ProjectID: 1,
 						Assignee: &domain.User{ID: 1},
@@ -152,7 +234,7 @@ func TestApp_AnalyzeWorkload(t *testing.T) {
 				}, nil)
 				m.On("GetUserByUsername", ctx, "user1").Return(users[0], nil)
 				m.On("GetUserByUsername", ctx, "user2").Return(users[1], nil)
-				m.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return([]*domain.User{}, nil)
+				m.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(domain.NewApprovals([]*domain.User{}), nil)
 			},
 			validate: func(t *testing.T, workloads []*domain.UserWorkload, err error) {
 				require.NoError(t, err)
@@ -182,7 +264,7 @@ func TestApp_AnalyzeWorkload(t *testing.T) {
 				}
 				m.On("GetAllUsers", ctx).Return(users, nil)
 				m.On("ListCommits", ctx, 1).Return([]*domain.Commit{}, nil)
-				m.On("ListMergeRequests", ctx, "opened", []string{"all"}).Return([]*domain.MergeRequest{}, nil)
+				m.On("ListMergeRequests", ctx, openedMergeRequests(domain.ScopeAll)).Return([]*domain.MergeRequest{}, nil)
 				m.On("GetUserByUsername", ctx, "user1").Return(users[0], nil)
 				m.On("GetUserByUsername", ctx, "nonexistent").Return(nil, errors.New("not found"))
 			},
@@ -215,7 +297,8 @@ func TestApp_AnalyzeWorkload(t *testing.T) {
 				}
 				m.On("GetAllUsers", ctx).Return(users, nil)
 				m.On("ListCommits", ctx, 1).Return([]*domain.Commit{}, nil)
-				m.On("ListMergeRequests", ctx, "opened", []string{"all"}).Return(nil, errors.New("mr error"))
+				m.On("GetUserByUsername", ctx, "user1").Return(users[0], nil)
+				m.On("ListMergeRequests", ctx, openedMergeRequests(domain.ScopeAll)).Return(nil, errors.New("mr error"))
 			},
 			validate: func(t *testing.T, workloads []*domain.UserWorkload, err error) {
 				require.Error(t, err)
@@ -231,7 +314,7 @@ func TestApp_AnalyzeWorkload(t *testing.T) {
 				}
 				m.On("GetAllUsers", ctx).Return(users, nil)
 				m.On("ListCommits", ctx, 1).Return([]*domain.Commit{}, nil)
-				m.On("ListMergeRequests", ctx, "opened", []string{"all"}).Return([]*domain.MergeRequest{
+				m.On("ListMergeRequests", ctx, openedMergeRequests(domain.ScopeAll)).Return([]*domain.MergeRequest{
 					{
 						ID: 1, IID: 1, ProjectID: 1,
 						Assignee: &domain.User{ID: 1},
@@ -239,7 +322,7 @@ func TestApp_AnalyzeWorkload(t *testing.T) {
 					},
 				}, nil)
 				m.On("GetUserByUsername", ctx, "user1").Return(users[0], nil)
-				m.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return([]*domain.User{{ID: 1}}, nil)
+				m.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(domain.NewApprovals([]*domain.User{{ID: 1}}), nil)
 			},
 			validate: func(t *testing.T, workloads []*domain.UserWorkload, err error) {
 				require.NoError(t, err)
@@ -284,9 +367,9 @@ func TestApp_AnalyzeActiveMRs(t *testing.T) {
 						Author:   &domain.User{ID: 2},
 					},
 				}
-				m.On("ListMergeRequests", mock.Anything, "opened", []string{"all"}).Return(mrs, nil)
+				m.On("ListMergeRequests", mock.Anything, openedMergeRequests(domain.ScopeAll)).Return(mrs, nil)
 				m.On("GetUserByUsername", mock.Anything, "user1").Return(user, nil)
-				m.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return([]*domain.User{}, nil)
+				m.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(domain.NewApprovals([]*domain.User{}), nil)
 			},
 			validate: func(t *testing.T, workloads []*domain.UserWorkload, err error) {
 				require.NoError(t, err)
@@ -295,11 +378,34 @@ func TestApp_AnalyzeActiveMRs(t *testing.T) {
 				assert.Len(t, workloads[0].ActiveMRs, 1)
 			},
 		},
+		{
+			name:      "same IID in different projects",
+			teamUsers: []string{"user1"},
+			setupMock: func(m *mocks.MockRepository) {
+				user := &domain.User{ID: 1, Username: "user1"}
+				mrs := []*domain.MergeRequest{
+					{ID: 1, IID: 42, ProjectID: 1, Assignee: user, Author: &domain.User{ID: 2}},
+					{ID: 2, IID: 42, ProjectID: 2, Assignee: user, Author: &domain.User{ID: 2}},
+				}
+				m.On("ListMergeRequests", mock.Anything, openedMergeRequests(domain.ScopeAll)).Return(mrs, nil)
+				m.On("GetUserByUsername", mock.Anything, "user1").Return(user, nil)
+				m.On("GetMergeRequestApprovals", mock.Anything, 1, 42).Return(domain.NewApprovals([]*domain.User{user}), nil)
+				m.On("GetMergeRequestApprovals", mock.Anything, 2, 42).Return(domain.NewApprovals([]*domain.User{}), nil)
+			},
+			validate: func(t *testing.T, workloads []*domain.UserWorkload, err error) {
+				require.NoError(t, err)
+				require.Len(t, workloads, 1)
+				assert.Equal(t, 1, workloads[0].MRCount)
+				require.Len(t, workloads[0].ActiveMRs, 1)
+				assert.Equal(t, 2, workloads[0].ActiveMRs[0].ProjectID)
+			},
+		},
 		{
 			name:      "error listing merge requests",
 			teamUsers: []string{"user1"},
 			setupMock: func(m *mocks.MockRepository) {
-				m.On("ListMergeRequests", mock.Anything, "opened", []string{"all"}).Return(nil, errors.New("api error"))
+				m.On("GetUserByUsername", mock.Anything, "user1").Return(&domain.User{ID: 1, Username: "user1"}, nil)
+				m.On("ListMergeRequests", mock.Anything, openedMergeRequests(domain.ScopeAll)).Return(nil, errors.New("api error"))
 			},
 			validate: func(t *testing.T, workloads []*domain.UserWorkload, err error) {
 				require.Error(t, err)
@@ -310,7 +416,7 @@ func TestApp_AnalyzeActiveMRs(t *testing.T) {
 			name:      "user not found",
 			teamUsers: []string{"user1"},
 			setupMock: func(m *mocks.MockRepository) {
-				m.On("ListMergeRequests", mock.Anything, "opened", []string{"all"}).Return([]*domain.MergeRequest{}, nil)
+				m.On("ListMergeRequests", mock.Anything, openedMergeRequests(domain.ScopeAll)).Return([]*domain.MergeRequest{}, nil)
 				m.On("GetUserByUsername", mock.Anything, "user1").Return(nil, errors.New("not found"))
 			},
 			validate: func(t *testing.T, workloads []*domain.UserWorkload, err error) {
@@ -423,6 +529,52 @@ func TestApp_SuggestAssigneeAndReviewer(t *testing.T) {
 	}
 }
 
+func TestApp_SuggestAssignment(t *testing.T) {
+	ctx := context.Background()
+	app := &App{teamUsers: []string{}}
+
+	available := domain.UserStatus{Availability: "available"}
+	mr := &domain.MergeRequest{Author: &domain.User{ID: 1}}
+	workloads := []*domain.UserWorkload{
+		{User: &domain.User{ID: 1, Status: available}},
+		{User: &domain.User{ID: 2, Status: available}, MRCount: 1},
+		{User: &domain.User{ID: 3, Status: available}, MRCount: 2},
+		{User: &domain.User{ID: 4, Status: available}, MRCount: 3},
+	}
+
+	userIDs := func(users []*domain.User) []int {
+		ids := make([]int, 0, len(users))
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+
+		return ids
+	}
+
+	t.Run("assignee and two reviewers", func(t *testing.T) {
+		assignee, reviewers, err := app.SuggestAssignment(ctx, mr, workloads, RouletteOptions{Reviewers: 2})
+		require.NoError(t, err)
+		require.NotNil(t, assignee)
+		require.Len(t, reviewers, 2)
+		assert.NotContains(t, userIDs(reviewers), assignee.ID)
+		assert.NotContains(t, userIDs(reviewers), 1)
+	})
+
+	t.Run("no assignee", func(t *testing.T) {
+		assignee, reviewers, err := app.SuggestAssignment(ctx, mr, workloads, RouletteOptions{SkipAssignee: true, Reviewers: 3})
+		require.NoError(t, err)
+		assert.Nil(t, assignee)
+		assert.ElementsMatch(t, []int{2, 3, 4}, userIDs(reviewers))
+	})
+
+	t.Run("fewer candidates than reviewers", func(t *testing.T) {
+		assignee, reviewers, err := app.SuggestAssignment(ctx, mr, workloads, RouletteOptions{Reviewers: 5})
+		require.NoError(t, err)
+		require.NotNil(t, assignee)
+		assert.Len(t, reviewers, 2)
+	})
+}
+
 func TestApp_SortMergeRequestsByPriority(t *testing.T) {
 	app := &App{teamUsers: []string{}}
 
@@ -470,9 +622,13 @@ func TestApp_GetMergeRequestsWithStatus(t *testing.T) {
 		},
 	}
 
-	// ListMergeRequests is called with "opened" and no scope (empty variadic)
-	repo.On("ListMergeRequests", mock.Anything, "opened", []string{}).Return(mrs, nil)
-	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return([]*domain.User{}, nil)
+	// ListMergeRequests is called for the open merge requests created by the user
+	repo.On("ListMergeRequests", mock.Anything, openedMergeRequests(domain.ScopeCreatedByMe)).Return(mrs, nil)
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(domain.NewApprovals([]*domain.User{}), nil)
+	repo.On("ListMergeRequestDiscussions", mock.Anything, 1, 1).Return([]*domain.Discussion{
+		{Resolvable: true, Notes: []*domain.Note{{ID: 1}}},
+		{Resolvable: true, Resolved: true, Notes: []*domain.Note{{ID: 2}}},
+	}, nil)
 
 	// GetCurrentProjectInfo may succeed if we're in a git repo, so mock GetProject
 	// It will try to parse the git remote URL and call GetProject
@@ -485,6 +641,8 @@ func TestApp_GetMergeRequestsWithStatus(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, mrsWithStatus, 1)
 	assert.True(t, mrsWithStatus[0].IsStalled) // Updated 7 days ago (definitely > 3 working days)
+	assert.Equal(t, 2, mrsWithStatus[0].ResolvableThreads)
+	assert.Equal(t, 1, mrsWithStatus[0].UnresolvedThreads)
 }
 
 func TestApp_GetMergeRequestApprovals(t *testing.T) {
@@ -492,9 +650,10 @@ func TestApp_GetMergeRequestApprovals(t *testing.T) {
 	repo := &mocks.MockRepository{}
 	app := &App{repo: repo, teamUsers: []string{}}
 
-	approvals := []*domain.User{
-		{ID: 1, Username: "reviewer1"},
-		{ID: 2, Username: "reviewer2"},
+	approvals := &domain.Approvals{
+		ApprovedBy: []*domain.User{{ID: 1, Username: "reviewer1"}, {ID: 2, Username: "reviewer2"}},
+		Required:   3,
+		Left:       1,
 	}
 
 	repo.On("GetMergeRequestApprovals", ctx, 1, 2).Return(approvals, nil)
@@ -549,16 +708,27 @@ func TestApp_GetMyReviewWorkloadWithStatus(t *testing.T) {
 	}
 
 	repo.On("GetCurrentUser", mock.Anything).Return(currentUser, nil)
-	repo.On("ListMergeRequests", mock.Anything, "opened", []string{"all"}).Return(mrs, nil)
-	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return([]*domain.User{}, nil)
-	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 2).Return([]*domain.User{}, nil)
-	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 5).Return([]*domain.User{{ID: 1}}, nil) // Already approved
+	repo.On("ListMergeRequests", mock.Anything, openedMergeRequests(domain.ScopeAll)).Return(mrs, nil)
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(domain.NewApprovals([]*domain.User{}), nil)
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 2).Return(domain.NewApprovals([]*domain.User{}), nil)
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 5).Return(domain.NewApprovals([]*domain.User{{ID: 1}}), nil) // Already approved
+	repo.On("ListMergeRequestDiscussions", mock.Anything, 1, 1).Return([]*domain.Discussion{
+		{Notes: []*domain.Note{{Author: currentUser}, {Author: &domain.User{ID: 2}}}},
+	}, nil)
+	repo.On("ListMergeRequestDiscussions", mock.Anything, 1, 2).Return(nil, assert.AnError)
 	repo.On("GetProject", mock.Anything, mock.Anything).Return(nil, assert.AnError).Maybe()
 
 	mrsWithStatus, err := app.GetMyReviewWorkloadWithStatus(ctx)
 
 	require.NoError(t, err)
 	require.Len(t, mrsWithStatus, 2) // Should exclude draft, author's own MRs, and already approved
+
+	answered := make(map[int]bool)
+	for _, mr := range mrsWithStatus {
+		answered[mr.IID] = mr.IsAnswered
+	}
+	assert.Equal(t, map[int]bool{1: true, 2: false}, answered,
+		"the author replied to the comment of the reviewer on !1, discussions of !2 failed to load")
 }
 
 func TestApp_buildEmailToUserIDMap(t *testing.T) {
@@ -818,15 +988,37 @@ func TestApp_fetchMRApprovals(t *testing.T) {
 		{ID: 2, IID: 2, ProjectID: 1},
 	}
 
-	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return([]*domain.User{{ID: 1}}, nil)
-	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 2).Return([]*domain.User{{ID: 2}}, nil)
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(domain.NewApprovals([]*domain.User{{ID: 1}}), nil)
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 2).Return(domain.NewApprovals([]*domain.User{{ID: 2}}), nil)
+
+	approvalsMap, err := app.fetchMRApprovals(ctx, mrs)
+
+	require.NoError(t, err)
+	require.Len(t, approvalsMap, 2)
+	assert.Len(t, approvalsMap[domain.MergeRequestKey{ProjectID: 1, IID: 1}], 1)
+	assert.Len(t, approvalsMap[domain.MergeRequestKey{ProjectID: 1, IID: 2}], 1)
+	repo.AssertExpectations(t)
+}
+
+func TestApp_fetchMRApprovals_SameIIDAcrossProjects(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.MockRepository{}
+	app := &App{repo: repo, teamUsers: []string{}}
+
+	mrs := []*domain.MergeRequest{
+		{ID: 1, IID: 42, ProjectID: 1},
+		{ID: 2, IID: 42, ProjectID: 2},
+	}
+
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 42).Return(domain.NewApprovals([]*domain.User{{ID: 1}}), nil)
+	repo.On("GetMergeRequestApprovals", mock.Anything, 2, 42).Return(domain.NewApprovals([]*domain.User{{ID: 2}, {ID: 3}}), nil)
 
 	approvalsMap, err := app.fetchMRApprovals(ctx, mrs)
 
 	require.NoError(t, err)
 	require.Len(t, approvalsMap, 2)
-	assert.Len(t, approvalsMap[1], 1)
-	assert.Len(t, approvalsMap[2], 1)
+	assert.Equal(t, []*domain.User{{ID: 1}}, approvalsMap[mrs[0].Key()])
+	assert.Equal(t, []*domain.User{{ID: 2}, {ID: 3}}, approvalsMap[mrs[1].Key()])
 	repo.AssertExpectations(t)
 }
 
@@ -859,3 +1051,37 @@ func TestApp_fetchMRApprovals_EmptyList(t *testing.T) {
 	assert.NotNil(t, approvalsMap)
 	assert.Empty(t, approvalsMap)
 }
+
+func TestSortWorkloads(t *testing.T) {
+	workloads := func() []*domain.UserWorkload {
+		return []*domain.UserWorkload{
+			{User: &domain.User{ID: 1}, MRCount: 1, Commits: 1},
+			{User: &domain.User{ID: 2}, MRCount: 3, Commits: 40},
+			{User: &domain.User{ID: 3}, MRCount: 0, Commits: 0},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		fallback string
+		expected []int
+	}{
+		{name: "expertise", strategy: config.StrategyExpertise, expected: []int{2, 1, 3}},
+		{name: "workload", strategy: config.StrategyWorkload, expected: []int{3, 1, 2}},
+		{name: "fallback", fallback: config.StrategyWorkload, expected: []int{3, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := workloads()
+			sortWorkloads(sorted, tt.strategy, tt.fallback)
+
+			ids := make([]int, 0, len(sorted))
+			for _, workload := range sorted {
+				ids = append(ids, workload.User.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"golang.org/x/sync/errgroup"
+)
+
+// Approval states filtering merge requests.
+const (
+	// ApprovalApproved keeps the merge requests that have no approvals left,
+	// like the ones ready to merge, including those needing none.
+	ApprovalApproved = "approved"
+	// ApprovalPending keeps the merge requests that still miss approvals.
+	ApprovalPending = "pending"
+	// ApprovalNone keeps the merge requests nobody approved.
+	ApprovalNone = "none"
+)
+
+// ApprovalStates returns every approval state.
+func ApprovalStates() []string {
+	return []string{ApprovalApproved, ApprovalPending, ApprovalNone}
+}
+
+// ListQuery selects, filters and sorts merge requests for ListMergeRequests.
+// On top of the filters of the backends, it filters on the review status.
+type ListQuery struct {
+	domain.MergeRequestQuery
+
+	// Stalled keeps the merge requests not updated for StalledAfterDays
+	// working days, or for the configured number of days when not positive.
+	Stalled          bool
+	StalledAfterDays int
+	// Approval keeps the merge requests in one of the approval states, when
+	// not empty.
+	Approval string
+	// Reverse reverses the order of Sort, which defaults to SortUpdated.
+	Reverse bool
+}
+
+// openedMergeRequests queries the open merge requests of a scope.
+func openedMergeRequests(scope string) *domain.MergeRequestQuery {
+	return &domain.MergeRequestQuery{State: domain.StateOpened, Scope: scope}
+}
+
+// ListMergeRequests lists the merge requests selected by query with their
+// status, sorted.
+func (a *App) ListMergeRequests(ctx context.Context, query *ListQuery) ([]*domain.MergeRequestWithStatus, error) {
+	if query.Approval != "" && !slices.Contains(ApprovalStates(), query.Approval) {
+		return nil, fmt.Errorf("unknown approval state %q", query.Approval)
+	}
+	if query.Sort != "" && !slices.Contains(domain.SortKeys(), query.Sort) {
+		return nil, fmt.Errorf("unknown sort key %q", query.Sort)
+	}
+
+	var currentProjectID int
+	currentProject, currentBranch, err := a.GetCurrentProjectInfo(ctx)
+	if err == nil {
+		currentProjectID = currentProject.ID
+	}
+
+	// Listing every merge request of the instance is slow and rarely wanted,
+	// so a query naming no project, group or user sticks to the project of
+	// the current repository, or to the merge requests of the current user.
+	listQuery := query.MergeRequestQuery
+	if !listQuery.HasOwner() {
+		if currentProject != nil {
+			listQuery.Project = currentProject.Path
+		} else {
+			listQuery.Scope = domain.ScopeCreatedByMe
+		}
+	}
+
+	mrs, err := a.repo.ListMergeRequests(ctx, &listQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge requests: %w", err)
+	}
+
+	now := time.Now()
+	stalledSince := subtractWorkingDays(now, a.stalledAfter())
+	updatedBefore := stalledSince
+	if query.StalledAfterDays > 0 {
+		updatedBefore = subtractWorkingDays(now, query.StalledAfterDays)
+	}
+
+	var matching []*domain.MergeRequest
+	for _, mr := range mrs {
+		if !listQuery.Matches(mr) {
+			continue
+		}
+		if query.Stalled && !mr.UpdatedAt.Before(updatedBefore) {
+			continue
+		}
+		matching = append(matching, mr)
+	}
+
+	// Like in the other listings, approvals and discussions that cannot be
+	// fetched are left out.
+	mrsWithStatus := make([]*domain.MergeRequestWithStatus, len(matching))
+	var g errgroup.Group
+	g.SetLimit(fetchLimit)
+	for i, mr := range matching {
+		g.Go(func() error {
+			approvals, err := a.repo.GetMergeRequestApprovals(ctx, mr.ProjectID, mr.IID)
+			if err != nil {
+				approvals = nil
+			}
+
+			mrWithStatus := a.createMRWithStatus(mr, approvals, currentProjectID, currentBranch, stalledSince)
+			a.addDiscussions(ctx, mrWithStatus)
+			mrsWithStatus[i] = mrWithStatus
+
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	result := make([]*domain.MergeRequestWithStatus, 0, len(mrsWithStatus))
+	for _, mr := range mrsWithStatus {
+		if hasApprovalState(mr, query.Approval) {
+			result = append(result, mr)
+		}
+	}
+
+	sortMergeRequests(result, query.Sort)
+	if query.Reverse {
+		slices.Reverse(result)
+	}
+
+	return result, nil
+}
+
+// hasApprovalState reports whether the merge request is in the approval
+// state, any state matching an empty one.
+func hasApprovalState(mr *domain.MergeRequestWithStatus, state string) bool {
+	switch state {
+	case ApprovalApproved:
+		return mr.ApprovalsLeft == 0
+	case ApprovalPending:
+		return mr.ApprovalsLeft > 0
+	case ApprovalNone:
+		return mr.ApprovalCount == 0
+	default:
+		return true
+	}
+}
+
+// sortMergeRequests orders merge requests by one of the sort keys, the most
+// recently updated first between equals.
+func sortMergeRequests(mrs []*domain.MergeRequestWithStatus, key string) {
+	sort.SliceStable(mrs, func(i, j int) bool {
+		a, b := mrs[i], mrs[j]
+
+		switch {
+		case key == domain.SortCreated && !a.CreatedAt.Equal(b.CreatedAt):
+			return a.CreatedAt.After(b.CreatedAt)
+		case key == domain.SortAge && !a.CreatedAt.Equal(b.CreatedAt):
+			return a.CreatedAt.Before(b.CreatedAt)
+		case key == domain.SortSize && a.ChangedFiles != b.ChangedFiles:
+			return a.ChangedFiles > b.ChangedFiles
+		default:
+			return a.UpdatedAt.After(b.UpdatedAt)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/mocks"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_ListMergeRequests(t *testing.T) {
+	now := time.Now()
+	alice := &domain.User{ID: 2, Username: "alice"}
+	bob := &domain.User{ID: 3, Username: "bob"}
+
+	// Updated 7 calendar days ago, more than 3 working days whatever the day.
+	mrs := []*domain.MergeRequest{
+		{
+			IID: 1, ProjectID: 1, Author: alice, Labels: []string{"backend"}, ChangedFiles: 3,
+			CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now.Add(-time.Hour),
+		},
+		{
+			IID: 2, ProjectID: 1, Author: bob, Labels: []string{"Backend", "bug"}, ChangedFiles: 12,
+			CreatedAt: now.Add(-240 * time.Hour), UpdatedAt: now.Add(-7 * 24 * time.Hour),
+		},
+		{
+			IID: 3, ProjectID: 1, Author: alice, ChangedFiles: 1,
+			CreatedAt: now.Add(-24 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		query  ListQuery
+		expect []int
+	}{
+		{
+			name:   "recently updated first",
+			expect: []int{1, 3, 2},
+		},
+		{
+			name:   "author",
+			query:  ListQuery{MergeRequestQuery: domain.MergeRequestQuery{Author: "Alice"}},
+			expect: []int{1, 3},
+		},
+		{
+			name:   "label",
+			query:  ListQuery{MergeRequestQuery: domain.MergeRequestQuery{Labels: []string{"backend"}}},
+			expect: []int{1, 2},
+		},
+		{
+			name:   "stalled",
+			query:  ListQuery{Stalled: true},
+			expect: []int{2},
+		},
+		{
+			name:   "stalled for longer",
+			query:  ListQuery{Stalled: true, StalledAfterDays: 20},
+			expect: []int{},
+		},
+		{
+			name:   "approved",
+			query:  ListQuery{Approval: ApprovalApproved},
+			expect: []int{1, 2},
+		},
+		{
+			name:   "pending approval",
+			query:  ListQuery{Approval: ApprovalPending},
+			expect: []int{3},
+		},
+		{
+			name:   "without approvals",
+			query:  ListQuery{Approval: ApprovalNone},
+			expect: []int{2},
+		},
+		{
+			name:   "oldest first",
+			query:  ListQuery{MergeRequestQuery: domain.MergeRequestQuery{Sort: domain.SortAge}},
+			expect: []int{2, 1, 3},
+		},
+		{
+			name:   "newest first",
+			query:  ListQuery{MergeRequestQuery: domain.MergeRequestQuery{Sort: domain.SortCreated}},
+			expect: []int{3, 1, 2},
+		},
+		{
+			name:   "largest first",
+			query:  ListQuery{MergeRequestQuery: domain.MergeRequestQuery{Sort: domain.SortSize}},
+			expect: []int{2, 1, 3},
+		},
+		{
+			name:   "smallest first",
+			query:  ListQuery{MergeRequestQuery: domain.MergeRequestQuery{Sort: domain.SortSize}, Reverse: true},
+			expect: []int{3, 1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockRepository{}
+			app := &App{repo: repo, teamUsers: []string{}}
+
+			// Outside of a project, queries naming no owner list the merge
+			// requests of the current user.
+			listQuery := tt.query.MergeRequestQuery
+			if !listQuery.HasOwner() {
+				listQuery.Scope = domain.ScopeCreatedByMe
+			}
+
+			repo.On("ListMergeRequests", mock.Anything, &listQuery).Return(mrs, nil)
+			repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).
+				Return(domain.NewApprovals([]*domain.User{bob, alice}), nil).Maybe()
+			// Nobody approved 2, which needs no approvals.
+			repo.On("GetMergeRequestApprovals", mock.Anything, 1, 2).
+				Return(&domain.Approvals{}, nil).Maybe()
+			repo.On("GetMergeRequestApprovals", mock.Anything, 1, 3).
+				Return(domain.NewApprovals([]*domain.User{bob}), nil).Maybe()
+			repo.On("ListMergeRequestDiscussions", mock.Anything, 1, mock.Anything).Return(nil, assert.AnError).Maybe()
+			repo.On("GetProject", mock.Anything, mock.Anything).Return(nil, assert.AnError).Maybe()
+
+			result, err := app.ListMergeRequests(context.Background(), &tt.query)
+			require.NoError(t, err)
+
+			iids := make([]int, 0, len(result))
+			for _, mr := range result {
+				iids = append(iids, mr.IID)
+			}
+			assert.Equal(t, tt.expect, iids)
+		})
+	}
+}
+
+func TestApp_ListMergeRequests_InvalidQuery(t *testing.T) {
+	app := &App{repo: &mocks.MockRepository{}, teamUsers: []string{}}
+
+	_, err := app.ListMergeRequests(context.Background(), &ListQuery{Approval: "maybe"})
+	require.ErrorContains(t, err, "unknown approval state")
+
+	_, err = app.ListMergeRequests(context.Background(),
+		&ListQuery{MergeRequestQuery: domain.MergeRequestQuery{Sort: "name"}})
+	require.ErrorContains(t, err, "unknown sort key")
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+)
+
+// MergeCheck is a condition checked before merging a merge request.
+type MergeCheck struct {
+	Name   string
+	Passed bool
+	// Detail describes the state the check saw.
+	Detail string
+}
+
+// CheckMerge fetches a merge request along with its approvals and checks
+// whether it can be merged with opts: it must be open, not a draft and not in
+// need of a rebase, its pipeline must have succeeded, or be running when
+// opts.WhenPipelineSucceeds is set, and it must have enough approvals and no
+// unresolved threads.
+func (a *App) CheckMerge(
+	ctx context.Context,
+	projectID, mrID int,
+	opts *domain.MergeOptions,
+) (*domain.MergeRequestWithStatus, []*MergeCheck, error) {
+	mr, err := a.repo.GetMergeRequest(ctx, projectID, mrID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	approvals, err := a.repo.GetMergeRequestApprovals(ctx, projectID, mrID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get merge request approvals: %w", err)
+	}
+
+	mrWithStatus := a.createMRWithStatus(mr, approvals, 0, "", subtractWorkingDays(time.Now(), a.stalledAfter()))
+
+	discussions, err := a.repo.ListMergeRequestDiscussions(ctx, projectID, mrID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get merge request discussions: %w", err)
+	}
+	mrWithStatus.SetDiscussions(discussions)
+
+	return mrWithStatus, mergeChecks(mrWithStatus, opts), nil
+}
+
+// MergeMergeRequest merges a merge request. Use CheckMerge first to report
+// why it cannot be merged.
+func (a *App) MergeMergeRequest(ctx context.Context, projectID, mrID int, opts *domain.MergeOptions) error {
+	if err := a.repo.MergeMergeRequest(ctx, projectID, mrID, opts); err != nil {
+		return fmt.Errorf("failed to merge merge request: %w", err)
+	}
+
+	return nil
+}
+
+// RebaseMergeRequest rebases the source branch of a merge request onto its
+// target branch on the server and waits for the rebase to complete, checking
+// every interval. It returns the rebased merge request.
+func (a *App) RebaseMergeRequest(
+	ctx context.Context,
+	projectID, mrID int,
+	interval time.Duration,
+) (*domain.MergeRequest, error) {
+	if err := a.repo.RebaseMergeRequest(ctx, projectID, mrID); err != nil {
+		return nil, fmt.Errorf("failed to rebase merge request: %w", err)
+	}
+
+	for {
+		mr, err := a.repo.GetMergeRequest(ctx, projectID, mrID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get merge request: %w", err)
+		}
+
+		if !mr.RebaseInProgress {
+			if mr.MergeError != "" {
+				return nil, fmt.Errorf("failed to rebase merge request: %s", mr.MergeError)
+			}
+
+			return mr, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to wait for the rebase: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+func mergeChecks(mr *domain.MergeRequestWithStatus, opts *domain.MergeOptions) []*MergeCheck {
+	state := &MergeCheck{Name: "State", Passed: mr.State == "" || mr.State == domain.StateOpened, Detail: mr.State}
+	if mr.State == "" {
+		state.Detail = "unknown"
+	}
+
+	draft := &MergeCheck{Name: "Draft", Passed: !mr.Draft, Detail: "ready"}
+	if mr.Draft {
+		draft.Detail = "marked as draft"
+	}
+
+	approvals := &MergeCheck{
+		Name:   "Approvals",
+		Passed: mr.ApprovalsLeft == 0,
+		Detail: fmt.Sprintf("%d of %d, %d left", mr.ApprovalCount, mr.ApprovalsRequired, mr.ApprovalsLeft),
+	}
+
+	mergeability := &MergeCheck{Name: "Mergeability", Passed: !mr.NeedsRebase(), Detail: mr.DetailedMergeStatus}
+	if mr.DetailedMergeStatus == "" {
+		mergeability.Detail = "unknown"
+	}
+
+	threads := &MergeCheck{
+		Name:   "Threads",
+		Passed: mr.UnresolvedThreads == 0,
+		Detail: fmt.Sprintf("%d of %d unresolved", mr.UnresolvedThreads, mr.ResolvableThreads),
+	}
+
+	return []*MergeCheck{
+		state, draft, mergeability, pipelineCheck(mr.MergeRequest, opts.WhenPipelineSucceeds), approvals, threads,
+	}
+}
+
+// pipelineCheck passes a pipeline that is unknown or still running only when
+// the merge is to wait for it to succeed, leaving the backend to decide
+// whether it can.
+func pipelineCheck(mr *domain.MergeRequest, whenPipelineSucceeds bool) *MergeCheck {
+	status := mr.PipelineStatus
+	check := &MergeCheck{Name: "Pipeline", Detail: status}
+
+	switch {
+	case mr.PipelineUnknown:
+		check.Passed, check.Detail = whenPipelineSucceeds, "unknown"
+		if whenPipelineSucceeds {
+			check.Detail = "unknown, merging once it succeeds"
+		}
+	case status == "":
+		check.Passed, check.Detail = true, "none"
+	case domain.PipelineInProgress(status):
+		check.Passed = whenPipelineSucceeds
+		if whenPipelineSucceeds {
+			check.Detail = status + ", merging once it succeeds"
+		}
+	default:
+		check.Passed = status == domain.PipelineSuccess || status == domain.PipelineSkipped
+	}
+
+	return check
+}
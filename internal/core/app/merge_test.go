@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/mocks"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_CheckMerge(t *testing.T) {
+	alice := &domain.User{ID: 2, Username: "alice"}
+	bob := &domain.User{ID: 3, Username: "bob"}
+
+	tests := []struct {
+		name      string
+		mr        *domain.MergeRequest
+		approvals *domain.Approvals
+		threads   []*domain.Discussion
+		opts      domain.MergeOptions
+		failed    []string
+	}{
+		{
+			name:      "mergeable",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, PipelineStatus: domain.PipelineSuccess},
+			approvals: domain.NewApprovals([]*domain.User{alice, bob}),
+		},
+		{
+			name:      "draft with a failed pipeline",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, Draft: true, PipelineStatus: domain.PipelineFailed},
+			approvals: domain.NewApprovals([]*domain.User{alice, bob}),
+			failed:    []string{"Draft", "Pipeline"},
+		},
+		{
+			name:      "running pipeline",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, PipelineStatus: "running"},
+			approvals: domain.NewApprovals([]*domain.User{alice, bob}),
+			failed:    []string{"Pipeline"},
+		},
+		{
+			name:      "running pipeline merged when it succeeds",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, PipelineStatus: "running"},
+			approvals: domain.NewApprovals([]*domain.User{alice, bob}),
+			opts:      domain.MergeOptions{WhenPipelineSucceeds: true},
+		},
+		{
+			name:      "no pipeline",
+			mr:        &domain.MergeRequest{State: domain.StateOpened},
+			approvals: domain.NewApprovals([]*domain.User{alice, bob}),
+		},
+		{
+			name:      "unknown pipeline",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, PipelineUnknown: true},
+			approvals: domain.NewApprovals([]*domain.User{alice, bob}),
+			failed:    []string{"Pipeline"},
+		},
+		{
+			name:      "unknown pipeline left to the backend",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, PipelineUnknown: true},
+			approvals: domain.NewApprovals([]*domain.User{alice, bob}),
+			opts:      domain.MergeOptions{WhenPipelineSucceeds: true},
+		},
+		{
+			name:      "unresolved thread",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, PipelineStatus: domain.PipelineSuccess},
+			approvals: domain.NewApprovals([]*domain.User{alice, bob}),
+			threads:   []*domain.Discussion{{Resolvable: true}, {Resolvable: true, Resolved: true}},
+			failed:    []string{"Threads"},
+		},
+		{
+			name:      "code owner approval left",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, PipelineStatus: domain.PipelineSuccess},
+			approvals: &domain.Approvals{ApprovedBy: []*domain.User{alice, bob}, Required: 2, Left: 1},
+			failed:    []string{"Approvals"},
+		},
+		{
+			name:      "single approval required",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, PipelineStatus: domain.PipelineSuccess},
+			approvals: &domain.Approvals{ApprovedBy: []*domain.User{alice}, Required: 1},
+		},
+		{
+			name:      "no approval required",
+			mr:        &domain.MergeRequest{State: domain.StateOpened, PipelineStatus: domain.PipelineSuccess},
+			approvals: &domain.Approvals{Required: 0, Left: 0},
+		},
+		{
+			name: "conflicts with the target branch",
+			mr: &domain.MergeRequest{
+				State:               domain.StateOpened,
+				PipelineStatus:      domain.PipelineSuccess,
+				DetailedMergeStatus: domain.MergeStatusConflict,
+				HasConflicts:        true,
+			},
+			approvals: domain.NewApprovals([]*domain.User{alice, bob}),
+			failed:    []string{"Mergeability"},
+		},
+		{
+			name:      "merged without enough approvals",
+			mr:        &domain.MergeRequest{State: domain.StateMerged},
+			approvals: domain.NewApprovals([]*domain.User{alice}),
+			failed:    []string{"State", "Approvals"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockRepository{}
+			app := &App{repo: repo, teamUsers: []string{}}
+
+			tt.mr.IID, tt.mr.ProjectID, tt.mr.UpdatedAt = 7, 42, time.Now()
+			repo.On("GetMergeRequest", mock.Anything, 42, 7).Return(tt.mr, nil)
+			repo.On("GetMergeRequestApprovals", mock.Anything, 42, 7).Return(tt.approvals, nil)
+			repo.On("ListMergeRequestDiscussions", mock.Anything, 42, 7).Return(tt.threads, nil)
+
+			mr, checks, err := app.CheckMerge(context.Background(), 42, 7, &tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.approvals.ApprovedBy), mr.ApprovalCount)
+
+			var failed []string
+			for _, check := range checks {
+				if !check.Passed {
+					failed = append(failed, check.Name)
+				}
+			}
+			assert.Equal(t, tt.failed, failed)
+		})
+	}
+}
+
+func TestApp_MergeMergeRequest(t *testing.T) {
+	repo := &mocks.MockRepository{}
+	app := &App{repo: repo, teamUsers: []string{}}
+	opts := &domain.MergeOptions{Squash: true}
+
+	repo.On("MergeMergeRequest", mock.Anything, 42, 7, opts).Return(assert.AnError)
+
+	err := app.MergeMergeRequest(context.Background(), 42, 7, opts)
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Contains(t, err.Error(), "failed to merge merge request")
+}
+
+func TestApp_RebaseMergeRequest(t *testing.T) {
+	t.Run("waits for the rebase", func(t *testing.T) {
+		repo := &mocks.MockRepository{}
+		app := &App{repo: repo, teamUsers: []string{}}
+
+		repo.On("RebaseMergeRequest", mock.Anything, 42, 7).Return(nil)
+		repo.On("GetMergeRequest", mock.Anything, 42, 7).
+			Return(&domain.MergeRequest{IID: 7, RebaseInProgress: true}, nil).Once()
+		repo.On("GetMergeRequest", mock.Anything, 42, 7).
+			Return(&domain.MergeRequest{IID: 7, DetailedMergeStatus: domain.MergeStatusMergeable}, nil).Once()
+
+		mr, err := app.RebaseMergeRequest(context.Background(), 42, 7, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, domain.MergeStatusMergeable, mr.DetailedMergeStatus)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("reports the merge error", func(t *testing.T) {
+		repo := &mocks.MockRepository{}
+		app := &App{repo: repo, teamUsers: []string{}}
+
+		repo.On("RebaseMergeRequest", mock.Anything, 42, 7).Return(nil)
+		repo.On("GetMergeRequest", mock.Anything, 42, 7).
+			Return(&domain.MergeRequest{IID: 7, MergeError: "Rebase failed: conflicts"}, nil)
+
+		_, err := app.RebaseMergeRequest(context.Background(), 42, 7, time.Millisecond)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Rebase failed: conflicts")
+	})
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/log"
+)
+
+// groupPrefix marks team entries that name a group or subgroup instead of a user.
+const groupPrefix = "@"
+
+// errNoTeam is returned when a command needs a team but none is selected.
+var errNoTeam = errors.New("no team selected: pass --team or configure a default team")
+
+// teamDirectory keeps track of every username handed to the repository for
+// preloading. Repositories that list merge requests per user replace their
+// preloaded usernames on every call, so group members found later are added
+// to the ones already known. It is shared by all team-specific copies of an App.
+type teamDirectory struct {
+	mu        sync.Mutex
+	usernames []string
+	known     map[string]struct{}
+}
+
+// preload passes usernames to the repository together with all usernames
+// preloaded before. Nothing is done when every username is already known.
+func (d *teamDirectory) preload(ctx context.Context, repo Repository, usernames []string) error {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.known == nil {
+		d.known = make(map[string]struct{})
+	}
+
+	added := false
+	for _, username := range usernames {
+		if _, ok := d.known[username]; !ok {
+			d.known[username] = struct{}{}
+			d.usernames = append(d.usernames, username)
+			added = true
+		}
+	}
+
+	if !added {
+		return nil
+	}
+
+	if err := repo.PreloadUsersByUsernames(ctx, d.usernames); err != nil {
+		return fmt.Errorf("failed to preload users: %w", err)
+	}
+
+	return nil
+}
+
+// Teams returns the names of all named teams in alphabetical order.
+func (a *App) Teams() []string {
+	names := make([]string, 0, len(a.teams))
+	for name := range a.teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ForTeam returns a copy of the application that works with the named team.
+// An empty name keeps the default team.
+func (a *App) ForTeam(name string) (*App, error) {
+	if name == "" {
+		return a, nil
+	}
+
+	members, ok := a.teams[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown team %q", name)
+	}
+
+	team := *a
+	team.teamUsers = members
+
+	return &team, nil
+}
+
+// ForProject returns a copy of the application that works with the default
+// team of the project at projectPath, or the application itself when the
+// project has no default team.
+func (a *App) ForProject(projectPath string) *App {
+	name, ok := a.projectTeams[projectPath]
+	if !ok {
+		return a
+	}
+
+	team, err := a.ForTeam(name)
+	if err != nil {
+		return a
+	}
+
+	return team
+}
+
+// teamMembers resolves the selected team to users. Group entries are expanded
+// to their members; users that cannot be found are skipped.
+func (a *App) teamMembers(ctx context.Context) ([]*domain.User, error) {
+	if len(a.teamUsers) == 0 {
+		return nil, errNoTeam
+	}
+
+	seen := make(map[int]struct{})
+	members := make([]*domain.User, 0, len(a.teamUsers))
+	add := func(user *domain.User) {
+		if _, ok := seen[user.ID]; !ok {
+			seen[user.ID] = struct{}{}
+			members = append(members, user)
+		}
+	}
+
+	for _, entry := range a.teamUsers {
+		group, ok := strings.CutPrefix(entry, groupPrefix)
+		if !ok {
+			user, err := a.repo.GetUserByUsername(ctx, entry)
+			if err != nil {
+				continue
+			}
+			add(user)
+
+			continue
+		}
+
+		users, err := a.groupMembers(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			add(user)
+		}
+	}
+
+	return members, nil
+}
+
+// groupMembers lists the members of a group and preloads them, so that their
+// merge requests are listed like those of other team members.
+func (a *App) groupMembers(ctx context.Context, group string) ([]*domain.User, error) {
+	users, err := a.repo.ListGroupMembers(ctx, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members of group %s: %w", group, err)
+	}
+
+	usernames := make([]string, 0, len(users))
+	for _, user := range users {
+		usernames = append(usernames, user.Username)
+	}
+
+	if err := a.directory.preload(ctx, a.repo, usernames); err != nil {
+		log.Warnf("%v", err)
+	}
+
+	return users, nil
+}
+
+// plainUsernames returns the usernames listed directly, not through groups,
+// in the default team and all named teams.
+func plainUsernames(defaultTeam []string, teams map[string][]string) []string {
+	names := make([]string, 0, len(teams))
+	for name := range teams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lists := [][]string{defaultTeam}
+	for _, name := range names {
+		lists = append(lists, teams[name])
+	}
+
+	seen := make(map[string]struct{})
+	var usernames []string
+	for _, list := range lists {
+		for _, entry := range list {
+			if _, ok := seen[entry]; ok || strings.HasPrefix(entry, groupPrefix) {
+				continue
+			}
+			seen[entry] = struct{}{}
+			usernames = append(usernames, entry)
+		}
+	}
+
+	return usernames
+}
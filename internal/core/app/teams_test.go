@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/mocks"
+	"github.com/denchenko/gg/internal/config"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewApp_PreloadsAllTeams(t *testing.T) {
+	repo := &mocks.MockRepository{}
+	repo.On("PreloadUsersByUsernames", mock.Anything, []string{"alice", "bob", "carol"}).Return(nil)
+
+	_, err := NewApp(&config.Config{
+		TeamUsers: []string{"alice", "@acme/backend"},
+		Teams: map[string][]string{
+			"web": {"carol", "alice"},
+			"api": {"bob"},
+		},
+	}, repo)
+	require.NoError(t, err)
+
+	repo.AssertExpectations(t)
+}
+
+func TestApp_ForTeam(t *testing.T) {
+	app := &App{
+		teamUsers:    []string{"alice"},
+		teams:        map[string][]string{"web": {"bob"}, "api": {"carol"}},
+		projectTeams: map[string]string{"acme/api": "api"},
+	}
+
+	assert.Equal(t, []string{"api", "web"}, app.Teams())
+
+	same, err := app.ForTeam("")
+	require.NoError(t, err)
+	assert.Same(t, app, same)
+
+	web, err := app.ForTeam("web")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, web.teamUsers)
+	assert.Equal(t, []string{"alice"}, app.teamUsers, "the original keeps its team")
+
+	_, err = app.ForTeam("missing")
+	require.EqualError(t, err, `unknown team "missing"`)
+
+	assert.Equal(t, []string{"carol"}, app.ForProject("acme/api").teamUsers)
+	assert.Same(t, app, app.ForProject("acme/web"))
+}
+
+func TestApp_teamMembers(t *testing.T) {
+	ctx := context.Background()
+
+	alice := &domain.User{ID: 1, Username: "alice"}
+	bob := &domain.User{ID: 2, Username: "bob"}
+	carol := &domain.User{ID: 3, Username: "carol"}
+
+	repo := &mocks.MockRepository{}
+	repo.On("GetUserByUsername", ctx, "alice").Return(alice, nil)
+	repo.On("GetUserByUsername", ctx, "ghost").Return(nil, errors.New("not found"))
+	repo.On("ListGroupMembers", ctx, "acme/backend").Return([]*domain.User{alice, bob, carol}, nil)
+	repo.On("PreloadUsersByUsernames", ctx, []string{"alice", "bob", "carol"}).Return(nil).Once()
+
+	app := &App{repo: repo, teamUsers: []string{"alice", "ghost", "@acme/backend"}, directory: &teamDirectory{}}
+
+	members, err := app.teamMembers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.User{alice, bob, carol}, members)
+
+	// Known group members are not preloaded again.
+	_, err = app.teamMembers(ctx)
+	require.NoError(t, err)
+
+	repo.AssertExpectations(t)
+
+	_, err = (&App{repo: repo}).teamMembers(ctx)
+	require.ErrorIs(t, err, errNoTeam)
+}
+
+func TestApp_CheckTeamMembers(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &mocks.MockRepository{}
+	repo.On("GetUserByUsername", ctx, "alice").Return(&domain.User{ID: 1, Username: "alice"}, nil)
+	repo.On("ListGroupMembers", ctx, "acme/empty").Return([]*domain.User{}, nil)
+
+	app := &App{
+		repo:      repo,
+		teamUsers: []string{"alice"},
+		teams:     map[string][]string{"web": {"@acme/empty"}},
+	}
+
+	checks := app.CheckTeamMembers(ctx)
+	require.Len(t, checks, 2)
+
+	assert.Empty(t, checks[0].Team)
+	assert.Equal(t, "alice", checks[0].Username)
+	require.NoError(t, checks[0].Err)
+
+	assert.Equal(t, "web", checks[1].Team)
+	assert.Equal(t, "@acme/empty", checks[1].Username)
+	assert.Nil(t, checks[1].User)
+	require.Error(t, checks[1].Err)
+}
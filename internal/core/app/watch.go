@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+
+	"github.com/denchenko/gg/internal/core/domain"
+)
+
+// WatchSource selects the merge requests a Watcher polls.
+type WatchSource int
+
+const (
+	// WatchMyMergeRequests polls the merge requests of the current user.
+	WatchMyMergeRequests WatchSource = iota
+	// WatchMyReviews polls the review workload of the current user.
+	WatchMyReviews
+)
+
+// ChangeKind describes what happened to a merge request between two polls.
+type ChangeKind string
+
+const (
+	ChangeReviewRequested ChangeKind = "review-requested"
+	ChangeApproved        ChangeKind = "approved"
+	ChangeCommented       ChangeKind = "commented"
+	ChangeReadyToMerge    ChangeKind = "ready-to-merge"
+	ChangeStalled         ChangeKind = "stalled"
+)
+
+// Change is a notable difference of a merge request between two polls.
+type Change struct {
+	Kind         ChangeKind
+	MergeRequest *domain.MergeRequestWithStatus
+	// Users are the new approvers of a ChangeApproved.
+	Users []*domain.User
+	// Comments is the number of new comments of a ChangeCommented.
+	Comments int
+}
+
+// Watcher polls merge requests and reports what changed since the previous
+// poll.
+type Watcher struct {
+	app      *App
+	source   WatchSource
+	snapshot map[domain.MergeRequestKey]*domain.MergeRequestWithStatus
+}
+
+// NewWatcher creates a Watcher of the given source.
+func (a *App) NewWatcher(source WatchSource) *Watcher {
+	return &Watcher{app: a, source: source}
+}
+
+// Poll fetches the merge requests of the source and returns them along with
+// the changes since the previous successful poll. The first poll reports no
+// changes.
+func (w *Watcher) Poll(ctx context.Context) ([]*domain.MergeRequestWithStatus, []*Change, error) {
+	var (
+		mrs []*domain.MergeRequestWithStatus
+		err error
+	)
+
+	switch w.source {
+	case WatchMyReviews:
+		mrs, err = w.app.GetMyReviewWorkloadWithStatus(ctx)
+	default:
+		mrs, err = w.app.GetMergeRequestsWithStatus(ctx)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []*Change
+	if w.snapshot != nil {
+		changes = diffMergeRequests(w.snapshot, mrs, w.source == WatchMyReviews)
+	}
+
+	w.snapshot = make(map[domain.MergeRequestKey]*domain.MergeRequestWithStatus, len(mrs))
+	for _, mr := range mrs {
+		w.snapshot[mr.Key()] = mr
+	}
+
+	return mrs, changes, nil
+}
+
+// diffMergeRequests compares merge requests with their previous state.
+// Merge requests missing from previous are review requests when
+// newAreReviewRequests is set, and are otherwise not reported. Approvals and
+// discussions that could not be fetched keep their previous values, and
+// changes depending on values never fetched are not reported.
+func diffMergeRequests(
+	previous map[domain.MergeRequestKey]*domain.MergeRequestWithStatus,
+	mrs []*domain.MergeRequestWithStatus,
+	newAreReviewRequests bool,
+) []*Change {
+	var changes []*Change
+
+	for _, mr := range mrs {
+		prev, ok := previous[mr.Key()]
+		if !ok {
+			if newAreReviewRequests {
+				changes = append(changes, &Change{Kind: ChangeReviewRequested, MergeRequest: mr})
+			}
+
+			continue
+		}
+
+		keepUnknown(prev, mr)
+		approvalsKnown := !prev.ApprovalsUnknown && !mr.ApprovalsUnknown
+		statusKnown := approvalsKnown && !prev.DiscussionsUnknown && !mr.DiscussionsUnknown
+
+		if approvers := newApprovers(prev.Approvals, mr.Approvals); approvalsKnown && len(approvers) > 0 {
+			changes = append(changes, &Change{Kind: ChangeApproved, MergeRequest: mr, Users: approvers})
+		}
+
+		if comments := mr.CommentCount - prev.CommentCount; comments > 0 {
+			changes = append(changes, &Change{Kind: ChangeCommented, MergeRequest: mr, Comments: comments})
+		}
+
+		if statusKnown && mr.IsReadyToMerge() && !prev.IsReadyToMerge() {
+			changes = append(changes, &Change{Kind: ChangeReadyToMerge, MergeRequest: mr})
+		}
+
+		if mr.IsStalled && !prev.IsStalled {
+			changes = append(changes, &Change{Kind: ChangeStalled, MergeRequest: mr})
+		}
+	}
+
+	return changes
+}
+
+// keepUnknown gives mr the approvals and discussions of prev that could not
+// be fetched for it, which are only unknown if they were unknown for prev too.
+func keepUnknown(prev, mr *domain.MergeRequestWithStatus) {
+	if mr.ApprovalsUnknown {
+		mr.Approvals, mr.ApprovalCount = prev.Approvals, prev.ApprovalCount
+		mr.ApprovalsRequired, mr.ApprovalsLeft = prev.ApprovalsRequired, prev.ApprovalsLeft
+		mr.ApprovalsUnknown = prev.ApprovalsUnknown
+	}
+
+	if mr.DiscussionsUnknown {
+		mr.Discussions, mr.IsAnswered = prev.Discussions, prev.IsAnswered
+		mr.ResolvableThreads, mr.UnresolvedThreads = prev.ResolvableThreads, prev.UnresolvedThreads
+		mr.DiscussionsUnknown = prev.DiscussionsUnknown
+	}
+}
+
+// newApprovers returns the users of approvals missing from previous.
+func newApprovers(previous, approvals []*domain.User) []*domain.User {
+	var approvers []*domain.User
+	for _, user := range approvals {
+		if !hasUserApprovedMR(previous, user.ID) {
+			approvers = append(approvers, user)
+		}
+	}
+
+	return approvers
+}
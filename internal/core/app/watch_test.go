@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/adapters/secondary/repository/mocks"
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Poll(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.MockRepository{}
+	app := &App{repo: repo, teamUsers: []string{}}
+
+	now := time.Now()
+	alice := &domain.User{ID: 2, Username: "alice"}
+	bob := &domain.User{ID: 3, Username: "bob"}
+
+	first := []*domain.MergeRequest{
+		{ID: 1, IID: 1, ProjectID: 1, UpdatedAt: now, CommentCount: 1},
+		{ID: 2, IID: 2, ProjectID: 1, UpdatedAt: now},
+	}
+	second := []*domain.MergeRequest{
+		{ID: 1, IID: 1, ProjectID: 1, UpdatedAt: now, CommentCount: 3},
+		{ID: 2, IID: 2, ProjectID: 1, UpdatedAt: now.Add(-7 * 24 * time.Hour)},
+		{ID: 3, IID: 3, ProjectID: 1, UpdatedAt: now},
+	}
+
+	repo.On("ListMergeRequests", mock.Anything, openedMergeRequests(domain.ScopeCreatedByMe)).Return(first, nil).Once()
+	repo.On("ListMergeRequests", mock.Anything, openedMergeRequests(domain.ScopeCreatedByMe)).Return(second, nil).Once()
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(domain.NewApprovals([]*domain.User{alice}), nil).Once()
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(domain.NewApprovals([]*domain.User{alice, bob}), nil).Once()
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 2).Return(domain.NewApprovals([]*domain.User{}), nil)
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 3).Return(domain.NewApprovals([]*domain.User{}), nil)
+	repo.On("ListMergeRequestDiscussions", mock.Anything, 1, mock.Anything).Return([]*domain.Discussion{}, nil)
+	repo.On("GetProject", mock.Anything, mock.Anything).Return(nil, assert.AnError).Maybe()
+
+	watcher := app.NewWatcher(WatchMyMergeRequests)
+
+	mrs, changes, err := watcher.Poll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, mrs, 2)
+	assert.Empty(t, changes, "the first poll reports no changes")
+
+	mrs, changes, err = watcher.Poll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, mrs, 3)
+
+	kinds := make(map[domain.MergeRequestKey][]ChangeKind)
+	for _, change := range changes {
+		kinds[change.MergeRequest.Key()] = append(kinds[change.MergeRequest.Key()], change.Kind)
+
+		switch change.Kind {
+		case ChangeApproved:
+			assert.Equal(t, []*domain.User{bob}, change.Users)
+		case ChangeCommented:
+			assert.Equal(t, 2, change.Comments)
+		}
+	}
+
+	assert.Equal(t, []ChangeKind{ChangeApproved, ChangeCommented, ChangeReadyToMerge},
+		kinds[domain.MergeRequestKey{ProjectID: 1, IID: 1}])
+	assert.Equal(t, []ChangeKind{ChangeStalled}, kinds[domain.MergeRequestKey{ProjectID: 1, IID: 2}])
+	assert.Empty(t, kinds[domain.MergeRequestKey{ProjectID: 1, IID: 3}],
+		"new merge requests of the user are not reported")
+	repo.AssertExpectations(t)
+}
+
+func TestWatcher_Poll_FailedFetch(t *testing.T) {
+	ctx := context.Background()
+	repo := &mocks.MockRepository{}
+	app := &App{repo: repo, teamUsers: []string{}}
+
+	alice := &domain.User{ID: 2, Username: "alice"}
+	approved := &domain.Approvals{ApprovedBy: []*domain.User{alice}, Required: 1, Left: 0}
+	mrs := []*domain.MergeRequest{{ID: 1, IID: 1, ProjectID: 1, UpdatedAt: time.Now()}}
+
+	repo.On("ListMergeRequests", mock.Anything, openedMergeRequests(domain.ScopeCreatedByMe)).Return(mrs, nil)
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(approved, nil).Once()
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(nil, assert.AnError).Once()
+	repo.On("GetMergeRequestApprovals", mock.Anything, 1, 1).Return(approved, nil).Once()
+	repo.On("ListMergeRequestDiscussions", mock.Anything, 1, 1).Return([]*domain.Discussion{}, nil).Once()
+	repo.On("ListMergeRequestDiscussions", mock.Anything, 1, 1).Return(nil, assert.AnError).Once()
+	repo.On("ListMergeRequestDiscussions", mock.Anything, 1, 1).Return([]*domain.Discussion{}, nil).Once()
+	repo.On("GetProject", mock.Anything, mock.Anything).Return(nil, assert.AnError).Maybe()
+
+	watcher := app.NewWatcher(WatchMyMergeRequests)
+
+	_, changes, err := watcher.Poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	got, changes, err := watcher.Poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes, "a failed fetch is not a change")
+	require.Len(t, got, 1)
+	assert.Equal(t, []*domain.User{alice}, got[0].Approvals, "the previous approvals are kept")
+	assert.True(t, got[0].IsReadyToMerge())
+
+	_, changes, err = watcher.Poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changes, "fetching again does not report the approvals as new")
+	repo.AssertExpectations(t)
+}
+
+func TestDiffMergeRequests_UnknownStatus(t *testing.T) {
+	alice := &domain.User{ID: 2, Username: "alice"}
+	unknown := &domain.MergeRequestWithStatus{
+		MergeRequest:       &domain.MergeRequest{IID: 1, ProjectID: 1},
+		ApprovalsUnknown:   true,
+		DiscussionsUnknown: true,
+	}
+	unknown.SetApprovals(domain.NewApprovals(nil))
+	fetched := &domain.MergeRequestWithStatus{MergeRequest: &domain.MergeRequest{IID: 1, ProjectID: 1}}
+	fetched.SetApprovals(&domain.Approvals{ApprovedBy: []*domain.User{alice}, Required: 1})
+
+	changes := diffMergeRequests(map[domain.MergeRequestKey]*domain.MergeRequestWithStatus{unknown.Key(): unknown},
+		[]*domain.MergeRequestWithStatus{fetched}, false)
+	assert.Empty(t, changes, "values never fetched before are not compared")
+}
+
+func TestDiffMergeRequests_ReviewRequests(t *testing.T) {
+	existing := &domain.MergeRequestWithStatus{MergeRequest: &domain.MergeRequest{IID: 1, ProjectID: 1}}
+	added := &domain.MergeRequestWithStatus{MergeRequest: &domain.MergeRequest{IID: 1, ProjectID: 2}}
+	previous := map[domain.MergeRequestKey]*domain.MergeRequestWithStatus{existing.Key(): existing}
+
+	changes := diffMergeRequests(previous, []*domain.MergeRequestWithStatus{existing, added}, true)
+
+	require.Len(t, changes, 1)
+	assert.Equal(t, ChangeReviewRequested, changes[0].Kind)
+	assert.Same(t, added, changes[0].MergeRequest)
+}
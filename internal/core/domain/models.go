@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
 
 type User struct {
 	ID       int
@@ -9,6 +13,16 @@ type User struct {
 	Status   UserStatus
 }
 
+// TokenInfo describes the access token in use.
+type TokenInfo struct {
+	User *User
+	// Name is empty when the server does not name tokens.
+	Name   string
+	Scopes []string
+	// ExpiresAt is nil for tokens that do not expire or when the expiry is unknown.
+	ExpiresAt *time.Time
+}
+
 type UserStatus struct {
 	Message      string
 	Availability string
@@ -28,15 +42,316 @@ type MergeRequest struct {
 	ProjectID    int
 	Draft        bool
 	SourceBranch string
+	TargetBranch string
+	Labels       []string
+	// State is one of StateOpened, StateClosed and StateMerged.
+	State string
+	// PipelineStatus is the status of the head pipeline, such as
+	// PipelineSuccess. It is empty when there is no pipeline or when
+	// PipelineUnknown is set.
+	PipelineStatus string
+	// PipelineUnknown is set when the backend did not report the pipeline,
+	// so an empty PipelineStatus does not mean there is none.
+	PipelineUnknown bool
+	// FailedJobs lists the names of the jobs that failed the head pipeline,
+	// leaving out those allowed to fail.
+	FailedJobs []string
+	// HeadRef is the ref of the target repository pointing to the head of
+	// the merge request, which also works for merge requests from forks.
+	HeadRef string
+	// Fork tells that the source branch belongs to a fork of the project,
+	// so it may share its name with an unrelated branch of the project.
+	Fork bool
+	// CommentCount is the number of comments left by users.
+	CommentCount int
+	// Milestone is the title of the milestone, empty when there is none.
+	Milestone string
+	// ChangedFiles is the number of files the merge request changes. It is
+	// zero when unknown.
+	ChangedFiles int
+	// DetailedMergeStatus tells whether the merge request can be merged, such
+	// as MergeStatusMergeable, or what prevents it. It is empty when the
+	// backend does not report it.
+	DetailedMergeStatus string
+	HasConflicts        bool
+	// DivergedCommits is the number of commits of the target branch missing
+	// from the source branch. It is only reported for a single merge request.
+	DivergedCommits int
+	// RebaseInProgress and MergeError tell how a rebase requested on the
+	// server is going. They are only reported for a single merge request.
+	RebaseInProgress bool
+	MergeError       string
+	// Approvals is the approval state when the backend reports it along
+	// with the merge request, nil otherwise.
+	Approvals *Approvals
+}
+
+// Detailed merge statuses. The others tell why a merge request cannot be
+// merged yet.
+const (
+	MergeStatusMergeable  = "mergeable"
+	MergeStatusConflict   = "conflict"
+	MergeStatusNeedRebase = "need_rebase"
+)
+
+// NeedsRebase reports whether the source branch has to be rebased before
+// merging, because it conflicts with the target branch or the project only
+// allows fast-forward merges.
+func (mr *MergeRequest) NeedsRebase() bool {
+	return mr.HasConflicts || mr.DetailedMergeStatus == MergeStatusConflict ||
+		mr.DetailedMergeStatus == MergeStatusNeedRebase
+}
+
+// Merge request states.
+const (
+	StateOpened = "opened"
+	StateClosed = "closed"
+	StateMerged = "merged"
+)
+
+// Pipeline statuses. Pipelines in any other status are still in progress.
+const (
+	PipelineSuccess  = "success"
+	PipelineFailed   = "failed"
+	PipelineCanceled = "canceled"
+	PipelineSkipped  = "skipped"
+)
+
+// PipelineInProgress reports whether a pipeline in the given status has yet
+// to finish.
+func PipelineInProgress(status string) bool {
+	switch status {
+	case "", PipelineSuccess, PipelineFailed, PipelineCanceled, PipelineSkipped:
+		return false
+	default:
+		return true
+	}
+}
+
+// HasFailedPipeline reports whether the head pipeline failed, which leaves
+// the next step to the author rather than to the reviewers.
+func (mr *MergeRequest) HasFailedPipeline() bool {
+	return mr.PipelineStatus == PipelineFailed
+}
+
+// MergeOptions controls how a merge request is merged.
+type MergeOptions struct {
+	// WhenPipelineSucceeds sets the merge request to merge once its head
+	// pipeline succeeds instead of merging it right away.
+	WhenPipelineSucceeds bool
+	Squash               bool
+	RemoveSourceBranch   bool
+}
+
+// Key returns the project-scoped identity of the merge request.
+func (mr *MergeRequest) Key() MergeRequestKey {
+	return MergeRequestKey{ProjectID: mr.ProjectID, IID: mr.IID}
+}
+
+// CheckoutBranch returns the local branch to check the merge request out to
+// when its source branch cannot be used, as in "mr/42".
+func (mr *MergeRequest) CheckoutBranch() string {
+	return "mr/" + strconv.Itoa(mr.IID)
+}
+
+// MergeRequestKey identifies a merge request across projects. IIDs are only
+// unique within a project, so both parts are required.
+type MergeRequestKey struct {
+	ProjectID int
+	IID       int
+}
+
+// String returns the key in "projectID!iid" form.
+func (k MergeRequestKey) String() string {
+	return strconv.Itoa(k.ProjectID) + "!" + strconv.Itoa(k.IID)
+}
+
+// DefaultRequiredApprovals is the number of approvals a merge request needs
+// when the backend does not report how many the project requires.
+const DefaultRequiredApprovals = 2
+
+// Approvals is the approval state of a merge request.
+type Approvals struct {
+	ApprovedBy []*User
+	// Required is the number of approvals the merge request needs and Left
+	// how many it still misses. Left is not always Required minus the
+	// approvals given, as approval rules may require specific approvers.
+	Required int
+	Left     int
+}
+
+// NewApprovals returns the approval state of a merge request approved by
+// approvedBy that needs DefaultRequiredApprovals approvals, for backends that
+// do not report how many the project requires.
+func NewApprovals(approvedBy []*User) *Approvals {
+	return &Approvals{
+		ApprovedBy: approvedBy,
+		Required:   DefaultRequiredApprovals,
+		Left:       max(DefaultRequiredApprovals-len(approvedBy), 0),
+	}
+}
+
+// ApprovalRule is a rule of a merge request telling who has to approve it.
+type ApprovalRule struct {
+	Name string
+	// Type is the kind of rule, such as "regular", "code_owner" or
+	// "any_approver".
+	Type              string
+	Required          int
+	Approved          bool
+	ApprovedBy        []*User
+	EligibleApprovers []*User
 }
 
 type MergeRequestWithStatus struct {
 	*MergeRequest
-	Approvals        []*User
-	ApprovalCount    int
-	IsStalled        bool
-	IsCurrentBranch  bool
-	IsCurrentProject bool
+	Approvals     []*User
+	ApprovalCount int
+	// ApprovalsRequired and ApprovalsLeft are set with SetApprovals.
+	// ApprovalRules are only set for a single merge request.
+	ApprovalsRequired int
+	ApprovalsLeft     int
+	ApprovalRules     []*ApprovalRule
+	// ApprovalsUnknown and DiscussionsUnknown are set when the approvals or
+	// the discussions could not be fetched, leaving empty ones in their place.
+	ApprovalsUnknown   bool
+	DiscussionsUnknown bool
+	IsStalled          bool
+	IsCurrentBranch   bool
+	IsCurrentProject  bool
+	// Discussions are the comment threads of the merge request, set with
+	// SetDiscussions along with the thread counts.
+	Discussions       []*Discussion
+	ResolvableThreads int
+	UnresolvedThreads int
+	// IsAnswered tells, in a review workload, that someone replied to the
+	// comments of the reviewer.
+	IsAnswered bool
+}
+
+// IsReadyToMerge reports whether the merge request has all the approvals it
+// needs and no unresolved threads. A project that requires no approval lets
+// merge requests nobody approved be merged.
+func (mr *MergeRequestWithStatus) IsReadyToMerge() bool {
+	return mr.ApprovalsLeft == 0 && mr.UnresolvedThreads == 0
+}
+
+// SetApprovals sets the approvals of the merge request along with how many it
+// needs and misses.
+func (mr *MergeRequestWithStatus) SetApprovals(approvals *Approvals) {
+	mr.Approvals = approvals.ApprovedBy
+	mr.ApprovalCount = len(approvals.ApprovedBy)
+	mr.ApprovalsRequired = approvals.Required
+	mr.ApprovalsLeft = approvals.Left
+}
+
+// SetDiscussions sets the discussions of the merge request and counts its
+// resolvable and unresolved threads. Note URLs relative to the merge request,
+// such as "#note_1", are resolved against its web URL.
+func (mr *MergeRequestWithStatus) SetDiscussions(discussions []*Discussion) {
+	mr.Discussions = discussions
+	mr.ResolvableThreads, mr.UnresolvedThreads = 0, 0
+
+	for _, d := range discussions {
+		for _, note := range d.Notes {
+			if strings.HasPrefix(note.WebURL, "#") && mr.MergeRequest != nil {
+				note.WebURL = mr.WebURL + note.WebURL
+			}
+		}
+		if d.Resolvable {
+			mr.ResolvableThreads++
+		}
+		if d.IsUnresolved() {
+			mr.UnresolvedThreads++
+		}
+	}
+}
+
+// UnresolvedDiscussions returns the threads that still have to be resolved.
+func (mr *MergeRequestWithStatus) UnresolvedDiscussions() []*Discussion {
+	var unresolved []*Discussion
+	for _, d := range mr.Discussions {
+		if d.IsUnresolved() {
+			unresolved = append(unresolved, d)
+		}
+	}
+
+	return unresolved
+}
+
+// Discussion is a thread of comments on a merge request.
+type Discussion struct {
+	ID string
+	// Resolvable tells whether the thread can be resolved, as review threads
+	// can, unlike plain comments.
+	Resolvable bool
+	Resolved   bool
+	// Notes are the comments of the thread, oldest first.
+	Notes []*Note
+}
+
+// Note is a comment of a discussion.
+type Note struct {
+	ID        int
+	Author    *User
+	Body      string
+	CreatedAt time.Time
+	// WebURL links to the note, possibly relative to the merge request.
+	WebURL string
+}
+
+// IsUnresolved reports whether the thread is resolvable and not resolved yet.
+func (d *Discussion) IsUnresolved() bool {
+	return d.Resolvable && !d.Resolved
+}
+
+// LastNote returns the latest comment of the thread, or nil when it has none.
+func (d *Discussion) LastNote() *Note {
+	if len(d.Notes) == 0 {
+		return nil
+	}
+
+	return d.Notes[len(d.Notes)-1]
+}
+
+// WaitingOn returns whom the thread waits for: the author of the merge
+// request when someone else commented last, otherwise the last other
+// participant. It is nil when the author is the only participant.
+func (d *Discussion) WaitingOn(author *User) *User {
+	if author == nil {
+		return nil
+	}
+
+	for i := len(d.Notes) - 1; i >= 0; i-- {
+		noteAuthor := d.Notes[i].Author
+		if noteAuthor == nil || noteAuthor.ID == author.ID {
+			continue
+		}
+		if i == len(d.Notes)-1 {
+			return author
+		}
+
+		return noteAuthor
+	}
+
+	return nil
+}
+
+// IsAnsweredFor reports whether user commented on the thread and someone else
+// commented after the last comment of user.
+func (d *Discussion) IsAnsweredFor(user *User) bool {
+	commented, answered := false, false
+	for _, note := range d.Notes {
+		switch {
+		case note.Author == nil:
+		case note.Author.ID == user.ID:
+			commented, answered = true, false
+		case commented:
+			answered = true
+		}
+	}
+
+	return answered
 }
 
 type Commit struct {
@@ -56,8 +371,21 @@ type UserWorkload struct {
 }
 
 type Project struct {
-	ID   int
-	Path string
+	ID            int
+	Path          string
+	DefaultBranch string
+}
+
+// NewMergeRequest describes a merge request to create.
+type NewMergeRequest struct {
+	Title        string
+	Description  string
+	SourceBranch string
+	TargetBranch string
+	Draft        bool
+	Labels       []string
+	AssigneeID   *int
+	ReviewerIDs  []int
 }
 
 type Event struct {
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Merge request scopes, relative to the current user.
+const (
+	ScopeCreatedByMe  = "created_by_me"
+	ScopeAssignedToMe = "assigned_to_me"
+	ScopeAll          = "all"
+)
+
+// Merge request sort keys.
+const (
+	// SortUpdated puts the most recently updated merge requests first.
+	SortUpdated = "updated"
+	// SortCreated puts the most recently created merge requests first.
+	SortCreated = "created"
+	// SortAge puts the oldest merge requests first.
+	SortAge = "age"
+	// SortSize puts the merge requests changing the most files first.
+	SortSize = "size"
+)
+
+// SortKeys returns every merge request sort key.
+func SortKeys() []string {
+	return []string{SortUpdated, SortCreated, SortAge, SortSize}
+}
+
+// MergeRequestQuery selects merge requests. Fields left empty do not filter.
+// Backends apply the filters they support when listing and Matches tells
+// whether a merge request satisfies all of them.
+type MergeRequestQuery struct {
+	// State is one of StateOpened, StateClosed and StateMerged, or empty for
+	// merge requests in any state.
+	State string
+	// Scope is one of ScopeCreatedByMe (the default), ScopeAssignedToMe and
+	// ScopeAll. Backends that cannot list every merge request of the
+	// instance resolve ScopeAll to the merge requests involving the current
+	// user or a preloaded team member, unless another filter narrows it down.
+	Scope string
+	// Author, Assignee and Reviewer are usernames.
+	Author   string
+	Assignee string
+	Reviewer string
+	// Project and Group are paths, such as "group/project". Group includes
+	// its subgroups.
+	Project string
+	Group   string
+	// Labels keeps the merge requests that have all of the labels.
+	Labels    []string
+	Milestone string
+	// Draft keeps drafts when true and merge requests ready for review when
+	// false.
+	Draft        *bool
+	SourceBranch string
+	TargetBranch string
+	// CreatedAfter and CreatedBefore bound the creation time.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Sort is one of the sort keys. Merge requests are sorted by the caller,
+	// backends only use it to fetch what sorting needs.
+	Sort string
+}
+
+// HasFilters reports whether the query filters on more than the state and
+// the scope.
+func (q *MergeRequestQuery) HasFilters() bool {
+	return q.Author != "" || q.Assignee != "" || q.Reviewer != "" || q.Project != "" || q.Group != "" ||
+		len(q.Labels) > 0 || q.Milestone != "" || q.Draft != nil || q.SourceBranch != "" || q.TargetBranch != "" ||
+		q.CreatedAfter != nil || q.CreatedBefore != nil
+}
+
+// HasOwner reports whether the query names a project, a group or a user,
+// which bound the merge requests it selects.
+func (q *MergeRequestQuery) HasOwner() bool {
+	return q.Project != "" || q.Group != "" || q.Author != "" || q.Assignee != "" || q.Reviewer != ""
+}
+
+// Matches reports whether the merge request satisfies the filters of the
+// query. The scope depends on the current user and is left to the backends.
+func (q *MergeRequestQuery) Matches(mr *MergeRequest) bool {
+	switch {
+	case q.State != "" && mr.State != "" && mr.State != q.State,
+		q.Author != "" && !hasUsername([]*User{mr.Author}, q.Author),
+		q.Assignee != "" && !hasUsername([]*User{mr.Assignee}, q.Assignee),
+		q.Reviewer != "" && !hasUsername(mr.Reviewers, q.Reviewer),
+		q.Project != "" && !mr.InProject(q.Project),
+		q.Group != "" && !mr.InGroup(q.Group),
+		q.Milestone != "" && !strings.EqualFold(mr.Milestone, q.Milestone),
+		q.Draft != nil && mr.Draft != *q.Draft,
+		q.SourceBranch != "" && mr.SourceBranch != q.SourceBranch,
+		q.TargetBranch != "" && mr.TargetBranch != q.TargetBranch,
+		q.CreatedAfter != nil && mr.CreatedAt.Before(*q.CreatedAfter),
+		q.CreatedBefore != nil && !mr.CreatedAt.Before(*q.CreatedBefore):
+		return false
+	}
+
+	for _, label := range q.Labels {
+		if !slices.ContainsFunc(mr.Labels, func(l string) bool { return strings.EqualFold(l, label) }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// InProject reports whether the merge request belongs to the project with the
+// given path, judging by its web URL.
+func (mr *MergeRequest) InProject(path string) bool {
+	projectURL, _, ok := SplitMergeRequestURL(mr.WebURL)
+
+	return ok && strings.HasSuffix(strings.ToLower(projectURL), "/"+strings.ToLower(strings.Trim(path, "/")))
+}
+
+// InGroup reports whether the merge request belongs to a project of the group
+// with the given path or of one of its subgroups, judging by its web URL.
+func (mr *MergeRequest) InGroup(path string) bool {
+	projectURL, _, ok := SplitMergeRequestURL(mr.WebURL)
+
+	return ok && strings.Contains(strings.ToLower(projectURL), "/"+strings.ToLower(strings.Trim(path, "/"))+"/")
+}
+
+func hasUsername(users []*User, username string) bool {
+	return slices.ContainsFunc(users, func(u *User) bool {
+		return u != nil && strings.EqualFold(u.Username, username)
+	})
+}
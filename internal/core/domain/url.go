@@ -0,0 +1,25 @@
+package domain
+
+import "strings"
+
+// mergeRequestURLMarkers separate the project URL from the merge request
+// number in web URLs of the supported backends.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var mergeRequestURLMarkers = []string{
+	"/-/merge_requests/", // GitLab
+	"/pull/",             // GitHub
+	"/pulls/",            // Gitea, Forgejo
+}
+
+// SplitMergeRequestURL splits a merge request web URL into the project URL
+// and the merge request number.
+func SplitMergeRequestURL(webURL string) (projectURL, number string, ok bool) {
+	for _, marker := range mergeRequestURLMarkers {
+		if projectURL, number, ok = strings.Cut(webURL, marker); ok {
+			return projectURL, strings.TrimSuffix(number, "/"), true
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,127 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+const (
+	configDir = "gg"
+	fileName  = "credentials"
+)
+
+// ErrNotFound is returned when no token is stored for an instance.
+var ErrNotFound = errors.New("token not found")
+
+// Store keeps access tokens per instance base URL.
+type Store interface {
+	// Name describes where tokens are kept, e.g. for "gg auth status".
+	Name() string
+	Get(baseURL string) (string, error)
+	Set(baseURL, token string) error
+	Delete(baseURL string) error
+}
+
+// Default returns the Secret Service keyring when it is usable and an
+// encrypted file in the user config directory otherwise. Tokens missing from
+// the keyring are looked up in the file and moved to the keyring.
+//
+// The store is shared by the whole process, so that the passphrase of the
+// file is asked for at most once.
+//
+//nolint:gochecknoglobals // shared by the process on purpose
+var Default = sync.OnceValues(func() (Store, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+
+	file := NewFileStore(filepath.Join(dir, configDir, fileName), Passphrase)
+	if keyring := NewKeyring(); keyring.Available() {
+		return &migratingStore{Store: keyring, fallback: file}, nil
+	}
+
+	return file, nil
+})
+
+// migratingStore moves tokens from a fallback store to Store as they are read.
+type migratingStore struct {
+	Store
+
+	fallback Store
+}
+
+// Get returns the token stored for baseURL, moving it from the fallback
+// store when only that one has it. A failed move keeps the token where it is.
+func (s *migratingStore) Get(baseURL string) (string, error) {
+	token, err := s.Store.Get(baseURL)
+	if !errors.Is(err, ErrNotFound) {
+		return token, err
+	}
+
+	token, err = s.fallback.Get(baseURL)
+	if err != nil {
+		return "", err
+	}
+
+	if s.Store.Set(baseURL, token) == nil {
+		_ = s.fallback.Delete(baseURL)
+	}
+
+	return token, nil
+}
+
+// Delete removes the token stored for baseURL from both stores.
+func (s *migratingStore) Delete(baseURL string) error {
+	err := s.Store.Delete(baseURL)
+	if fallbackErr := s.fallback.Delete(baseURL); !errors.Is(fallbackErr, ErrNotFound) {
+		return errors.Join(err, fallbackErr)
+	}
+
+	return err
+}
+
+// Passphrase returns the passphrase for the encrypted file store from
+// GG_PASSPHRASE or, on a terminal, by asking for it.
+func Passphrase(path string) ([]byte, error) {
+	if passphrase := os.Getenv("GG_PASSPHRASE"); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	passphrase, err := ReadSecret(fmt.Sprintf("Passphrase for %s: ", path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase (set GG_PASSPHRASE when not on a terminal): %w", err)
+	}
+
+	return []byte(passphrase), nil
+}
+
+// ReadSecret asks for a secret on the terminal without echoing it. When
+// standard input is not a terminal, the first line of it is used instead.
+func ReadSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd()) //nolint:gosec // file descriptors fit into int
+
+	if !term.IsTerminal(fd) {
+		var line string
+		if _, err := fmt.Fscanln(os.Stdin, &line); err != nil {
+			return "", fmt.Errorf("failed to read from standard input: %w", err)
+		}
+
+		return strings.TrimSpace(line), nil
+	}
+
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from terminal: %w", err)
+	}
+
+	return strings.TrimSpace(string(secret)), nil
+}
@@ -0,0 +1,47 @@
+package credentials
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeyringStore returns a keyring keeping its single secret in a file.
+func fakeKeyringStore(t *testing.T) *Keyring {
+	t.Helper()
+
+	secret := filepath.Join(t.TempDir(), "secret")
+
+	return fakeSecretTool(t, `case "$1" in
+lookup) cat '`+secret+`' 2>/dev/null || exit 1 ;;
+store) cat > '`+secret+`' ;;
+clear) rm -f '`+secret+`' ;;
+esac`)
+}
+
+func TestMigratingStore(t *testing.T) {
+	var calls int
+	file := NewFileStore(filepath.Join(t.TempDir(), "credentials"), fixedPassphrase("secret", &calls))
+	require.NoError(t, file.Set("https://gitlab.com", "glpat-1"))
+
+	keyring := fakeKeyringStore(t)
+	store := &migratingStore{Store: keyring, fallback: file}
+
+	token, err := store.Get("https://gitlab.com")
+	require.NoError(t, err)
+	assert.Equal(t, "glpat-1", token)
+
+	token, err = keyring.Get("https://gitlab.com")
+	require.NoError(t, err)
+	assert.Equal(t, "glpat-1", token, "the token is moved to the keyring")
+
+	_, err = file.Get("https://gitlab.com")
+	require.ErrorIs(t, err, ErrNotFound, "the token is removed from the file")
+	assert.Equal(t, 1, calls, "the passphrase is asked for once")
+
+	require.NoError(t, store.Delete("https://gitlab.com"))
+	_, err = store.Get("https://gitlab.com")
+	require.ErrorIs(t, err, ErrNotFound)
+}
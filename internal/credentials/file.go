@@ -0,0 +1,217 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+	pbkdf2Iterations = 600_000
+	keyLength        = 32
+	saltLength       = 16
+
+	fileDirPerm = 0o700
+	filePerm    = 0o600
+)
+
+// FileStore keeps tokens in a file encrypted with AES-256-GCM under a key
+// derived from a passphrase. It is safe for concurrent use.
+type FileStore struct {
+	mu         sync.Mutex
+	path       string
+	passphrase func(path string) ([]byte, error)
+	key        []byte
+	salt       []byte
+}
+
+// encryptedFile is the on-disk format of a FileStore.
+type encryptedFile struct {
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NewFileStore creates a file store at path. The passphrase is requested at
+// most once, on first access.
+func NewFileStore(path string, passphrase func(path string) ([]byte, error)) *FileStore {
+	return &FileStore{path: path, passphrase: passphrase}
+}
+
+// Name describes the store.
+func (s *FileStore) Name() string {
+	return "encrypted file " + s.path
+}
+
+// Get returns the token stored for baseURL.
+func (s *FileStore) Get(baseURL string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return "", err
+	}
+
+	token, ok := tokens[baseURL]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return token, nil
+}
+
+// Set stores the token for baseURL, replacing any previous one.
+func (s *FileStore) Set(baseURL, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	tokens[baseURL] = token
+
+	return s.write(tokens)
+}
+
+// Delete removes the token stored for baseURL.
+func (s *FileStore) Delete(baseURL string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := tokens[baseURL]; !ok {
+		return ErrNotFound
+	}
+
+	delete(tokens, baseURL)
+
+	return s.write(tokens)
+}
+
+// read decrypts the stored tokens. A missing file holds no tokens and does
+// not ask for the passphrase.
+func (s *FileStore) read() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials %s: %w", s.path, err)
+	}
+
+	gcm, err := s.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		// Forget the key so that the passphrase can be retried.
+		s.key = nil
+
+		return nil, fmt.Errorf("failed to decrypt credentials %s: wrong passphrase or corrupted file", s.path)
+	}
+
+	tokens := map[string]string{}
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials %s: %w", s.path, err)
+	}
+
+	return tokens, nil
+}
+
+func (s *FileStore) write(tokens map[string]string) error {
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	salt := s.salt
+	if salt == nil {
+		salt = make([]byte, saltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+
+	gcm, err := s.cipher(salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	data, err := json.Marshal(encryptedFile{
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode credentials: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), fileDirPerm); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, filePerm); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+
+	return nil
+}
+
+// cipher returns an AES-GCM cipher keyed from the passphrase and salt. The
+// derived key is cached because PBKDF2 is deliberately slow.
+func (s *FileStore) cipher(salt []byte) (cipher.AEAD, error) {
+	if s.key == nil || string(s.salt) != string(salt) {
+		passphrase, err := s.passphrase(s.path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := pbkdf2.Key(sha256.New, string(passphrase), salt, pbkdf2Iterations, keyLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+
+		s.key, s.salt = key, salt
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,67 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedPassphrase(passphrase string, calls *int) func(string) ([]byte, error) {
+	return func(string) ([]byte, error) {
+		*calls++
+
+		return []byte(passphrase), nil
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gg", "credentials")
+
+	var calls int
+	store := NewFileStore(path, fixedPassphrase("secret", &calls))
+
+	_, err := store.Get("https://gitlab.com")
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Zero(t, calls, "a missing file must not ask for the passphrase")
+
+	require.NoError(t, store.Set("https://gitlab.com", "glpat-1"))
+	require.NoError(t, store.Set("https://gitlab.example.com", "glpat-2"))
+	assert.Equal(t, 1, calls)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "glpat-1")
+
+	reopened := NewFileStore(path, fixedPassphrase("secret", &calls))
+	token, err := reopened.Get("https://gitlab.com")
+	require.NoError(t, err)
+	assert.Equal(t, "glpat-1", token)
+
+	require.NoError(t, reopened.Delete("https://gitlab.com"))
+	_, err = reopened.Get("https://gitlab.com")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	token, err = reopened.Get("https://gitlab.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "glpat-2", token)
+}
+
+func TestFileStore_WrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+
+	var calls int
+	require.NoError(t, NewFileStore(path, fixedPassphrase("secret", &calls)).Set("https://gitlab.com", "glpat-1"))
+
+	_, err := NewFileStore(path, fixedPassphrase("guess", &calls)).Get("https://gitlab.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wrong passphrase")
+	assert.False(t, errors.Is(err, ErrNotFound))
+}
@@ -0,0 +1,101 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	secretTool     = "secret-tool"
+	keyringService = "gg"
+)
+
+// Keyring stores tokens in the Secret Service keyring (GNOME Keyring, KWallet)
+// through the secret-tool command from libsecret.
+type Keyring struct {
+	command string
+}
+
+// NewKeyring creates a keyring store backed by secret-tool.
+func NewKeyring() *Keyring {
+	return &Keyring{command: secretTool}
+}
+
+// Available reports whether secret-tool is installed and a session bus to
+// reach the Secret Service is present.
+func (k *Keyring) Available() bool {
+	if _, err := exec.LookPath(k.command); err != nil {
+		return false
+	}
+
+	return os.Getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+}
+
+// Name describes the store.
+func (k *Keyring) Name() string {
+	return "Secret Service keyring"
+}
+
+// Get returns the token stored for baseURL.
+func (k *Keyring) Get(baseURL string) (string, error) {
+	output, err := k.run("", "lookup", "service", keyringService, "url", baseURL)
+
+	token := strings.TrimSpace(string(output))
+	switch {
+	case token != "":
+		return token, nil
+	case err == nil || isNoMatch(err):
+		return "", ErrNotFound
+	default:
+		return "", err
+	}
+}
+
+// Set stores the token for baseURL, replacing any previous one.
+func (k *Keyring) Set(baseURL, token string) error {
+	_, err := k.run(token, "store", "--label", "gg token for "+baseURL, "service", keyringService, "url", baseURL)
+
+	return err
+}
+
+// Delete removes the token stored for baseURL.
+func (k *Keyring) Delete(baseURL string) error {
+	_, err := k.run("", "clear", "service", keyringService, "url", baseURL)
+
+	return err
+}
+
+// run runs secret-tool. Errors carry what it printed to stderr, which Output
+// collects in the exit error.
+func (k *Keyring) run(stdin string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(context.Background(), k.command, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if message := strings.TrimSpace(string(exitErr.Stderr)); message != "" {
+				return output, fmt.Errorf("%s %s: %s: %w", k.command, args[0], message, err)
+			}
+		}
+
+		return output, fmt.Errorf("%s %s: %w", k.command, args[0], err)
+	}
+
+	return output, nil
+}
+
+// isNoMatch reports whether secret-tool failed because nothing matched, which
+// it tells by exiting with status 1 without printing anything. A locked
+// keyring or an unreachable Secret Service prints why.
+func isNoMatch(err error) bool {
+	var exitErr *exec.ExitError
+
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(bytes.TrimSpace(exitErr.Stderr)) == 0
+}
@@ -0,0 +1,39 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretTool returns a keyring running a shell script in place of
+// secret-tool.
+func fakeSecretTool(t *testing.T, script string) *Keyring {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "secret-tool")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o700)) //nolint:gosec // test script
+
+	return &Keyring{command: path}
+}
+
+func TestKeyring_Get(t *testing.T) {
+	token, err := fakeSecretTool(t, "echo token").Get("https://gitlab.com")
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+
+	_, err = fakeSecretTool(t, "exit 1").Get("https://gitlab.com")
+	require.ErrorIs(t, err, ErrNotFound, "secret-tool exits with 1 silently when nothing matches")
+
+	_, err = fakeSecretTool(t, "echo 'Cannot unlock collection' >&2; exit 1").Get("https://gitlab.com")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, err.Error(), "Cannot unlock collection")
+
+	_, err = fakeSecretTool(t, "exit 2").Get("https://gitlab.com")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,141 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/denchenko/gg/internal/core/domain"
+)
+
+// Activity description constants.
+const (
+	commitTitleMaxLen = 60
+	commitTitleTrunc  = 57
+	noteBodyMaxLen    = 80
+	noteBodyTrunc     = 77
+)
+
+// ActivityDescription describes an activity event in a single line.
+func ActivityDescription(event *domain.Event) string {
+	action := strings.ToLower(event.Action)
+	targetType := strings.ToLower(event.TargetType)
+
+	// Handle push events
+	if (targetType == "" || strings.Contains(action, "push") || event.Action == "deleted") && event.PushRef != "" {
+		return formatPushEventDescription(event)
+	}
+
+	// Handle note/comment events
+	if targetType == "note" || strings.Contains(action, "comment") {
+		return formatCommentEventDescription(event)
+	}
+
+	// Handle merge request events
+	if targetType == "mergerequest" || targetType == "merge_request" {
+		return formatMergeRequestEventDescription(event)
+	}
+
+	// Handle issue events
+	if targetType == "issue" {
+		return formatIssueEventDescription(event)
+	}
+
+	// Default formatter
+	return formatDefaultEventDescription(event)
+}
+
+func formatPushEventDescription(event *domain.Event) string {
+	ref := normalizeRef(event.PushRef)
+	refType := getRefType(event.PushRef)
+	pushAction := event.PushAction
+	if pushAction == "" {
+		pushAction = event.Action
+	}
+
+	desc := fmt.Sprintf("%s %s %s", pushAction, refType, ref)
+	if event.CommitCount > 0 {
+		desc += fmt.Sprintf(" (%d commit%s", event.CommitCount, pluralize(event.CommitCount))
+		if event.CommitTitle != "" {
+			title := truncateText(event.CommitTitle, commitTitleMaxLen, commitTitleTrunc)
+			desc += ": " + title
+		}
+		desc += ")"
+	}
+
+	return desc
+}
+
+func formatCommentEventDescription(event *domain.Event) string {
+	desc := "commented"
+	if event.TargetTitle != "" {
+		desc += ": " + event.TargetTitle
+	}
+	if event.NoteBody != "" {
+		body := strings.ReplaceAll(event.NoteBody, "\n", " ")
+		body = truncateText(body, noteBodyMaxLen, noteBodyTrunc)
+		desc += fmt.Sprintf(" (%s)", body)
+	}
+
+	return desc
+}
+
+func formatMergeRequestEventDescription(event *domain.Event) string {
+	desc := event.Action
+	if event.TargetTitle != "" {
+		desc += ": " + event.TargetTitle
+	}
+
+	return desc
+}
+
+func formatIssueEventDescription(event *domain.Event) string {
+	desc := event.Action
+	if event.TargetTitle != "" {
+		desc += ": " + event.TargetTitle
+	}
+
+	return desc
+}
+
+func formatDefaultEventDescription(event *domain.Event) string {
+	desc := event.Action
+	if event.TargetType != "" {
+		desc += " " + event.TargetType
+	}
+	if event.TargetTitle != "" {
+		desc += ": " + event.TargetTitle
+	}
+
+	return desc
+}
+
+func normalizeRef(ref string) string {
+	ref = strings.TrimPrefix(ref, "refs/tags/")
+	ref = strings.TrimPrefix(ref, "refs/heads/")
+
+	return ref
+}
+
+func getRefType(ref string) string {
+	if strings.HasPrefix(ref, "refs/tags/") {
+		return "tag"
+	}
+
+	return "branch"
+}
+
+func truncateText(text string, maxLen, truncLen int) string {
+	if len(text) > maxLen {
+		return text[:truncLen] + "..."
+	}
+
+	return text
+}
+
+func pluralize(count int) string {
+	if count == 1 {
+		return ""
+	}
+
+	return "s"
+}
@@ -0,0 +1,109 @@
+package ascii
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// Names of the templates. User templates are looked up as <name>.tmpl.
+const (
+	TemplateMyMR       = "my_mr"
+	TemplateMyReview   = "my_review"
+	TemplateTeamReview = "team_review"
+	TemplateMRRoulette = "mr_roulette"
+	TemplateMRStatus   = "mr_status"
+	TemplateMyActivity = "my_activity"
+	TemplateMRList     = "mr_list"
+
+	templateExt = ".tmpl"
+)
+
+// TemplateNames returns the names of all templates.
+func TemplateNames() []string {
+	return []string{
+		TemplateMyMR,
+		TemplateMyReview,
+		TemplateTeamReview,
+		TemplateMRRoulette,
+		TemplateMRStatus,
+		TemplateMyActivity,
+		TemplateMRList,
+	}
+}
+
+// TemplateFileName returns the file name of the named template.
+func TemplateFileName(name string) string {
+	return name + templateExt
+}
+
+// DefaultTemplate returns the embedded template with the given name.
+func DefaultTemplate(name string) (string, bool) {
+	text, ok := map[string]string{
+		TemplateMyMR:       myMRTemplate,
+		TemplateMyReview:   myReviewTemplate,
+		TemplateTeamReview: teamReviewTemplate,
+		TemplateMRRoulette: mrRouletteTemplate,
+		TemplateMRStatus:   mrStatusTemplate,
+		TemplateMyActivity: myActivityTemplate,
+		TemplateMRList:     mrListTemplate,
+	}[name]
+
+	return text, ok
+}
+
+// WithTemplateFile returns a copy of the formatter that renders every view
+// with the template in *path instead, unless *path is empty. The path is read
+// when formatting, so that it can be bound to a command-line flag.
+func (f *Formatter) WithTemplateFile(path *string) *Formatter {
+	withFile := *f
+	withFile.templateFile = path
+
+	return &withFile
+}
+
+// parse parses the template with the given name: the template file, if set,
+// else the file in the template directory, else the embedded template.
+func (f *Formatter) parse(name string, funcs template.FuncMap) (*template.Template, error) {
+	text, source, err := f.templateText(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.New(source).Funcs(funcs).Parse(text)
+}
+
+// templateText returns the text of the template with the given name and where
+// it comes from, used to name the template in errors.
+func (f *Formatter) templateText(name string) (string, string, error) {
+	if f.templateFile != nil && *f.templateFile != "" {
+		data, err := os.ReadFile(*f.templateFile)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read template: %w", err)
+		}
+
+		return string(data), *f.templateFile, nil
+	}
+
+	if f.templateDir != "" {
+		path := filepath.Join(f.templateDir, TemplateFileName(name))
+
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return string(data), path, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", "", fmt.Errorf("failed to read template: %w", err)
+		}
+	}
+
+	text, ok := DefaultTemplate(name)
+	if !ok {
+		return "", "", fmt.Errorf("unknown template %q", name)
+	}
+
+	return text, name, nil
+}
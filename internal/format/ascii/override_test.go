@@ -0,0 +1,62 @@
+package ascii
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/issue"
+	"github.com/denchenko/gg/internal/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatter_templateOverrides(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, TemplateFileName(TemplateTeamReview)),
+		[]byte(`{{range .Workloads}}{{.User.Username}}={{len .ActiveMRs}} {{end}}`), 0o600))
+
+	workloads := []*domain.UserWorkload{{User: &domain.User{Username: "alice"}}}
+	issuer := issue.NewIssuer("")
+
+	t.Run("embedded", func(t *testing.T) {
+		out, err := NewFormatter(issuer, "").FormatTeamWorkload(workloads)
+		require.NoError(t, err)
+		assert.Contains(t, out, "alice")
+		assert.NotEqual(t, "alice=0 ", out)
+	})
+
+	t.Run("template directory", func(t *testing.T) {
+		out, err := NewFormatter(issuer, dir).FormatTeamWorkload(workloads)
+		require.NoError(t, err)
+		assert.Equal(t, "alice=0 ", out)
+
+		// Templates missing from the directory fall back to the embedded ones.
+		_, err = NewFormatter(issuer, dir).FormatMyActivity("", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("template file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "custom.tmpl")
+		require.NoError(t, os.WriteFile(file, []byte(`{{bold "team"}} {{len .Workloads}}`), 0o600))
+
+		path := ""
+		color := terminal.ColorAlways
+		f := NewFormatter(issuer, dir).WithTemplateFile(&path).WithColor(&color)
+
+		path = file
+		out, err := f.FormatTeamWorkload(workloads)
+		require.NoError(t, err)
+		assert.Equal(t, "\033[1mteam\033[0m 1", out)
+	})
+
+	t.Run("parse error names the file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "broken.tmpl")
+		require.NoError(t, os.WriteFile(file, []byte("{{.Workloads"), 0o600))
+
+		_, err := NewFormatter(issuer, "").WithTemplateFile(&file).FormatTeamWorkload(workloads)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), file)
+	})
+}
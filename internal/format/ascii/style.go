@@ -0,0 +1,249 @@
+package ascii
+
+import (
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/terminal"
+)
+
+const (
+	// minBoxWidth is the narrowest box drawn on narrow terminals.
+	minBoxWidth = 40
+	// wrapIndent indents the continuation of wrapped lines.
+	wrapIndent = "  "
+
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+// style describes the terminal a report is rendered for.
+type style struct {
+	// color enables ANSI colors and text attributes.
+	color bool
+	// width is the width of boxes.
+	width int
+	// wrap wraps lines longer than width.
+	wrap bool
+}
+
+// WithColor returns a copy of the formatter that colors its output according
+// to *mode. The mode is read when formatting, so that it can be bound to a
+// command-line flag.
+func (f *Formatter) WithColor(mode *terminal.ColorMode) *Formatter {
+	withColor := *f
+	withColor.color = mode
+
+	return &withColor
+}
+
+// style returns the style for the standard output: boxes shrink to narrow
+// terminals and long lines are wrapped, while output that is not written to
+// a terminal keeps full-width boxes and unbroken lines.
+func (f *Formatter) style() style {
+	mode := terminal.ColorAuto
+	if f.color != nil {
+		mode = *f.color
+	}
+
+	s := style{
+		color: terminal.UseColor(mode, os.Stdout),
+		width: boxWidth,
+	}
+	if width := terminal.Width(os.Stdout); width > 0 {
+		s.width = min(max(width, minBoxWidth), boxWidth)
+		s.wrap = true
+	}
+
+	return s
+}
+
+func (s style) paint(code, text string) string {
+	if !s.color {
+		return text
+	}
+
+	return code + text + ansiReset
+}
+
+func (s style) bold(text string) string {
+	return s.paint(ansiBold, text)
+}
+
+func (s style) formatBoxTitle(title string) string {
+	titleMax := s.width - boxTitlePadding // space for ┌─, ─┐, and spaces
+
+	if visibleLen(title) > titleMax {
+		title = truncateVisible(title, titleMax)
+	}
+	dashCount := s.width - visibleLen(title) - boxTitlePadding
+	if dashCount < 0 {
+		dashCount = 0
+	}
+
+	return "┌─ " + title + " " + strings.Repeat("─", dashCount) + "┐"
+}
+
+func (s style) formatBoxSeparator() string {
+	return "├" + strings.Repeat("─", s.width-boxBottomPadding) + "┤"
+}
+
+func (s style) formatBoxBottom() string {
+	return "└" + strings.Repeat("─", s.width-boxBottomPadding) + "┘"
+}
+
+// getStatusEmoji labels draft, pipeline-failed, needs-rebase, stalled and
+// ready-to-merge merge requests, and those where someone replied to the
+// reviewer.
+func (s style) getStatusEmoji(mr *domain.MergeRequestWithStatus) string {
+	var label string
+	if mr.Draft {
+		label += s.paint(ansiYellow, "[draft]") + " "
+	}
+
+	switch format.Status(mr) {
+	case format.StatusPipelineFailed:
+		label += s.paint(ansiRed, "[pipeline-failed]") + " "
+	case format.StatusNeedsRebase:
+		label += s.paint(ansiRed, "[needs-rebase]") + " "
+	case format.StatusStalled:
+		label += s.paint(ansiRed, "[stalled]") + " "
+	case format.StatusReadyToMerge:
+		label += s.paint(ansiGreen, "[ready-to-merge]") + " "
+	}
+
+	if mr.IsAnswered {
+		label += s.paint(ansiCyan, "[answered]") + " "
+	}
+
+	return label
+}
+
+// fit wraps the lines of a rendered report that are wider than the style
+// allows. The continuation of a line inside a box keeps the box border.
+func (s style) fit(text string) string {
+	if !s.wrap {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	fitted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		fitted = append(fitted, wrapLine(line, s.width)...)
+	}
+
+	return strings.Join(fitted, "\n")
+}
+
+// wrapLine breaks line into lines of at most width visible characters,
+// preferably at spaces.
+func wrapLine(line string, width int) []string {
+	if visibleLen(line) <= width {
+		return []string{line}
+	}
+
+	content := strings.TrimPrefix(line, "│")
+	border := line[:len(line)-len(content)]
+	rest := strings.TrimLeft(content, " ")
+	prefix := border + content[:len(content)-len(rest)]
+
+	var lines []string
+	for {
+		capacity := width - visibleLen(prefix)
+		if capacity < minBoxWidth/2 {
+			capacity = minBoxWidth / 2
+		}
+		if visibleLen(rest) <= capacity {
+			return append(lines, prefix+rest)
+		}
+
+		cut := visibleIndex(rest, capacity)
+		head, tail := rest[:cut], rest[cut:]
+		if strings.HasPrefix(tail, " ") {
+			tail = tail[1:]
+		} else if space := strings.LastIndexByte(head, ' '); space > len(head)/2 {
+			head, tail = rest[:space], rest[space+1:]
+		}
+		lines = append(lines, prefix+head)
+
+		if len(lines) == 1 {
+			prefix += wrapIndent
+		}
+		rest = tail
+	}
+}
+
+// visibleLen returns the number of characters of text shown on a terminal,
+// not counting ANSI escape sequences.
+func visibleLen(text string) int {
+	return utf8.RuneCountInString(stripANSI(text))
+}
+
+// visibleIndex returns the byte index in text after n visible characters.
+func visibleIndex(text string, n int) int {
+	i := 0
+	for i < len(text) && n > 0 {
+		if end := ansiSequenceEnd(text, i); end > i {
+			i = end
+
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		n--
+	}
+
+	return i
+}
+
+// truncateVisible shortens text to n visible characters, ending it with an
+// ellipsis.
+func truncateVisible(text string, n int) string {
+	truncated := text[:visibleIndex(text, n-1)] + "…"
+	if strings.Contains(text, "\033[") {
+		truncated += ansiReset
+	}
+
+	return truncated
+}
+
+func stripANSI(text string) string {
+	if !strings.Contains(text, "\033[") {
+		return text
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		if end := ansiSequenceEnd(text, i); end > i {
+			i = end
+
+			continue
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+
+	return b.String()
+}
+
+// ansiSequenceEnd returns the index after the ANSI SGR sequence starting at
+// i, or i when there is none.
+func ansiSequenceEnd(text string, i int) int {
+	if !strings.HasPrefix(text[i:], "\033[") {
+		return i
+	}
+
+	end := strings.IndexByte(text[i:], 'm')
+	if end < 0 {
+		return i
+	}
+
+	return i + end + 1
+}
@@ -0,0 +1,69 @@
+package ascii
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStyle_formatBoxTitle(t *testing.T) {
+	s := style{width: minBoxWidth}
+
+	title := s.formatBoxTitle("acme/api")
+	assert.Equal(t, minBoxWidth, visibleLen(title))
+	assert.True(t, strings.HasPrefix(title, "┌─ acme/api ─"))
+
+	long := s.formatBoxTitle(strings.Repeat("x", 2*minBoxWidth))
+	assert.Equal(t, minBoxWidth, visibleLen(long))
+	assert.Contains(t, long, "x… ┐")
+
+	colored := style{width: minBoxWidth, color: true}
+	bold := colored.formatBoxTitle(colored.bold(strings.Repeat("x", 2*minBoxWidth)))
+	assert.Equal(t, minBoxWidth, visibleLen(bold))
+	assert.Contains(t, bold, "…"+ansiReset)
+
+	assert.Equal(t, minBoxWidth, visibleLen(s.formatBoxBottom()))
+	assert.Equal(t, minBoxWidth, visibleLen(s.formatBoxSeparator()))
+}
+
+func TestStyle_getStatusEmoji(t *testing.T) {
+	mr := &domain.MergeRequestWithStatus{
+		MergeRequest: &domain.MergeRequest{Draft: true},
+		IsStalled:    true,
+	}
+
+	assert.Equal(t, "[draft] [stalled] ", style{}.getStatusEmoji(mr))
+	assert.Equal(t, "\033[33m[draft]\033[0m \033[31m[stalled]\033[0m ", style{color: true}.getStatusEmoji(mr))
+
+	mr.Draft, mr.IsStalled = false, false
+	assert.Equal(t, "[ready-to-merge] ", style{}.getStatusEmoji(mr), "no approval required")
+
+	mr.PipelineStatus = domain.PipelineFailed
+	assert.Equal(t, "[pipeline-failed] ", style{}.getStatusEmoji(mr), "a failed pipeline waits for the author")
+	assert.Equal(t, "b", style{}.bold("b"))
+}
+
+func TestStyle_fit(t *testing.T) {
+	url := "https://gitlab.example.com/" + strings.Repeat("a", 40) + "/-/merge_requests/1"
+	text := "│ Fix the flaky pipeline of the project\n│   URL: " + url + "\n└──┘"
+
+	assert.Equal(t, text, style{width: minBoxWidth}.fit(text), "not wrapped off a terminal")
+
+	fitted := style{width: minBoxWidth, wrap: true}.fit(text)
+	for _, line := range strings.Split(fitted, "\n") {
+		assert.LessOrEqual(t, visibleLen(line), minBoxWidth, line)
+		assert.True(t, strings.HasPrefix(line, "│") || line == "└──┘", line)
+	}
+	assert.Contains(t, fitted, "│ Fix the flaky pipeline of the project\n│   URL: https://")
+	assert.Equal(t, url, strings.NewReplacer("\n", "", "│", "", " ", "").Replace(
+		fitted[strings.Index(fitted, "https://"):strings.Index(fitted, "└")]))
+}
+
+func TestWrapLine(t *testing.T) {
+	assert.Equal(t, []string{"│ short"}, wrapLine("│ short", 30))
+	assert.Equal(t,
+		[]string{"│ one two three four five", "│   six seven"},
+		wrapLine("│ one two three four five six seven", 25))
+}
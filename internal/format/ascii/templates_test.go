@@ -0,0 +1,126 @@
+package ascii
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/issue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatter_FormatMRRoulette(t *testing.T) {
+	alice := &domain.User{ID: 1, Username: "alice"}
+	bob := &domain.User{ID: 2, Username: "bob"}
+	mr := &domain.MergeRequest{Title: "Add feature", Author: alice}
+	workloads := []*domain.UserWorkload{{User: alice}, {User: bob, MRCount: 2, Commits: 5}}
+	f := NewFormatter(issue.NewIssuer(""), "")
+
+	t.Run("suggestions", func(t *testing.T) {
+		out, err := f.FormatMRRoulette(mr, "", workloads, bob, bob)
+		require.NoError(t, err)
+		assert.Contains(t, out, "Suggested Assignee: bob (Active MRs: 2, Commits: 5)")
+		assert.Contains(t, out, "Suggested Reviewer: bob (Active MRs: 2, Commits: 5)")
+		assert.Contains(t, out, "alice [Not selected - Author of the MR]")
+	})
+
+	t.Run("no suggestions", func(t *testing.T) {
+		out, err := f.FormatMRRoulette(mr, "", workloads, nil, nil)
+		require.NoError(t, err)
+		assert.Contains(t, out, "No suitable assignee found")
+		assert.Contains(t, out, "No suitable reviewer found")
+	})
+}
+
+func TestFormatter_FormatMRStatus_Threads(t *testing.T) {
+	alice := &domain.User{ID: 1, Username: "alice"}
+	bob := &domain.User{ID: 2, Username: "bob"}
+	mr := &domain.MergeRequestWithStatus{
+		MergeRequest: &domain.MergeRequest{
+			Title:  "Add feature",
+			WebURL: "https://gitlab.example.com/acme/api/-/merge_requests/7",
+			Author: alice,
+		},
+		ApprovalCount: 2,
+	}
+	mr.SetDiscussions([]*domain.Discussion{
+		{Resolvable: true, Notes: []*domain.Note{
+			{Author: bob, Body: "\nPlease rename this.\nIt is confusing.", WebURL: mr.WebURL + "#note_1"},
+		}},
+		{Resolvable: true, Notes: []*domain.Note{
+			{Author: bob, Body: "Why?"},
+			{Author: alice, Body: "Because.", WebURL: mr.WebURL + "#note_3"},
+		}},
+		{Resolvable: true, Resolved: true, Notes: []*domain.Note{{Author: bob, Body: "Done"}}},
+	})
+
+	out, err := NewFormatter(issue.NewIssuer(""), "").FormatMRStatus("https://gitlab.example.com", mr)
+	require.NoError(t, err)
+
+	assert.NotContains(t, out, "[ready-to-merge]", "unresolved threads block merging")
+	assert.Contains(t, out, "Threads: 2 of 3 unresolved")
+	assert.Contains(t, out, "- bob: Please rename this. (waiting on alice)")
+	assert.Contains(t, out, "- alice: Because. (waiting on bob)")
+	assert.Contains(t, out, mr.WebURL+"#note_3")
+	assert.NotContains(t, out, "Done")
+}
+
+func TestFormatter_FormatMRStatus_ApprovalRules(t *testing.T) {
+	alice := &domain.User{ID: 1, Username: "alice"}
+	bob := &domain.User{ID: 2, Username: "bob"}
+	carol := &domain.User{ID: 3, Username: "carol"}
+	mr := &domain.MergeRequestWithStatus{
+		MergeRequest: &domain.MergeRequest{
+			Title:  "Add feature",
+			WebURL: "https://gitlab.example.com/acme/api/-/merge_requests/7",
+		},
+		ApprovalRules: []*domain.ApprovalRule{
+			{Name: "All Members", Type: "any_approver", Required: 1, Approved: true, ApprovedBy: []*domain.User{alice}},
+			{Name: "Backend", Type: "code_owner", Required: 2, ApprovedBy: []*domain.User{alice},
+				EligibleApprovers: []*domain.User{alice, bob, carol}},
+		},
+	}
+	mr.SetApprovals(&domain.Approvals{ApprovedBy: []*domain.User{alice}, Required: 3, Left: 1})
+
+	out, err := NewFormatter(issue.NewIssuer(""), "").FormatMRStatus("https://gitlab.example.com", mr)
+	require.NoError(t, err)
+
+	assert.NotContains(t, out, "[ready-to-merge]", "the code owners still have to approve")
+	assert.Contains(t, out, "Approvals left: 1 of 3 left")
+	assert.Contains(t, out, "- All Members (any approver): approved, approved by alice")
+	assert.Contains(t, out, "- Backend (code owner): 1 of 2, approved by alice, eligible: bob, carol")
+}
+
+func TestFormatter_FormatMergeRequestList(t *testing.T) {
+	alice := &domain.User{ID: 1, Username: "alice"}
+	mrs := []*domain.MergeRequestWithStatus{
+		{MergeRequest: &domain.MergeRequest{
+			Title:        "Add feature",
+			WebURL:       "https://gitlab.example.com/acme/api/-/merge_requests/7",
+			Author:       alice,
+			Labels:       []string{"backend", "bug"},
+			Milestone:    "v1.2",
+			ChangedFiles: 12,
+		}},
+		{MergeRequest: &domain.MergeRequest{
+			Title:  "Fix typo",
+			WebURL: "https://gitlab.example.com/acme/web/-/merge_requests/3",
+		}},
+	}
+	f := NewFormatter(issue.NewIssuer(""), "")
+
+	out, err := f.FormatMergeRequestList("https://gitlab.example.com", mrs)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Merge Requests (2)")
+	assert.Contains(t, out, "Project: acme/api")
+	assert.Contains(t, out, "Author: alice")
+	assert.Contains(t, out, "Labels: backend, bug")
+	assert.Contains(t, out, "Milestone: v1.2")
+	assert.Contains(t, out, "Changed files: 12")
+	assert.Less(t, strings.Index(out, "Add feature"), strings.Index(out, "Fix typo"), "the order must be kept")
+
+	out, err = f.FormatMergeRequestList("https://gitlab.example.com", nil)
+	require.NoError(t, err)
+	assert.Contains(t, out, "No merge requests found.")
+}
@@ -0,0 +1,201 @@
+// Package format defines how command results are rendered and selects the
+// renderer for the --output flag.
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/denchenko/gg/internal/core/domain"
+)
+
+// Output names an output format.
+type Output string
+
+// Supported output formats.
+const (
+	OutputTable Output = "table"
+	OutputJSON  Output = "json"
+	OutputYAML  Output = "yaml"
+	OutputCSV   Output = "csv"
+
+	OutputMarkdown Output = "markdown"
+	OutputHTML     Output = "html"
+)
+
+// Outputs returns every supported output format.
+func Outputs() []Output {
+	return []Output{OutputTable, OutputJSON, OutputYAML, OutputCSV, OutputMarkdown, OutputHTML}
+}
+
+// String implements pflag.Value.
+func (o *Output) String() string {
+	return string(*o)
+}
+
+// Set implements pflag.Value.
+func (o *Output) Set(s string) error {
+	for _, output := range Outputs() {
+		if strings.EqualFold(s, string(output)) {
+			*o = output
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported output format %q", s)
+}
+
+// Type implements pflag.Value.
+func (o *Output) Type() string {
+	return "format"
+}
+
+// ProfileMergeRequests holds the merge requests found with one profile.
+// Profile is empty for the top-level configuration, and Err is set when the
+// merge requests could not be fetched.
+type ProfileMergeRequests struct {
+	Profile       string
+	BaseURL       string
+	MergeRequests []*domain.MergeRequestWithStatus
+	Err           error
+}
+
+// Formatter renders the results of commands.
+type Formatter interface {
+	// Output returns the format the formatter renders.
+	Output() Output
+	FormatTeamWorkload(workloads []*domain.UserWorkload) (string, error)
+	FormatMyMergeRequestStatus(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error)
+	FormatProfilesMergeRequestStatus(profiles []ProfileMergeRequests) (string, error)
+	FormatMRRoulette(
+		mr *domain.MergeRequest,
+		mrURL string,
+		workloads []*domain.UserWorkload,
+		suggestedAssignee, suggestedReviewer *domain.User,
+	) (string, error)
+	FormatMyReviewWorkload(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error)
+	FormatMyActivity(baseURL string, events []*domain.Event) (string, error)
+	FormatMRStatus(baseURL string, mr *domain.MergeRequestWithStatus) (string, error)
+	// FormatMergeRequestList renders merge requests in the given order.
+	FormatMergeRequestList(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error)
+}
+
+// Switch delegates to the formatter of the output selected at the time of
+// the call, so that it can be created before command-line flags are parsed.
+type Switch struct {
+	output     *Output
+	formatters map[Output]Formatter
+}
+
+// NewSwitch creates a Switch choosing between formatters by *output.
+func NewSwitch(output *Output, formatters ...Formatter) *Switch {
+	s := &Switch{
+		output:     output,
+		formatters: make(map[Output]Formatter, len(formatters)),
+	}
+	for _, f := range formatters {
+		s.formatters[f.Output()] = f
+	}
+
+	return s
+}
+
+func (s *Switch) current() (Formatter, error) {
+	f, ok := s.formatters[*s.output]
+	if !ok {
+		return nil, fmt.Errorf("unsupported output format %q", *s.output)
+	}
+
+	return f, nil
+}
+
+// Output returns the selected output format.
+func (s *Switch) Output() Output {
+	return *s.output
+}
+
+// FormatTeamWorkload implements Formatter.
+func (s *Switch) FormatTeamWorkload(workloads []*domain.UserWorkload) (string, error) {
+	f, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	return f.FormatTeamWorkload(workloads)
+}
+
+// FormatMyMergeRequestStatus implements Formatter.
+func (s *Switch) FormatMyMergeRequestStatus(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	f, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	return f.FormatMyMergeRequestStatus(baseURL, mrs)
+}
+
+// FormatProfilesMergeRequestStatus implements Formatter.
+func (s *Switch) FormatProfilesMergeRequestStatus(profiles []ProfileMergeRequests) (string, error) {
+	f, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	return f.FormatProfilesMergeRequestStatus(profiles)
+}
+
+// FormatMRRoulette implements Formatter.
+func (s *Switch) FormatMRRoulette(
+	mr *domain.MergeRequest,
+	mrURL string,
+	workloads []*domain.UserWorkload,
+	suggestedAssignee, suggestedReviewer *domain.User,
+) (string, error) {
+	f, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	return f.FormatMRRoulette(mr, mrURL, workloads, suggestedAssignee, suggestedReviewer)
+}
+
+// FormatMyReviewWorkload implements Formatter.
+func (s *Switch) FormatMyReviewWorkload(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	f, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	return f.FormatMyReviewWorkload(baseURL, mrs)
+}
+
+// FormatMyActivity implements Formatter.
+func (s *Switch) FormatMyActivity(baseURL string, events []*domain.Event) (string, error) {
+	f, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	return f.FormatMyActivity(baseURL, events)
+}
+
+// FormatMRStatus implements Formatter.
+func (s *Switch) FormatMRStatus(baseURL string, mr *domain.MergeRequestWithStatus) (string, error) {
+	f, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	return f.FormatMRStatus(baseURL, mr)
+}
+
+// FormatMergeRequestList implements Formatter.
+func (s *Switch) FormatMergeRequestList(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	f, err := s.current()
+	if err != nil {
+		return "", err
+	}
+
+	return f.FormatMergeRequestList(baseURL, mrs)
+}
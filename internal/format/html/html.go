@@ -0,0 +1,128 @@
+// Package html renders command results as self-contained HTML pages, to
+// publish on wikis or send by email.
+package html
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/issue"
+)
+
+// Names of the templates.
+const (
+	templateMyMR       = "my_mr.tmpl"
+	templateProfiles   = "profiles.tmpl"
+	templateMyReview   = "my_review.tmpl"
+	templateTeamReview = "team_review.tmpl"
+	templateMRRoulette = "mr_roulette.tmpl"
+	templateMRStatus   = "mr_status.tmpl"
+	templateMyActivity = "my_activity.tmpl"
+	templateMRList     = "mr_list.tmpl"
+
+	// templateLayout is the page every view is rendered in; views define
+	// its "title" and "content".
+	templateLayout = "layout.tmpl"
+	// templatePartials defines the templates shared by the views.
+	templatePartials = "partials.tmpl"
+)
+
+//go:embed *.tmpl
+var templates embed.FS
+
+// section is the data of a view nested under a heading of the given level.
+type section struct {
+	Level int
+	Data  any
+}
+
+// Formatter renders HTML pages.
+type Formatter struct {
+	issuer *issue.Issuer
+}
+
+// NewFormatter creates a new Formatter instance with the given Issuer.
+func NewFormatter(issuer *issue.Issuer) *Formatter {
+	return &Formatter{issuer: issuer}
+}
+
+// Output returns format.OutputHTML.
+func (f *Formatter) Output() format.Output {
+	return format.OutputHTML
+}
+
+// FormatTeamWorkload renders the workload of every team member.
+func (f *Formatter) FormatTeamWorkload(workloads []*domain.UserWorkload) (string, error) {
+	return f.execute(templateTeamReview, "", format.NewTeamWorkloadData(workloads))
+}
+
+// FormatMyMergeRequestStatus renders my merge requests, grouped by project.
+func (f *Formatter) FormatMyMergeRequestStatus(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	return f.execute(templateMyMR, baseURL, format.NewMyMergeRequestStatusData(baseURL, mrs))
+}
+
+// FormatProfilesMergeRequestStatus renders my merge requests of every
+// profile, each under a heading naming the profile.
+func (f *Formatter) FormatProfilesMergeRequestStatus(profiles []format.ProfileMergeRequests) (string, error) {
+	return f.execute(templateProfiles, "", format.NewProfilesMergeRequestStatusData(profiles))
+}
+
+// FormatMRRoulette renders the candidates and the suggestions of the roulette.
+func (f *Formatter) FormatMRRoulette(
+	mr *domain.MergeRequest,
+	mrURL string,
+	workloads []*domain.UserWorkload,
+	suggestedAssignee, suggestedReviewer *domain.User,
+) (string, error) {
+	return f.execute(templateMRRoulette, "",
+		format.NewMRRouletteData(mr, mrURL, workloads, suggestedAssignee, suggestedReviewer))
+}
+
+// FormatMyReviewWorkload renders the merge requests to review, grouped by project.
+func (f *Formatter) FormatMyReviewWorkload(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	return f.execute(templateMyReview, baseURL, format.NewMyReviewWorkloadData(baseURL, mrs))
+}
+
+// FormatMyActivity renders my events, grouped by project.
+func (f *Formatter) FormatMyActivity(baseURL string, events []*domain.Event) (string, error) {
+	return f.execute(templateMyActivity, baseURL, format.NewMyActivityData(events))
+}
+
+// FormatMRStatus renders the status of a single merge request.
+func (f *Formatter) FormatMRStatus(baseURL string, mr *domain.MergeRequestWithStatus) (string, error) {
+	return f.execute(templateMRStatus, baseURL, mr)
+}
+
+// FormatMergeRequestList renders merge requests in a single table, keeping
+// their order.
+func (f *Formatter) FormatMergeRequestList(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	return f.execute(templateMRList, baseURL, format.NewMergeRequestListData(mrs))
+}
+
+func (f *Formatter) execute(name, baseURL string, data any) (string, error) {
+	tmpl, err := template.New(templateLayout).Funcs(f.funcs(baseURL)).
+		ParseFS(templates, templateLayout, templatePartials, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, templateLayout, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func (f *Formatter) funcs(baseURL string) template.FuncMap {
+	funcs := template.FuncMap(format.Funcs(f.issuer, baseURL))
+	funcs["section"] = func(level int, data any) section {
+		return section{Level: level, Data: data}
+	}
+
+	return funcs
+}
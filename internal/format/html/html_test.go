@@ -0,0 +1,103 @@
+package html
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseURL = "https://gitlab.example.com"
+
+func testMergeRequest() *domain.MergeRequestWithStatus {
+	return &domain.MergeRequestWithStatus{
+		MergeRequest: &domain.MergeRequest{
+			Title:     "Escape <script> & friends",
+			WebURL:    baseURL + "/acme/api/-/merge_requests/7",
+			Author:    &domain.User{ID: 1, Username: "alice"},
+			UpdatedAt: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+		},
+		ApprovalsLeft:    1,
+		IsStalled:        true,
+		IsCurrentProject: true,
+	}
+}
+
+func TestFormatter_FormatMRStatus(t *testing.T) {
+	f := NewFormatter(nil)
+
+	out, err := f.FormatMRStatus(baseURL, testMergeRequest())
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "<!DOCTYPE html>")
+	assert.Contains(t, out, "<style>", "styles are inlined")
+	assert.Contains(t, out, "<title>Escape &lt;script&gt; &amp; friends</title>")
+	assert.Contains(t, out,
+		`<h1><a href="`+baseURL+`/acme/api/-/merge_requests/7">Escape &lt;script&gt; &amp; friends</a></h1>`)
+	assert.Contains(t, out, `<tr><th>Status</th><td class="stalled">stalled</td></tr>`)
+	assert.NotContains(t, out, "<script>")
+}
+
+func TestFormatter_FormatMyMergeRequestStatus(t *testing.T) {
+	f := NewFormatter(nil)
+	mr := testMergeRequest()
+	mr.WebURL = "javascript:alert(1)"
+
+	out, err := f.FormatMyMergeRequestStatus(baseURL, []*domain.MergeRequestWithStatus{mr})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "<p>1 total, 0 ready-to-merge, 1 stalled</p>")
+	assert.Contains(t, out, `<a href="#ZgotmplZ">`, "unsafe URLs are not linked")
+}
+
+func TestFormatter_FormatMergeRequestList(t *testing.T) {
+	f := NewFormatter(nil)
+	mr := testMergeRequest()
+	mr.Labels = []string{"<b>bug</b>"}
+	mr.Milestone = "v1.2"
+	mr.ChangedFiles = 4
+
+	out, err := f.FormatMergeRequestList(baseURL, []*domain.MergeRequestWithStatus{mr})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "<title>Merge requests</title>")
+	assert.Contains(t, out, `<td class="stalled">stalled</td><td>acme/api</td>`)
+	assert.Contains(t, out, "<td>alice</td>")
+	assert.Contains(t, out, "<td>&lt;b&gt;bug&lt;/b&gt;</td><td>v1.2</td><td>4</td>")
+
+	out, err = f.FormatMergeRequestList(baseURL, nil)
+	require.NoError(t, err)
+	assert.Contains(t, out, "<p>No merge requests found.</p>")
+}
+
+func TestFormatter_FormatProfilesMergeRequestStatus(t *testing.T) {
+	f := NewFormatter(nil)
+
+	out, err := f.FormatProfilesMergeRequestStatus([]format.ProfileMergeRequests{
+		{Profile: "work", BaseURL: baseURL, MergeRequests: []*domain.MergeRequestWithStatus{testMergeRequest()}},
+		{Profile: "oss", BaseURL: "https://gitlab.com"},
+		{Profile: "", BaseURL: baseURL, Err: errors.New("401 Unauthorized")},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, `<h2>work (<a href="`+baseURL+`">`+baseURL+"</a>)</h2>\n<p>1 total")
+	assert.Contains(t, out, "<h3>acme/api</h3>")
+	assert.Contains(t, out, "<p>No open merge requests found.</p>")
+	assert.Contains(t, out, "<h2>default (<a href=\""+baseURL+"\">"+baseURL+"</a>)</h2>\n<p>Error: 401 Unauthorized</p>")
+}
+
+func TestFormatter_FormatMyActivity(t *testing.T) {
+	f := NewFormatter(nil)
+
+	out, err := f.FormatMyActivity(baseURL, []*domain.Event{
+		{Action: "opened", TargetType: "MergeRequest", TargetTitle: "Add feature", ProjectPath: "acme/api"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "<h2>acme/api</h2>")
+	assert.Contains(t, out, "<td>0001-01-01 00:00:00</td><td>")
+}
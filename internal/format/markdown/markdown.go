@@ -0,0 +1,143 @@
+// Package markdown renders command results as Markdown tables and links, to
+// paste into wikis, issues and chats.
+package markdown
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/denchenko/gg/internal/issue"
+)
+
+// Names of the templates.
+const (
+	templateMyMR       = "my_mr.tmpl"
+	templateProfiles   = "profiles.tmpl"
+	templateMyReview   = "my_review.tmpl"
+	templateTeamReview = "team_review.tmpl"
+	templateMRRoulette = "mr_roulette.tmpl"
+	templateMRStatus   = "mr_status.tmpl"
+	templateMyActivity = "my_activity.tmpl"
+	templateMRList     = "mr_list.tmpl"
+
+	// templatePartials defines the templates shared by the views.
+	templatePartials = "partials.tmpl"
+)
+
+//go:embed *.tmpl
+var templates embed.FS
+
+// markdownEscaper escapes the characters that would otherwise be read as
+// Markdown, or end a table cell.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"#", `\#`,
+	"\r\n", " ",
+	"\n", " ",
+)
+
+// section is the data of a view nested under a heading of the given level.
+type section struct {
+	Level int
+	Data  any
+}
+
+// Formatter renders Markdown documents.
+type Formatter struct {
+	issuer *issue.Issuer
+}
+
+// NewFormatter creates a new Formatter instance with the given Issuer.
+func NewFormatter(issuer *issue.Issuer) *Formatter {
+	return &Formatter{issuer: issuer}
+}
+
+// Output returns format.OutputMarkdown.
+func (f *Formatter) Output() format.Output {
+	return format.OutputMarkdown
+}
+
+// FormatTeamWorkload renders the workload of every team member.
+func (f *Formatter) FormatTeamWorkload(workloads []*domain.UserWorkload) (string, error) {
+	return f.execute(templateTeamReview, "", format.NewTeamWorkloadData(workloads))
+}
+
+// FormatMyMergeRequestStatus renders my merge requests, grouped by project.
+func (f *Formatter) FormatMyMergeRequestStatus(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	return f.execute(templateMyMR, baseURL, format.NewMyMergeRequestStatusData(baseURL, mrs))
+}
+
+// FormatProfilesMergeRequestStatus renders my merge requests of every
+// profile, each under a heading naming the profile.
+func (f *Formatter) FormatProfilesMergeRequestStatus(profiles []format.ProfileMergeRequests) (string, error) {
+	return f.execute(templateProfiles, "", format.NewProfilesMergeRequestStatusData(profiles))
+}
+
+// FormatMRRoulette renders the candidates and the suggestions of the roulette.
+func (f *Formatter) FormatMRRoulette(
+	mr *domain.MergeRequest,
+	mrURL string,
+	workloads []*domain.UserWorkload,
+	suggestedAssignee, suggestedReviewer *domain.User,
+) (string, error) {
+	return f.execute(templateMRRoulette, "",
+		format.NewMRRouletteData(mr, mrURL, workloads, suggestedAssignee, suggestedReviewer))
+}
+
+// FormatMyReviewWorkload renders the merge requests to review, grouped by project.
+func (f *Formatter) FormatMyReviewWorkload(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	return f.execute(templateMyReview, baseURL, format.NewMyReviewWorkloadData(baseURL, mrs))
+}
+
+// FormatMyActivity renders my events, grouped by project.
+func (f *Formatter) FormatMyActivity(baseURL string, events []*domain.Event) (string, error) {
+	return f.execute(templateMyActivity, baseURL, format.NewMyActivityData(events))
+}
+
+// FormatMRStatus renders the status of a single merge request.
+func (f *Formatter) FormatMRStatus(baseURL string, mr *domain.MergeRequestWithStatus) (string, error) {
+	return f.execute(templateMRStatus, baseURL, mr)
+}
+
+// FormatMergeRequestList renders merge requests in a single table, keeping
+// their order.
+func (f *Formatter) FormatMergeRequestList(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	return f.execute(templateMRList, baseURL, format.NewMergeRequestListData(mrs))
+}
+
+func (f *Formatter) execute(name, baseURL string, data any) (string, error) {
+	tmpl, err := template.New(name).Funcs(f.funcs(baseURL)).ParseFS(templates, templatePartials, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func (f *Formatter) funcs(baseURL string) template.FuncMap {
+	funcs := template.FuncMap(format.Funcs(f.issuer, baseURL))
+	funcs["md"] = markdownEscaper.Replace
+	funcs["section"] = func(level int, data any) section {
+		return section{Level: level, Data: data}
+	}
+
+	return funcs
+}
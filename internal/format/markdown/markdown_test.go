@@ -0,0 +1,135 @@
+package markdown
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseURL = "https://gitlab.example.com"
+
+func testMergeRequests() []*domain.MergeRequestWithStatus {
+	alice := &domain.User{ID: 1, Username: "alice"}
+	bob := &domain.User{ID: 2, Username: "bob_b"}
+
+	return []*domain.MergeRequestWithStatus{
+		{
+			MergeRequest: &domain.MergeRequest{
+				Title:          "Fix | pipes",
+				WebURL:         baseURL + "/acme/api/-/merge_requests/7",
+				Author:         alice,
+				Reviewers:      []*domain.User{bob},
+				UpdatedAt:      time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+				PipelineStatus: domain.PipelineSuccess,
+			},
+			Approvals:        []*domain.User{alice, bob},
+			ApprovalCount:    2,
+			IsCurrentProject: true,
+			IsCurrentBranch:  true,
+		},
+		{
+			MergeRequest: &domain.MergeRequest{
+				Title:          "Other",
+				WebURL:         baseURL + "/acme/web/-/merge_requests/3",
+				Assignee:       bob,
+				PipelineStatus: domain.PipelineFailed,
+				FailedJobs:     []string{"lint"},
+			},
+			ApprovalsLeft: 1,
+			IsStalled:     true,
+		},
+	}
+}
+
+func TestFormatter_FormatMyMergeRequestStatus(t *testing.T) {
+	f := NewFormatter(nil)
+
+	out, err := f.FormatMyMergeRequestStatus(baseURL, testMergeRequests())
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "# My merge requests\n")
+	assert.Contains(t, out, "2 total, 1 ready-to-merge, 1 stalled\n\n## acme/api\n\n")
+	assert.Contains(t, out, "## acme/web\n\n")
+	assert.Contains(t, out,
+		"| ready-to-merge | **[Fix \\| pipes]("+baseURL+"/acme/api/-/merge_requests/7)** |  | bob\\_b | alice, bob\\_b | success | 2024-05-02 10:00:00 |\n")
+	assert.Contains(t, out,
+		"| pipeline-failed | [Other]("+baseURL+"/acme/web/-/merge_requests/3) | bob\\_b | None | None | failed (lint) | 0001-01-01 00:00:00 |\n")
+	assert.NotContains(t, out, "│", "no box drawing")
+
+	out, err = f.FormatMyMergeRequestStatus(baseURL, nil)
+	require.NoError(t, err)
+	assert.Contains(t, out, "No open merge requests found.")
+}
+
+func TestFormatter_FormatMergeRequestList(t *testing.T) {
+	f := NewFormatter(nil)
+	mrs := testMergeRequests()
+	mrs[1].Labels = []string{"bug", "ui"}
+	mrs[1].Milestone = "v1.2"
+	mrs[1].ChangedFiles = 4
+
+	out, err := f.FormatMergeRequestList(baseURL, []*domain.MergeRequestWithStatus{mrs[1], mrs[0]})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "# Merge requests\n")
+	assert.Contains(t, out,
+		"| pipeline-failed | acme/web | [Other]("+baseURL+"/acme/web/-/merge_requests/3) |  | bob\\_b | None | None | bug, ui | v1.2 | 4 | failed (lint) | 0001-01-01 00:00:00 | 0001-01-01 00:00:00 |\n")
+	assert.Less(t, strings.Index(out, "acme/web"), strings.Index(out, "acme/api"), "the order must be kept")
+
+	out, err = f.FormatMergeRequestList(baseURL, nil)
+	require.NoError(t, err)
+	assert.Contains(t, out, "No merge requests found.")
+}
+
+func TestFormatter_FormatProfilesMergeRequestStatus(t *testing.T) {
+	f := NewFormatter(nil)
+
+	out, err := f.FormatProfilesMergeRequestStatus([]format.ProfileMergeRequests{
+		{Profile: "work", BaseURL: baseURL, MergeRequests: testMergeRequests()},
+		{Profile: "oss", BaseURL: "https://gitlab.com"},
+		{Profile: "", BaseURL: baseURL, Err: errors.New("401 Unauthorized")},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "## work ("+baseURL+")\n\n2 total")
+	assert.Contains(t, out, "### acme/api\n")
+	assert.Contains(t, out, "## oss (https://gitlab.com)\n\nNo open merge requests found.")
+	assert.Contains(t, out, "## default ("+baseURL+")\n\nError: 401 Unauthorized")
+}
+
+func TestFormatter_FormatTeamWorkload(t *testing.T) {
+	f := NewFormatter(nil)
+	mr := testMergeRequests()[0].MergeRequest
+	mr.Description = "First line\n\nSecond line"
+
+	out, err := f.FormatTeamWorkload([]*domain.UserWorkload{
+		{User: mr.Reviewers[0], MRCount: 1, ActiveMRs: []*domain.MergeRequest{mr}},
+		{User: mr.Author},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "## bob\\_b\n\nActive MRs: 1\n\n| Merge request |")
+	assert.Contains(t, out, "| Reviewer | alice | First line; Second line |")
+	assert.Contains(t, out, "## alice\n\nActive MRs: 0\n")
+}
+
+func TestFormatter_FormatMRRoulette(t *testing.T) {
+	f := NewFormatter(nil)
+	mr := testMergeRequests()[0].MergeRequest
+	bob := mr.Reviewers[0]
+
+	out, err := f.FormatMRRoulette(mr, mr.WebURL,
+		[]*domain.UserWorkload{{User: mr.Author}, {User: bob, Commits: 3}}, bob, nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, out, "| alice | 0 | 0 | author | author |\n")
+	assert.Contains(t, out, "| bob\\_b | 0 | 3 | selected | assignee |\n")
+	assert.Contains(t, out, "- Assignee: **bob\\_b**\n")
+	assert.Contains(t, out, "- Reviewer: no suitable reviewer found\n")
+}
@@ -0,0 +1,369 @@
+package structured
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+)
+
+// SchemaVersion is increased on every incompatible change of the documents
+// below. Adding fields is not considered incompatible.
+const SchemaVersion = 1
+
+// User is a user of the instance.
+type User struct {
+	ID       int    `json:"id"                       yaml:"id"`
+	Username string `json:"username"                 yaml:"username"`
+	// Availability is the availability the user set, such as "busy".
+	Availability  string `json:"availability,omitempty"   yaml:"availability,omitempty"`
+	StatusMessage string `json:"status_message,omitempty" yaml:"status_message,omitempty"`
+}
+
+// MergeRequest is a merge request without review status.
+type MergeRequest struct {
+	ID  int `json:"id"  yaml:"id"`
+	IID int `json:"iid" yaml:"iid"`
+	// Project is the path of the project, such as "group/project".
+	Project      string    `json:"project"          yaml:"project"`
+	ProjectID    int       `json:"project_id"       yaml:"project_id"`
+	Title        string    `json:"title"            yaml:"title"`
+	Description  string    `json:"description"      yaml:"description"`
+	WebURL       string    `json:"web_url"          yaml:"web_url"`
+	Author       *User     `json:"author"           yaml:"author"`
+	Assignee     *User     `json:"assignee"         yaml:"assignee"`
+	Reviewers    []*User   `json:"reviewers"        yaml:"reviewers"`
+	Draft        bool      `json:"draft"            yaml:"draft"`
+	SourceBranch string    `json:"source_branch"    yaml:"source_branch"`
+	TargetBranch string    `json:"target_branch"    yaml:"target_branch"`
+	Labels       []string  `json:"labels"           yaml:"labels"`
+	CreatedAt    time.Time `json:"created_at"       yaml:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"       yaml:"updated_at"`
+	// Milestone is the title of the milestone, empty when there is none.
+	// ChangedFiles counts the changed files, zero when unknown: GitLab only
+	// reports it by mr status and by mr list sorted by size.
+	Milestone    string `json:"milestone,omitempty"     yaml:"milestone,omitempty"`
+	ChangedFiles int    `json:"changed_files,omitempty" yaml:"changed_files,omitempty"`
+	// PipelineStatus is the status of the head pipeline, empty when there is
+	// none. FailedJobs names the jobs that failed it.
+	PipelineStatus string   `json:"pipeline_status,omitempty" yaml:"pipeline_status,omitempty"`
+	FailedJobs     []string `json:"failed_jobs,omitempty"     yaml:"failed_jobs,omitempty"`
+	// DetailedMergeStatus tells whether the merge request can be merged, such
+	// as "mergeable", "conflict" or "need_rebase", empty when unknown.
+	// DivergedCommits is only reported by mr status.
+	DetailedMergeStatus string `json:"detailed_merge_status,omitempty" yaml:"detailed_merge_status,omitempty"`
+	HasConflicts        bool   `json:"has_conflicts"                   yaml:"has_conflicts"`
+	DivergedCommits     int    `json:"diverged_commits"                yaml:"diverged_commits"`
+}
+
+// MergeRequestStatus is a merge request with its review status.
+type MergeRequestStatus struct {
+	MergeRequest `yaml:",inline"`
+
+	Approvals     []*User `json:"approvals"      yaml:"approvals"`
+	ApprovalCount int     `json:"approval_count" yaml:"approval_count"`
+	Stalled       bool    `json:"stalled"        yaml:"stalled"`
+	// ApprovalsRequired and ApprovalsLeft count the approvals the merge
+	// request needs and still misses. ApprovalRules are only reported by
+	// mr status, for GitLab.
+	ApprovalsRequired int             `json:"approvals_required"       yaml:"approvals_required"`
+	ApprovalsLeft     int             `json:"approvals_left"           yaml:"approvals_left"`
+	ApprovalRules     []*ApprovalRule `json:"approval_rules,omitempty" yaml:"approval_rules,omitempty"`
+	// ResolvableThreads counts the threads that can be resolved and
+	// UnresolvedThreads those of them still open. Answered tells, in a
+	// review workload, that someone replied to the comments of the reviewer.
+	ResolvableThreads int  `json:"resolvable_threads" yaml:"resolvable_threads"`
+	UnresolvedThreads int  `json:"unresolved_threads" yaml:"unresolved_threads"`
+	Answered          bool `json:"answered"           yaml:"answered"`
+	// CurrentBranch and CurrentProject tell whether the merge request belongs
+	// to the branch and project checked out in the working directory.
+	CurrentBranch  bool `json:"current_branch"  yaml:"current_branch"`
+	CurrentProject bool `json:"current_project" yaml:"current_project"`
+}
+
+// ApprovalRule tells who has to approve a merge request.
+type ApprovalRule struct {
+	Name              string  `json:"name"               yaml:"name"`
+	Type              string  `json:"type"               yaml:"type"`
+	Required          int     `json:"required"           yaml:"required"`
+	Approved          bool    `json:"approved"           yaml:"approved"`
+	ApprovedBy        []*User `json:"approved_by"        yaml:"approved_by"`
+	EligibleApprovers []*User `json:"eligible_approvers" yaml:"eligible_approvers"`
+}
+
+// Workload is the review workload of a team member.
+type Workload struct {
+	User *User `json:"user" yaml:"user"`
+	// OpenMergeRequests counts the open merge requests the user is assigned
+	// to or reviews and has not approved yet.
+	OpenMergeRequests int `json:"open_merge_requests" yaml:"open_merge_requests"`
+	// Commits counts the commits of the user in the project, only set by
+	// mr roulette.
+	Commits       int             `json:"commits"       yaml:"commits"`
+	MergeRequests []*MergeRequest `json:"merge_requests" yaml:"merge_requests"`
+}
+
+// Event is an activity event of the user.
+type Event struct {
+	ID          int       `json:"id"           yaml:"id"`
+	Action      string    `json:"action"       yaml:"action"`
+	TargetType  string    `json:"target_type"  yaml:"target_type"`
+	TargetID    int       `json:"target_id"    yaml:"target_id"`
+	TargetTitle string    `json:"target_title" yaml:"target_title"`
+	Project     string    `json:"project"      yaml:"project"`
+	ProjectID   int       `json:"project_id"   yaml:"project_id"`
+	CreatedAt   time.Time `json:"created_at"   yaml:"created_at"`
+	WebURL      string    `json:"web_url"      yaml:"web_url"`
+	// The push fields are only set for push events.
+	PushRef     string `json:"push_ref,omitempty"     yaml:"push_ref,omitempty"`
+	PushAction  string `json:"push_action,omitempty"  yaml:"push_action,omitempty"`
+	CommitCount int    `json:"commit_count,omitempty" yaml:"commit_count,omitempty"`
+	CommitTitle string `json:"commit_title,omitempty" yaml:"commit_title,omitempty"`
+	// The note fields are only set for comments.
+	NoteBody     string `json:"note_body,omitempty"     yaml:"note_body,omitempty"`
+	NoteableType string `json:"noteable_type,omitempty" yaml:"noteable_type,omitempty"`
+}
+
+// Header starts every document.
+type Header struct {
+	Version     int       `json:"version"      yaml:"version"`
+	GeneratedAt time.Time `json:"generated_at" yaml:"generated_at"`
+}
+
+func newHeader() Header {
+	return Header{Version: SchemaVersion, GeneratedAt: time.Now()}
+}
+
+// MergeRequestsDocument is written by "my mr" and "my review".
+type MergeRequestsDocument struct {
+	Header `yaml:",inline"`
+
+	MergeRequests []*MergeRequestStatus `json:"merge_requests" yaml:"merge_requests"`
+}
+
+// ProfileMergeRequests holds the merge requests found with one profile. The
+// top-level configuration has an empty profile.
+type ProfileMergeRequests struct {
+	Profile       string                `json:"profile"         yaml:"profile"`
+	BaseURL       string                `json:"base_url"        yaml:"base_url"`
+	MergeRequests []*MergeRequestStatus `json:"merge_requests"  yaml:"merge_requests"`
+	Error         string                `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ProfilesDocument is written by "my mr --all-profiles".
+type ProfilesDocument struct {
+	Header `yaml:",inline"`
+
+	Profiles []*ProfileMergeRequests `json:"profiles" yaml:"profiles"`
+}
+
+// MergeRequestDocument is written by "mr status".
+type MergeRequestDocument struct {
+	Header `yaml:",inline"`
+
+	MergeRequest *MergeRequestStatus `json:"merge_request" yaml:"merge_request"`
+}
+
+// WorkloadsDocument is written by "team review".
+type WorkloadsDocument struct {
+	Header `yaml:",inline"`
+
+	Workloads []*Workload `json:"workloads" yaml:"workloads"`
+}
+
+// RouletteDocument is written by "mr roulette".
+type RouletteDocument struct {
+	Header `yaml:",inline"`
+
+	MergeRequest      *MergeRequest `json:"merge_request"      yaml:"merge_request"`
+	SuggestedAssignee *User         `json:"suggested_assignee" yaml:"suggested_assignee"`
+	SuggestedReviewer *User         `json:"suggested_reviewer" yaml:"suggested_reviewer"`
+	// Candidates are the team members the suggestions were chosen from.
+	Candidates []*Workload `json:"candidates" yaml:"candidates"`
+}
+
+// EventsDocument is written by "my activity".
+type EventsDocument struct {
+	Header `yaml:",inline"`
+
+	Events []*Event `json:"events" yaml:"events"`
+}
+
+func newUser(user *domain.User) *User {
+	if user == nil {
+		return nil
+	}
+
+	return &User{
+		ID:            user.ID,
+		Username:      user.Username,
+		Availability:  user.Status.Availability,
+		StatusMessage: user.Status.Message,
+	}
+}
+
+func newUsers(users []*domain.User) []*User {
+	result := make([]*User, 0, len(users))
+	for _, user := range users {
+		result = append(result, newUser(user))
+	}
+
+	return result
+}
+
+func newMergeRequest(baseURL string, mr *domain.MergeRequest) *MergeRequest {
+	if mr == nil {
+		return nil
+	}
+
+	labels := mr.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	return &MergeRequest{
+		ID:           mr.ID,
+		IID:          mr.IID,
+		Project:      projectPath(baseURL, mr.WebURL),
+		ProjectID:    mr.ProjectID,
+		Title:        mr.Title,
+		Description:  mr.Description,
+		WebURL:       mr.WebURL,
+		Author:       newUser(mr.Author),
+		Assignee:     newUser(mr.Assignee),
+		Reviewers:    newUsers(mr.Reviewers),
+		Draft:        mr.Draft,
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		Labels:       labels,
+		CreatedAt:    mr.CreatedAt,
+		UpdatedAt:    mr.UpdatedAt,
+		Milestone:    mr.Milestone,
+		ChangedFiles: mr.ChangedFiles,
+
+		PipelineStatus:      mr.PipelineStatus,
+		FailedJobs:          mr.FailedJobs,
+		DetailedMergeStatus: mr.DetailedMergeStatus,
+		HasConflicts:        mr.HasConflicts,
+		DivergedCommits:     mr.DivergedCommits,
+	}
+}
+
+func newMergeRequests(baseURL string, mrs []*domain.MergeRequest) []*MergeRequest {
+	result := make([]*MergeRequest, 0, len(mrs))
+	for _, mr := range mrs {
+		result = append(result, newMergeRequest(baseURL, mr))
+	}
+
+	return result
+}
+
+func newMergeRequestStatus(baseURL string, mr *domain.MergeRequestWithStatus) *MergeRequestStatus {
+	return &MergeRequestStatus{
+		MergeRequest:   *newMergeRequest(baseURL, mr.MergeRequest),
+		Approvals:      newUsers(mr.Approvals),
+		ApprovalCount:  mr.ApprovalCount,
+		Stalled:        mr.IsStalled,
+		CurrentBranch:  mr.IsCurrentBranch,
+		CurrentProject: mr.IsCurrentProject,
+
+		ApprovalsRequired: mr.ApprovalsRequired,
+		ApprovalsLeft:     mr.ApprovalsLeft,
+		ApprovalRules:     newApprovalRules(mr.ApprovalRules),
+
+		ResolvableThreads: mr.ResolvableThreads,
+		UnresolvedThreads: mr.UnresolvedThreads,
+		Answered:          mr.IsAnswered,
+	}
+}
+
+func newApprovalRules(rules []*domain.ApprovalRule) []*ApprovalRule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	result := make([]*ApprovalRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, &ApprovalRule{
+			Name:              rule.Name,
+			Type:              rule.Type,
+			Required:          rule.Required,
+			Approved:          rule.Approved,
+			ApprovedBy:        newUsers(rule.ApprovedBy),
+			EligibleApprovers: newUsers(rule.EligibleApprovers),
+		})
+	}
+
+	return result
+}
+
+func newMergeRequestStatuses(baseURL string, mrs []*domain.MergeRequestWithStatus) []*MergeRequestStatus {
+	result := make([]*MergeRequestStatus, 0, len(mrs))
+	for _, mr := range mrs {
+		result = append(result, newMergeRequestStatus(baseURL, mr))
+	}
+
+	return result
+}
+
+func newWorkloads(baseURL string, workloads []*domain.UserWorkload) []*Workload {
+	result := make([]*Workload, 0, len(workloads))
+	for _, workload := range workloads {
+		result = append(result, &Workload{
+			User:              newUser(workload.User),
+			OpenMergeRequests: workload.MRCount,
+			Commits:           workload.Commits,
+			MergeRequests:     newMergeRequests(baseURL, workload.ActiveMRs),
+		})
+	}
+
+	return result
+}
+
+func newEvents(events []*domain.Event) []*Event {
+	result := make([]*Event, 0, len(events))
+	for _, event := range events {
+		result = append(result, &Event{
+			ID:           event.ID,
+			Action:       event.Action,
+			TargetType:   event.TargetType,
+			TargetID:     event.TargetID,
+			TargetTitle:  event.TargetTitle,
+			Project:      event.ProjectPath,
+			ProjectID:    event.ProjectID,
+			CreatedAt:    event.CreatedAt,
+			WebURL:       event.WebURL,
+			PushRef:      event.PushRef,
+			PushAction:   event.PushAction,
+			CommitCount:  event.CommitCount,
+			CommitTitle:  event.CommitTitle,
+			NoteBody:     event.NoteBody,
+			NoteableType: event.NoteableType,
+		})
+	}
+
+	return result
+}
+
+// projectPath returns the path of the project of a merge request. Without a
+// base URL, the instance is assumed to be served from the root of its host.
+func projectPath(baseURL, webURL string) string {
+	projectURL, _, ok := domain.SplitMergeRequestURL(webURL)
+	if !ok {
+		return ""
+	}
+
+	if baseURL != "" {
+		if path, ok := strings.CutPrefix(projectURL, strings.TrimSuffix(baseURL, "/")+"/"); ok {
+			return path
+		}
+	}
+
+	u, err := url.Parse(projectURL)
+	if err != nil {
+		return ""
+	}
+
+	return strings.Trim(u.Path, "/")
+}
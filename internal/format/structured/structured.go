@@ -0,0 +1,285 @@
+// Package structured renders command results as JSON, YAML or CSV for
+// scripts. The documents follow the schema in schema.go.
+package structured
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"gopkg.in/yaml.v3"
+)
+
+// listSeparator joins multiple values within a CSV cell.
+const listSeparator = ";"
+
+// Formatter writes documents in one of the machine-readable formats.
+type Formatter struct {
+	output format.Output
+}
+
+// NewFormatter creates a Formatter for format.OutputJSON, format.OutputYAML
+// or format.OutputCSV.
+func NewFormatter(output format.Output) *Formatter {
+	return &Formatter{output: output}
+}
+
+// Output returns the format the formatter renders.
+func (f *Formatter) Output() format.Output {
+	return f.output
+}
+
+// FormatTeamWorkload writes a WorkloadsDocument, or one CSV row per team member.
+func (f *Formatter) FormatTeamWorkload(workloads []*domain.UserWorkload) (string, error) {
+	doc := &WorkloadsDocument{Header: newHeader(), Workloads: newWorkloads("", workloads)}
+
+	return f.encode(doc, func() [][]string {
+		return workloadRows(doc.Workloads, nil)
+	})
+}
+
+// FormatMyMergeRequestStatus writes a MergeRequestsDocument, or one CSV row
+// per merge request.
+func (f *Formatter) FormatMyMergeRequestStatus(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	return f.formatMergeRequests(baseURL, mrs)
+}
+
+// FormatProfilesMergeRequestStatus writes a ProfilesDocument, or one CSV row
+// per merge request prefixed with the profile. CSV leaves out the errors.
+func (f *Formatter) FormatProfilesMergeRequestStatus(profiles []format.ProfileMergeRequests) (string, error) {
+	doc := &ProfilesDocument{Header: newHeader(), Profiles: make([]*ProfileMergeRequests, 0, len(profiles))}
+	for _, profile := range profiles {
+		p := &ProfileMergeRequests{
+			Profile:       profile.Profile,
+			BaseURL:       profile.BaseURL,
+			MergeRequests: newMergeRequestStatuses(profile.BaseURL, profile.MergeRequests),
+		}
+		if profile.Err != nil {
+			p.Error = profile.Err.Error()
+		}
+
+		doc.Profiles = append(doc.Profiles, p)
+	}
+
+	return f.encode(doc, func() [][]string {
+		rows := [][]string{append([]string{"profile", "base_url"}, mergeRequestColumns...)}
+		for _, profile := range doc.Profiles {
+			for _, mr := range profile.MergeRequests {
+				rows = append(rows, append([]string{profile.Profile, profile.BaseURL}, mergeRequestRow(mr)...))
+			}
+		}
+
+		return rows
+	})
+}
+
+// FormatMRRoulette writes a RouletteDocument, or one CSV row per candidate
+// with the role it is suggested for.
+func (f *Formatter) FormatMRRoulette(
+	mr *domain.MergeRequest,
+	_ string,
+	workloads []*domain.UserWorkload,
+	suggestedAssignee, suggestedReviewer *domain.User,
+) (string, error) {
+	doc := &RouletteDocument{
+		Header:            newHeader(),
+		MergeRequest:      newMergeRequest("", mr),
+		SuggestedAssignee: newUser(suggestedAssignee),
+		SuggestedReviewer: newUser(suggestedReviewer),
+		Candidates:        newWorkloads("", workloads),
+	}
+
+	return f.encode(doc, func() [][]string {
+		return workloadRows(doc.Candidates, func(user *User) string {
+			switch {
+			case doc.SuggestedAssignee != nil && user.ID == doc.SuggestedAssignee.ID:
+				return "assignee"
+			case doc.SuggestedReviewer != nil && user.ID == doc.SuggestedReviewer.ID:
+				return "reviewer"
+			default:
+				return ""
+			}
+		})
+	})
+}
+
+// FormatMyReviewWorkload writes a MergeRequestsDocument, or one CSV row per
+// merge request.
+func (f *Formatter) FormatMyReviewWorkload(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	return f.formatMergeRequests(baseURL, mrs)
+}
+
+// FormatMyActivity writes an EventsDocument, or one CSV row per event.
+func (f *Formatter) FormatMyActivity(_ string, events []*domain.Event) (string, error) {
+	doc := &EventsDocument{Header: newHeader(), Events: newEvents(events)}
+
+	return f.encode(doc, func() [][]string {
+		rows := [][]string{{
+			"created_at", "project", "action", "target_type", "target_title", "web_url",
+			"push_ref", "commit_count", "commit_title", "note_body",
+		}}
+		for _, event := range doc.Events {
+			rows = append(rows, []string{
+				formatTime(event.CreatedAt),
+				event.Project,
+				event.Action,
+				event.TargetType,
+				event.TargetTitle,
+				event.WebURL,
+				event.PushRef,
+				strconv.Itoa(event.CommitCount),
+				event.CommitTitle,
+				event.NoteBody,
+			})
+		}
+
+		return rows
+	})
+}
+
+// FormatMRStatus writes a MergeRequestDocument, or a single CSV row.
+func (f *Formatter) FormatMRStatus(baseURL string, mr *domain.MergeRequestWithStatus) (string, error) {
+	doc := &MergeRequestDocument{Header: newHeader(), MergeRequest: newMergeRequestStatus(baseURL, mr)}
+
+	return f.encode(doc, func() [][]string {
+		return [][]string{mergeRequestColumns, mergeRequestRow(doc.MergeRequest)}
+	})
+}
+
+// FormatMergeRequestList writes a MergeRequestsDocument, or one CSV row per
+// merge request, in the given order.
+func (f *Formatter) FormatMergeRequestList(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	return f.formatMergeRequests(baseURL, mrs)
+}
+
+func (f *Formatter) formatMergeRequests(baseURL string, mrs []*domain.MergeRequestWithStatus) (string, error) {
+	doc := &MergeRequestsDocument{Header: newHeader(), MergeRequests: newMergeRequestStatuses(baseURL, mrs)}
+
+	return f.encode(doc, func() [][]string {
+		rows := [][]string{mergeRequestColumns}
+		for _, mr := range doc.MergeRequests {
+			rows = append(rows, mergeRequestRow(mr))
+		}
+
+		return rows
+	})
+}
+
+// encode serializes doc, or the rows returned by csvRows for CSV output.
+func (f *Formatter) encode(doc any, csvRows func() [][]string) (string, error) {
+	var buf bytes.Buffer
+
+	switch f.output {
+	case format.OutputJSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			return "", fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	case format.OutputYAML:
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return "", fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	case format.OutputCSV:
+		w := csv.NewWriter(&buf)
+		if err := w.WriteAll(csvRows()); err != nil {
+			return "", fmt.Errorf("failed to write CSV: %w", err)
+		}
+	default:
+		return "", fmt.Errorf("unsupported output format %q", f.output)
+	}
+
+	return buf.String(), nil
+}
+
+//nolint:gochecknoglobals // read-only header row
+var mergeRequestColumns = []string{
+	"project", "iid", "title", "author", "assignee", "reviewers", "approvals", "approval_count",
+	"draft", "stalled", "source_branch", "target_branch", "labels", "created_at", "updated_at", "web_url",
+}
+
+func mergeRequestRow(mr *MergeRequestStatus) []string {
+	return []string{
+		mr.Project,
+		strconv.Itoa(mr.IID),
+		mr.Title,
+		username(mr.Author),
+		username(mr.Assignee),
+		usernames(mr.Reviewers),
+		usernames(mr.Approvals),
+		strconv.Itoa(mr.ApprovalCount),
+		strconv.FormatBool(mr.Draft),
+		strconv.FormatBool(mr.Stalled),
+		mr.SourceBranch,
+		mr.TargetBranch,
+		strings.Join(mr.Labels, listSeparator),
+		formatTime(mr.CreatedAt),
+		formatTime(mr.UpdatedAt),
+		mr.WebURL,
+	}
+}
+
+// workloadRows lists workloads; role, when given, adds a column with the role
+// suggested for the user.
+func workloadRows(workloads []*Workload, role func(*User) string) [][]string {
+	header := []string{"username", "availability", "open_merge_requests", "commits", "merge_requests"}
+	if role != nil {
+		header = append(header, "suggested_as")
+	}
+
+	rows := [][]string{header}
+	for _, workload := range workloads {
+		urls := make([]string, 0, len(workload.MergeRequests))
+		for _, mr := range workload.MergeRequests {
+			urls = append(urls, mr.WebURL)
+		}
+
+		row := []string{
+			username(workload.User),
+			workload.User.Availability,
+			strconv.Itoa(workload.OpenMergeRequests),
+			strconv.Itoa(workload.Commits),
+			strings.Join(urls, listSeparator),
+		}
+		if role != nil {
+			row = append(row, role(workload.User))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func username(user *User) string {
+	if user == nil {
+		return ""
+	}
+
+	return user.Username
+}
+
+func usernames(users []*User) string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+
+	return strings.Join(names, listSeparator)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
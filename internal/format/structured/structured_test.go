@@ -0,0 +1,200 @@
+package structured
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/format"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func testMergeRequests() []*domain.MergeRequestWithStatus {
+	alice := &domain.User{ID: 1, Username: "alice"}
+	bob := &domain.User{ID: 2, Username: "bob", Status: domain.UserStatus{Availability: "busy"}}
+
+	return []*domain.MergeRequestWithStatus{
+		{
+			MergeRequest: &domain.MergeRequest{
+				ID:           100,
+				IID:          7,
+				ProjectID:    3,
+				Title:        "Add feature, part 1",
+				WebURL:       "https://gitlab.example.com/acme/api/-/merge_requests/7",
+				Author:       alice,
+				Reviewers:    []*domain.User{bob},
+				SourceBranch: "feature",
+				TargetBranch: "main",
+				Labels:       []string{"backend", "api"},
+				CreatedAt:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				UpdatedAt:    time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+			},
+			Approvals:     []*domain.User{bob},
+			ApprovalCount: 1,
+			IsStalled:     true,
+		},
+	}
+}
+
+func TestFormatter_JSON(t *testing.T) {
+	f := NewFormatter(format.OutputJSON)
+
+	out, err := f.FormatMyMergeRequestStatus("https://gitlab.example.com", testMergeRequests())
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+
+	assert.InDelta(t, SchemaVersion, doc["version"], 0)
+	assert.Contains(t, doc, "generated_at")
+
+	mrs, ok := doc["merge_requests"].([]any)
+	require.True(t, ok)
+	require.Len(t, mrs, 1)
+
+	mr, ok := mrs[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "acme/api", mr["project"])
+	assert.InDelta(t, 7, mr["iid"], 0)
+	assert.Equal(t, "2024-05-02T10:00:00Z", mr["updated_at"])
+	assert.Equal(t, true, mr["stalled"])
+	assert.Nil(t, mr["assignee"])
+	assert.Equal(t, []any{"backend", "api"}, mr["labels"])
+	assert.Equal(t, map[string]any{"id": float64(2), "username": "bob", "availability": "busy"},
+		mr["approvals"].([]any)[0])
+}
+
+func TestFormatter_FormatMergeRequestList(t *testing.T) {
+	f := NewFormatter(format.OutputJSON)
+	mrs := testMergeRequests()
+	mrs[0].Milestone = "v1.2"
+	mrs[0].ChangedFiles = 4
+
+	out, err := f.FormatMergeRequestList("https://gitlab.example.com", mrs)
+	require.NoError(t, err)
+
+	var doc struct {
+		MergeRequests []struct {
+			Milestone    string `json:"milestone"`
+			ChangedFiles int    `json:"changed_files"`
+		} `json:"merge_requests"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+	require.Len(t, doc.MergeRequests, 1)
+	assert.Equal(t, "v1.2", doc.MergeRequests[0].Milestone)
+	assert.Equal(t, 4, doc.MergeRequests[0].ChangedFiles)
+}
+
+func TestFormatter_YAML(t *testing.T) {
+	f := NewFormatter(format.OutputYAML)
+
+	out, err := f.FormatMRStatus("https://gitlab.example.com", testMergeRequests()[0])
+	require.NoError(t, err)
+
+	var doc struct {
+		Version      int `yaml:"version"`
+		MergeRequest struct {
+			Project       string `yaml:"project"`
+			Title         string `yaml:"title"`
+			ApprovalCount int    `yaml:"approval_count"`
+			Author        struct {
+				Username string `yaml:"username"`
+			} `yaml:"author"`
+		} `yaml:"merge_request"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(out), &doc))
+
+	assert.Equal(t, SchemaVersion, doc.Version)
+	assert.Equal(t, "acme/api", doc.MergeRequest.Project)
+	assert.Equal(t, "Add feature, part 1", doc.MergeRequest.Title)
+	assert.Equal(t, 1, doc.MergeRequest.ApprovalCount)
+	assert.Equal(t, "alice", doc.MergeRequest.Author.Username)
+}
+
+func TestFormatter_CSV(t *testing.T) {
+	f := NewFormatter(format.OutputCSV)
+
+	t.Run("merge requests", func(t *testing.T) {
+		out, err := f.FormatMyReviewWorkload("https://gitlab.example.com", testMergeRequests())
+		require.NoError(t, err)
+
+		rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, mergeRequestColumns, rows[0])
+		assert.Equal(t, []string{
+			"acme/api", "7", "Add feature, part 1", "alice", "", "bob", "bob", "1",
+			"false", "true", "feature", "main", "backend;api",
+			"2024-05-01T10:00:00Z", "2024-05-02T10:00:00Z",
+			"https://gitlab.example.com/acme/api/-/merge_requests/7",
+		}, rows[1])
+	})
+
+	t.Run("team workload", func(t *testing.T) {
+		mr := testMergeRequests()[0].MergeRequest
+		out, err := f.FormatTeamWorkload([]*domain.UserWorkload{
+			{User: &domain.User{ID: 2, Username: "bob"}, MRCount: 1, ActiveMRs: []*domain.MergeRequest{mr}},
+			{User: &domain.User{ID: 3, Username: "carol"}},
+		})
+		require.NoError(t, err)
+
+		rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"username", "availability", "open_merge_requests", "commits", "merge_requests"},
+			{"bob", "", "1", "0", mr.WebURL},
+			{"carol", "", "0", "0", ""},
+		}, rows)
+	})
+
+	t.Run("roulette", func(t *testing.T) {
+		bob := &domain.User{ID: 2, Username: "bob"}
+		carol := &domain.User{ID: 3, Username: "carol"}
+		out, err := f.FormatMRRoulette(testMergeRequests()[0].MergeRequest, "",
+			[]*domain.UserWorkload{{User: bob, Commits: 5}, {User: carol, MRCount: 2}}, bob, carol)
+		require.NoError(t, err)
+
+		rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, "suggested_as", rows[0][5])
+		assert.Equal(t, "assignee", rows[1][5])
+		assert.Equal(t, "reviewer", rows[2][5])
+	})
+}
+
+func TestFormatter_Profiles(t *testing.T) {
+	f := NewFormatter(format.OutputJSON)
+
+	out, err := f.FormatProfilesMergeRequestStatus([]format.ProfileMergeRequests{
+		{Profile: "work", BaseURL: "https://gitlab.example.com", MergeRequests: testMergeRequests()},
+		{Profile: "oss", BaseURL: "https://gitlab.com"},
+		{Profile: "", BaseURL: "https://gitlab.example.com", Err: errors.New("401 Unauthorized")},
+	})
+	require.NoError(t, err)
+
+	var doc ProfilesDocument
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+	require.Len(t, doc.Profiles, 3)
+	assert.Equal(t, "work", doc.Profiles[0].Profile)
+	assert.Equal(t, "acme/api", doc.Profiles[0].MergeRequests[0].Project)
+	assert.NotNil(t, doc.Profiles[1].MergeRequests, "empty lists are written as []")
+	assert.Empty(t, doc.Profiles[1].Error)
+	assert.Empty(t, doc.Profiles[2].Profile)
+	assert.Equal(t, "401 Unauthorized", doc.Profiles[2].Error)
+}
+
+func TestProjectPath(t *testing.T) {
+	webURL := "https://example.com/gitlab/acme/api/-/merge_requests/7"
+
+	assert.Equal(t, "acme/api", projectPath("https://example.com/gitlab", webURL))
+	assert.Equal(t, "acme/api", projectPath("https://example.com/gitlab/", webURL))
+	assert.Equal(t, "gitlab/acme/api", projectPath("", webURL))
+	assert.Empty(t, projectPath("", "not a merge request"))
+}
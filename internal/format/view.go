@@ -0,0 +1,477 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/denchenko/gg/internal/core/domain"
+	"github.com/denchenko/gg/internal/issue"
+)
+
+const (
+	// UnknownProject names the project of merge requests and events whose
+	// project cannot be determined.
+	UnknownProject = "Unknown Project"
+	// TopLevelProfile names the top-level configuration among profiles.
+	TopLevelProfile = "default"
+
+	noneString        = "None"
+	descriptionMaxLen = 100
+	descriptionTrunc  = 97
+	excerptMaxLen     = 80
+	timeLayout        = "2006-01-02 15:04:05"
+)
+
+// Statuses of merge requests, as returned by Status.
+const (
+	StatusPipelineFailed = "pipeline-failed"
+	StatusNeedsRebase    = "needs-rebase"
+	StatusStalled        = "stalled"
+	StatusReadyToMerge   = "ready-to-merge"
+)
+
+// The view data below is what the templates of every renderer receive.
+
+// TeamWorkloadData holds data for team workload templates.
+type TeamWorkloadData struct {
+	Workloads []*domain.UserWorkload
+	Timestamp time.Time
+}
+
+// MyMergeRequestStatusData holds data for my merge request status templates.
+type MyMergeRequestStatusData struct {
+	MergeRequests     []*domain.MergeRequestWithStatus
+	CurrentProject    string
+	CurrentProjectMRs []*domain.MergeRequestWithStatus
+	OtherMRsByProject map[string][]*domain.MergeRequestWithStatus
+	ReadyToMerge      int
+	Stalled           int
+	Timestamp         time.Time
+}
+
+// ProfileMergeRequestStatusData holds my merge request status data of a profile.
+type ProfileMergeRequestStatusData struct {
+	Profile string
+	BaseURL string
+	Data    MyMergeRequestStatusData
+	// Error tells why the merge requests could not be fetched.
+	Error string
+}
+
+// MyReviewWorkloadData holds data for my review workload templates.
+type MyReviewWorkloadData struct {
+	MRsByProject map[string][]*domain.MergeRequestWithStatus
+	Timestamp    time.Time
+}
+
+// MergeRequestListData holds data for merge request list templates. Merge
+// requests keep the order they were sorted in.
+type MergeRequestListData struct {
+	MergeRequests []*domain.MergeRequestWithStatus
+	Timestamp     time.Time
+}
+
+// MRRouletteData holds data for MR roulette templates.
+type MRRouletteData struct {
+	MergeRequest      *domain.MergeRequest
+	MRURL             string
+	Workloads         []*domain.UserWorkload
+	SuggestedAssignee *domain.User
+	SuggestedReviewer *domain.User
+	Timestamp         time.Time
+}
+
+// MyActivityData holds data for my activity templates.
+type MyActivityData struct {
+	EventsByProject map[string][]*domain.Event
+	Timestamp       time.Time
+}
+
+// NewTeamWorkloadData prepares team workload data.
+func NewTeamWorkloadData(workloads []*domain.UserWorkload) TeamWorkloadData {
+	return TeamWorkloadData{
+		Workloads: workloads,
+		Timestamp: time.Now(),
+	}
+}
+
+// NewMyMergeRequestStatusData prepares my merge request status data. Merge
+// requests outside the current project are grouped by project.
+func NewMyMergeRequestStatusData(baseURL string, mrs []*domain.MergeRequestWithStatus) MyMergeRequestStatusData {
+	data := MyMergeRequestStatusData{
+		MergeRequests:     mrs,
+		OtherMRsByProject: make(map[string][]*domain.MergeRequestWithStatus),
+		Timestamp:         time.Now(),
+	}
+	for _, mr := range mrs {
+		if mr.IsStalled {
+			data.Stalled++
+		}
+		if IsReadyToMerge(mr) {
+			data.ReadyToMerge++
+		}
+
+		projectName := ProjectName(baseURL, mr.WebURL)
+		if mr.IsCurrentProject {
+			data.CurrentProject = projectName
+			data.CurrentProjectMRs = append(data.CurrentProjectMRs, mr)
+
+			continue
+		}
+		data.OtherMRsByProject[projectName] = append(data.OtherMRsByProject[projectName], mr)
+	}
+
+	return data
+}
+
+// NewProfilesMergeRequestStatusData prepares my merge request status data of
+// every profile.
+func NewProfilesMergeRequestStatusData(profiles []ProfileMergeRequests) []ProfileMergeRequestStatusData {
+	data := make([]ProfileMergeRequestStatusData, 0, len(profiles))
+	for _, profile := range profiles {
+		d := ProfileMergeRequestStatusData{
+			Profile: profile.Profile,
+			BaseURL: profile.BaseURL,
+			Data:    NewMyMergeRequestStatusData(profile.BaseURL, profile.MergeRequests),
+		}
+		if d.Profile == "" {
+			d.Profile = TopLevelProfile
+		}
+		if profile.Err != nil {
+			d.Error = profile.Err.Error()
+		}
+
+		data = append(data, d)
+	}
+
+	return data
+}
+
+// NewMyReviewWorkloadData prepares my review workload data, grouped by project.
+func NewMyReviewWorkloadData(baseURL string, mrs []*domain.MergeRequestWithStatus) MyReviewWorkloadData {
+	mrsByProject := make(map[string][]*domain.MergeRequestWithStatus)
+	for _, mr := range mrs {
+		projectName := ProjectName(baseURL, mr.WebURL)
+		mrsByProject[projectName] = append(mrsByProject[projectName], mr)
+	}
+
+	return MyReviewWorkloadData{
+		MRsByProject: mrsByProject,
+		Timestamp:    time.Now(),
+	}
+}
+
+// NewMergeRequestListData prepares merge request list data.
+func NewMergeRequestListData(mrs []*domain.MergeRequestWithStatus) MergeRequestListData {
+	return MergeRequestListData{
+		MergeRequests: mrs,
+		Timestamp:     time.Now(),
+	}
+}
+
+// NewMRRouletteData prepares MR roulette data.
+func NewMRRouletteData(
+	mr *domain.MergeRequest,
+	mrURL string,
+	workloads []*domain.UserWorkload,
+	suggestedAssignee, suggestedReviewer *domain.User,
+) MRRouletteData {
+	return MRRouletteData{
+		MergeRequest:      mr,
+		MRURL:             mrURL,
+		Workloads:         workloads,
+		SuggestedAssignee: suggestedAssignee,
+		SuggestedReviewer: suggestedReviewer,
+		Timestamp:         time.Now(),
+	}
+}
+
+// NewMyActivityData prepares my activity data, grouped by project.
+func NewMyActivityData(events []*domain.Event) MyActivityData {
+	eventsByProject := make(map[string][]*domain.Event)
+	for _, event := range events {
+		projectName := event.ProjectPath
+		if projectName == "" {
+			projectName = UnknownProject
+		}
+		eventsByProject[projectName] = append(eventsByProject[projectName], event)
+	}
+
+	return MyActivityData{
+		EventsByProject: eventsByProject,
+		Timestamp:       time.Now(),
+	}
+}
+
+// Funcs returns the template functions shared by every renderer. baseURL is
+// the instance URL used to derive project names.
+func Funcs(issuer *issue.Issuer, baseURL string) map[string]any {
+	return map[string]any{
+		"formatTime":                FormatTime,
+		"joinUsernames":             JoinUsernames,
+		"truncateDescription":       TruncateDescription,
+		"formatActivityDescription": ActivityDescription,
+		"getStatus":                 Status,
+		"formatPipeline":            Pipeline,
+		"formatThread":              Thread,
+		"formatMergeability":        Mergeability,
+		"formatApprovalsLeft":       ApprovalsLeft,
+		"formatApprovalRule":        ApprovalRule,
+		"isReadyToMerge":            IsReadyToMerge,
+		"getRole":                   Role,
+		"getProjectName":            func(webURL string) string { return ProjectName(baseURL, webURL) },
+		"getWorkloadMRCount": func(workloads []*domain.UserWorkload, userID int) int {
+			if w := FindWorkload(workloads, userID); w != nil {
+				return w.MRCount
+			}
+
+			return 0
+		},
+		"getWorkloadCommits": func(workloads []*domain.UserWorkload, userID int) int {
+			if w := FindWorkload(workloads, userID); w != nil {
+				return w.Commits
+			}
+
+			return 0
+		},
+		"sameUser": func(a, b *domain.User) bool {
+			return a != nil && b != nil && a.ID == b.ID
+		},
+		"getIssueURL": func(title string) string {
+			if issuer == nil {
+				return ""
+			}
+			url, _ := issuer.MakeURL(issuer.ExtractNumber(title))
+
+			return url
+		},
+		"add": func(a, b int) int {
+			return a + b
+		},
+		"sub": func(a, b int) int {
+			return a - b
+		},
+		"gte": func(a, b int) bool {
+			return a >= b
+		},
+		"repeat": strings.Repeat,
+	}
+}
+
+// FormatTime formats a time for reports.
+func FormatTime(t time.Time) string {
+	return t.Format(timeLayout)
+}
+
+// JoinUsernames lists the usernames of users, or "None".
+func JoinUsernames(users []*domain.User) string {
+	if len(users) == 0 {
+		return noneString
+	}
+	usernames := make([]string, len(users))
+	for i, user := range users {
+		usernames[i] = user.Username
+	}
+
+	return strings.Join(usernames, ", ")
+}
+
+// TruncateDescription puts a description on a single line and shortens it.
+func TruncateDescription(desc string) string {
+	// Replace multiple consecutive newlines with a single semicolon
+	for strings.Contains(desc, "\n\n") {
+		desc = strings.ReplaceAll(desc, "\n\n", "; ")
+	}
+	// Replace remaining single newlines with semicolons
+	desc = strings.ReplaceAll(desc, "\n", "; ")
+	if len(desc) > descriptionMaxLen {
+		return desc[:descriptionTrunc] + "..."
+	}
+
+	return desc
+}
+
+// ProjectName returns the path of the project of a merge request URL relative
+// to baseURL.
+func ProjectName(baseURL, webURL string) string {
+	projectPart, _, ok := domain.SplitMergeRequestURL(webURL)
+	if !ok {
+		return UnknownProject
+	}
+
+	// Remove the base URL prefix to get the project path
+	if strings.HasPrefix(projectPart, baseURL+"/") {
+		projectPart = strings.TrimPrefix(projectPart, baseURL+"/")
+	}
+
+	return projectPart
+}
+
+// Reference returns the short reference of a merge request, "project!iid".
+func Reference(baseURL string, mr *domain.MergeRequest) string {
+	return ProjectName(baseURL, mr.WebURL) + "!" + strconv.Itoa(mr.IID)
+}
+
+// IsReadyToMerge reports whether a merge request has all the approvals it
+// needs and no unresolved threads.
+func IsReadyToMerge(mr *domain.MergeRequestWithStatus) bool {
+	return mr.IsReadyToMerge()
+}
+
+// Status returns StatusPipelineFailed, StatusNeedsRebase, StatusStalled,
+// StatusReadyToMerge or an empty string. A failed pipeline and a needed rebase
+// come first as they wait for the author rather than for the reviewers.
+func Status(mr *domain.MergeRequestWithStatus) string {
+	if mr.HasFailedPipeline() {
+		return StatusPipelineFailed
+	}
+	if mr.NeedsRebase() {
+		return StatusNeedsRebase
+	}
+	if mr.IsStalled {
+		return StatusStalled
+	}
+	if IsReadyToMerge(mr) {
+		return StatusReadyToMerge
+	}
+
+	return ""
+}
+
+// Pipeline describes the head pipeline of a merge request, listing the failed
+// jobs of a failed one as in "failed (lint, test)". It is empty when there is
+// no pipeline.
+func Pipeline(mr *domain.MergeRequest) string {
+	if len(mr.FailedJobs) == 0 {
+		return mr.PipelineStatus
+	}
+
+	return mr.PipelineStatus + " (" + strings.Join(mr.FailedJobs, ", ") + ")"
+}
+
+// Mergeability describes whether a merge request can be merged, as in
+// "need rebase, has conflicts, 3 commits behind". It is empty when the backend
+// reports nothing.
+func Mergeability(mr *domain.MergeRequest) string {
+	var parts []string
+	if mr.DetailedMergeStatus != "" {
+		parts = append(parts, strings.ReplaceAll(mr.DetailedMergeStatus, "_", " "))
+	}
+	if mr.HasConflicts && mr.DetailedMergeStatus != domain.MergeStatusConflict {
+		parts = append(parts, "has conflicts")
+	}
+	if mr.DivergedCommits > 0 {
+		parts = append(parts, fmt.Sprintf("%d commits behind", mr.DivergedCommits))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// ApprovalsLeft describes how many approvals a merge request still misses, as
+// in "1 of 2 left". It is empty when the merge request needs none.
+func ApprovalsLeft(mr *domain.MergeRequestWithStatus) string {
+	if mr.ApprovalsRequired == 0 && mr.ApprovalsLeft == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d of %d left", mr.ApprovalsLeft, mr.ApprovalsRequired)
+}
+
+// ApprovalRule summarizes an approval rule, as in "Backend (code owner): 1 of 2,
+// approved by alice, eligible: bob, carol". Eligible approvers are only listed
+// while the rule is not approved, leaving out those who already approved.
+func ApprovalRule(rule *domain.ApprovalRule) string {
+	name := rule.Name
+	if rule.Type != "" && rule.Type != "regular" {
+		name += " (" + strings.ReplaceAll(rule.Type, "_", " ") + ")"
+	}
+
+	status := fmt.Sprintf("%d of %d", len(rule.ApprovedBy), rule.Required)
+	if rule.Approved {
+		status = "approved"
+	}
+
+	summary := name + ": " + status
+	if len(rule.ApprovedBy) > 0 {
+		summary += ", approved by " + JoinUsernames(rule.ApprovedBy)
+	}
+
+	if rule.Approved {
+		return summary
+	}
+
+	approved := make(map[int]bool, len(rule.ApprovedBy))
+	for _, user := range rule.ApprovedBy {
+		approved[user.ID] = true
+	}
+	var eligible []*domain.User
+	for _, user := range rule.EligibleApprovers {
+		if !approved[user.ID] {
+			eligible = append(eligible, user)
+		}
+	}
+	if len(eligible) > 0 {
+		summary += ", eligible: " + JoinUsernames(eligible)
+	}
+
+	return summary
+}
+
+// Thread summarizes an unresolved discussion of a merge request by author
+// with an excerpt of its last comment and whom it waits for, as in
+// "alice: Please rename this (waiting on bob)".
+func Thread(d *domain.Discussion, author *domain.User) string {
+	last := d.LastNote()
+	if last == nil {
+		return ""
+	}
+
+	summary := Excerpt(last.Body)
+	if last.Author != nil {
+		summary = last.Author.Username + ": " + summary
+	}
+	if waitingOn := d.WaitingOn(author); waitingOn != nil {
+		summary += " (waiting on " + waitingOn.Username + ")"
+	}
+
+	return summary
+}
+
+// Excerpt returns the first non-empty line of a comment, shortened.
+func Excerpt(body string) string {
+	var line string
+	for _, l := range strings.Split(body, "\n") {
+		if line = strings.TrimSpace(l); line != "" {
+			break
+		}
+	}
+
+	runes := []rune(line)
+	if len(runes) > excerptMaxLen {
+		return string(runes[:excerptMaxLen-1]) + "…"
+	}
+
+	return line
+}
+
+// Role returns the role of user in a merge request: "Assignee" or "Reviewer".
+func Role(mr *domain.MergeRequest, user *domain.User) string {
+	if mr.Assignee != nil && mr.Assignee.ID == user.ID {
+		return "Assignee"
+	}
+
+	return "Reviewer"
+}
+
+// FindWorkload returns the workload of the user with the given ID, or nil.
+func FindWorkload(workloads []*domain.UserWorkload, userID int) *domain.UserWorkload {
+	for _, w := range workloads {
+		if w.User.ID == userID {
+			return w
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+const urlPartsCount = 2
+
+// Remote describes a git remote URL.
+type Remote struct {
+	Host        string
+	ProjectPath string
+}
+
+// RemoteURL returns the URL of the origin remote of the repository in the
+// current working directory.
+func RemoteURL(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get remote URL: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CurrentBranch returns the branch checked out in the current working directory.
+func CurrentBranch(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", "branch", "--show-current").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+
+	branch := strings.TrimSpace(string(output))
+	if branch == "" {
+		return "", errors.New("failed to get current branch")
+	}
+
+	return branch, nil
+}
+
+// TopLevel returns the top-level directory of the repository in the current
+// working directory.
+func TopLevel(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository top-level directory: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CurrentRemote returns the parsed origin remote of the current repository.
+func CurrentRemote(ctx context.Context) (*Remote, error) {
+	remoteURL, err := RemoteURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRemote(remoteURL)
+}
+
+// ParseRemote parses SSH (git@host:group/project.git) and HTTPS remote URLs.
+func ParseRemote(remoteURL string) (*Remote, error) {
+	trimmed := strings.TrimSuffix(remoteURL, ".git")
+
+	if strings.HasPrefix(trimmed, "git@") {
+		parts := strings.Split(strings.TrimPrefix(trimmed, "git@"), ":")
+		if len(parts) != urlPartsCount {
+			return nil, errors.New("invalid SSH remote URL format")
+		}
+
+		return &Remote{Host: parts[0], ProjectPath: parts[1]}, nil
+	}
+
+	parts := strings.Split(trimmed, "/")
+	if len(parts) < urlPartsCount {
+		return nil, errors.New("invalid HTTPS remote URL format")
+	}
+
+	remote := &Remote{ProjectPath: strings.Join(parts[len(parts)-2:], "/")}
+	if u, err := url.Parse(trimmed); err == nil {
+		remote.Host = u.Hostname()
+	}
+
+	return remote, nil
+}
+
+// Checkout fetches ref from the origin remote and checks it out as branch in
+// the current working directory. An existing branch is fast-forwarded to ref,
+// and ref defaults to the branch of the same name on the remote.
+func Checkout(ctx context.Context, ref, branch string) error {
+	if err := fetch(ctx, ref, branch); err != nil {
+		return err
+	}
+
+	if !branchExists(ctx, branch) {
+		if err := run(ctx, "checkout", "-b", branch, "FETCH_HEAD"); err != nil {
+			return fmt.Errorf("failed to check out branch %s: %w", branch, err)
+		}
+
+		return nil
+	}
+
+	if err := run(ctx, "checkout", branch); err != nil {
+		return fmt.Errorf("failed to check out branch %s: %w", branch, err)
+	}
+
+	if err := run(ctx, "merge", "--ff-only", "FETCH_HEAD"); err != nil {
+		return fmt.Errorf("failed to fast-forward branch %s: %w", branch, err)
+	}
+
+	return nil
+}
+
+// AddWorktree fetches ref from the origin remote and checks it out as branch
+// in a new worktree at dir. Like Checkout, an existing branch is
+// fast-forwarded to ref, and ref defaults to the branch of the same name on
+// the remote.
+func AddWorktree(ctx context.Context, ref, branch, dir string) error {
+	if err := fetch(ctx, ref, branch); err != nil {
+		return err
+	}
+
+	// FETCH_HEAD belongs to the current worktree, so the new one is given
+	// the commit it points to.
+	commit, err := output(ctx, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+
+	if !branchExists(ctx, branch) {
+		if err := run(ctx, "worktree", "add", "-b", branch, dir, commit); err != nil {
+			return fmt.Errorf("failed to add worktree %s: %w", dir, err)
+		}
+
+		return nil
+	}
+
+	if err := run(ctx, "worktree", "add", dir, branch); err != nil {
+		return fmt.Errorf("failed to add worktree %s: %w", dir, err)
+	}
+
+	if err := run(ctx, "-C", dir, "merge", "--ff-only", commit); err != nil {
+		return fmt.Errorf("failed to fast-forward branch %s: %w", branch, err)
+	}
+
+	return nil
+}
+
+// fetch fetches ref, or branch when ref is empty, from the origin remote into
+// FETCH_HEAD.
+func fetch(ctx context.Context, ref, branch string) error {
+	if ref == "" {
+		ref = branch
+	}
+
+	if err := run(ctx, "fetch", "origin", ref); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+func branchExists(ctx context.Context, branch string) bool {
+	return run(ctx, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch) == nil
+}
+
+// Upstream returns the upstream branch of the local branch, as in
+// "origin/main", or an empty string when the branch does not exist or has
+// no upstream branch.
+func Upstream(ctx context.Context, branch string) string {
+	upstream, err := output(ctx, "rev-parse", "--abbrev-ref", "--symbolic-full-name", branch+"@{upstream}")
+	if err != nil {
+		return ""
+	}
+
+	return upstream
+}
+
+// NeedsPush reports whether the current branch has commits its upstream
+// branch lacks, or has no upstream branch at all.
+func NeedsPush(ctx context.Context) (bool, error) {
+	if _, err := output(ctx, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err != nil {
+		return true, nil //nolint:nilerr // a missing upstream means the branch was never pushed
+	}
+
+	ahead, err := output(ctx, "rev-list", "--count", "@{upstream}..HEAD")
+	if err != nil {
+		return false, fmt.Errorf("failed to compare with upstream branch: %w", err)
+	}
+
+	return ahead != "0", nil
+}
+
+// Push pushes branch to the origin remote and makes it the upstream branch.
+func Push(ctx context.Context, branch string) error {
+	if err := run(ctx, "push", "--set-upstream", "origin", branch); err != nil {
+		return fmt.Errorf("failed to push branch %s: %w", branch, err)
+	}
+
+	return nil
+}
+
+// CommitSubjects returns the subjects of the commits of the current branch
+// that are not on base of the origin remote, oldest first.
+func CommitSubjects(ctx context.Context, base string) ([]string, error) {
+	log, err := output(ctx, "log", "--reverse", "--format=%s", "origin/"+base+"..HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	if log == "" {
+		return nil, nil
+	}
+
+	return strings.Split(log, "\n"), nil
+}
+
+// output runs a git command and returns what it printed, trimmed.
+func output(ctx context.Context, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// run runs a git command, reporting what it printed when it fails.
+func run(ctx context.Context, args ...string) error {
+	output, err := exec.CommandContext(ctx, "git", args...).CombinedOutput()
+	if err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%w: %s", err, message)
+		}
+
+		return err
+	}
+
+	return nil
+}
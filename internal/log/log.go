@@ -2,6 +2,7 @@ package log
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -9,18 +10,43 @@ import (
 
 const spinnerDelay = 100 * time.Millisecond
 
+var (
+	spinnerEnabled = true
+	spinnerColored = true
+)
+
+// Configure sets whether WithSpinner shows a spinner, which is only useful
+// on a terminal, and whether it is colored.
+func Configure(interactive, colored bool) {
+	spinnerEnabled = interactive
+	spinnerColored = colored
+}
+
+// Warnf prints a warning to stderr, keeping it out of the command output.
+func Warnf(format string, args ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+}
+
 // WithSpinner executes the given function while showing a spinner with the specified message.
 func WithSpinner(message string, fn func() error) error {
+	if !spinnerEnabled {
+		return fn()
+	}
+
 	s := spinner.New(spinner.CharSets[14], spinnerDelay)
 	s.Suffix = " " + message
+	s.FinalMSG = message + " [done]"
+
+	if spinnerColored {
+		err := s.Color("green")
+		if err != nil {
+			return fmt.Errorf("coloring green: %w", err)
+		}
 
-	err := s.Color("green")
-	if err != nil {
-		return fmt.Errorf("coloring green: %w", err)
+		s.FinalMSG = message + " \033[32m[done]\033[0m"
 	}
 
 	s.Start()
-	s.FinalMSG = message + " \033[32m[done]\033[0m"
 	defer s.Stop()
 
 	return fn()
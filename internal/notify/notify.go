@@ -0,0 +1,37 @@
+// Package notify shows desktop notifications.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+const appName = "gg"
+
+// ErrUnsupported is returned when no notification tool is installed.
+var ErrUnsupported = errors.New("desktop notifications need notify-send")
+
+// Available reports whether desktop notifications can be sent.
+func Available() bool {
+	_, err := exec.LookPath("notify-send")
+
+	return err == nil
+}
+
+// Send shows a desktop notification with notify-send, which delivers it to
+// the notification daemon of the session over D-Bus.
+func Send(ctx context.Context, title, body string) error {
+	if !Available() {
+		return ErrUnsupported
+	}
+
+	//nolint:gosec // the arguments are passed to notify-send, not to a shell
+	output, err := exec.CommandContext(ctx, "notify-send", "--app-name="+appName, title, body).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w: %s", err, output)
+	}
+
+	return nil
+}
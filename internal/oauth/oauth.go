@@ -0,0 +1,117 @@
+// Package oauth implements the OAuth 2.0 device authorization grant against a
+// GitLab instance and keeps the resulting tokens fresh.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/denchenko/gg/internal/log"
+	"golang.org/x/oauth2"
+)
+
+// Scope is the scope requested for gg, which reads and updates merge requests.
+const Scope = "api"
+
+// Config returns the OAuth client configuration for a GitLab instance and the
+// ID of an OAuth application registered there.
+func Config(baseURL, clientID string) *oauth2.Config {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	return &oauth2.Config{
+		ClientID: clientID,
+		Endpoint: oauth2.Endpoint{
+			DeviceAuthURL: baseURL + "/oauth/authorize_device",
+			TokenURL:      baseURL + "/oauth/token",
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+		Scopes: []string{Scope},
+	}
+}
+
+// Login performs the device authorization grant. prompt is called with the
+// code the user has to enter in the browser; Login then waits until the user
+// approves or denies the request, or the code expires.
+func Login(ctx context.Context, cfg *oauth2.Config, prompt func(*oauth2.DeviceAuthResponse)) (*oauth2.Token, error) {
+	auth, err := cfg.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+
+	prompt(auth)
+
+	token, err := cfg.DeviceAccessToken(ctx, auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain access token: %w", err)
+	}
+
+	return token, nil
+}
+
+// TokenSource returns a token source that refreshes token when it expires and
+// passes every refreshed token to save, so that it survives the process.
+func TokenSource(ctx context.Context, cfg *oauth2.Config, token *oauth2.Token, save func(*oauth2.Token) error) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(token, &savingSource{
+		source:  cfg.TokenSource(ctx, token),
+		current: token.AccessToken,
+		save:    save,
+	})
+}
+
+// savingSource calls save whenever the wrapped source hands out a new token.
+// It is used behind oauth2.ReuseTokenSource, which serializes calls to it.
+type savingSource struct {
+	source  oauth2.TokenSource
+	current string
+	save    func(*oauth2.Token) error
+}
+
+func (s *savingSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh access token: %w", err)
+	}
+
+	if token.AccessToken != s.current {
+		s.current = token.AccessToken
+		if err := s.save(token); err != nil {
+			// The token is valid for this run, so do not fail the request.
+			log.Warnf("failed to save refreshed token: %v", err)
+		}
+	}
+
+	return token, nil
+}
+
+// storedToken is how OAuth tokens are kept in a credential store, next to
+// plain personal access tokens.
+type storedToken struct {
+	OAuth *oauth2.Token `json:"oauth"`
+}
+
+// Encode serializes a token for a credential store.
+func Encode(token *oauth2.Token) (string, error) {
+	data, err := json.Marshal(storedToken{OAuth: token})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// Decode parses a credential written by Encode. It reports false for
+// anything else, such as a personal access token.
+func Decode(raw string) (*oauth2.Token, bool) {
+	if !strings.HasPrefix(raw, "{") {
+		return nil, false
+	}
+
+	var stored storedToken
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil || stored.OAuth == nil || stored.OAuth.AccessToken == "" {
+		return nil, false
+	}
+
+	return stored.OAuth, true
+}
@@ -0,0 +1,121 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// newAuthorizationServer fakes the GitLab OAuth endpoints. The first token
+// poll is answered with authorization_pending.
+func newAuthorizationServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	polls := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/authorize_device", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "client", r.FormValue("client_id"))
+		assert.Equal(t, Scope, r.FormValue("scope"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"device_code": "device", "user_code": "ABCD-1234",
+			"verification_uri": "https://gitlab.example.com/oauth/device", "expires_in": 300, "interval": 1}`))
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.FormValue("grant_type") {
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			assert.Equal(t, "device", r.FormValue("device_code"))
+			if polls++; polls == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "authorization_pending"}`))
+
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "access-1", "refresh_token": "refresh-1", "token_type": "Bearer", "expires_in": 7200,
+			})
+		case "refresh_token":
+			assert.Equal(t, "refresh-1", r.FormValue("refresh_token"))
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "access-2", "refresh_token": "refresh-2", "token_type": "Bearer", "expires_in": 7200,
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "unsupported_grant_type"}`))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestLogin(t *testing.T) {
+	server := newAuthorizationServer(t)
+
+	var userCode string
+	token, err := Login(context.Background(), Config(server.URL, "client"), func(auth *oauth2.DeviceAuthResponse) {
+		userCode = auth.UserCode
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ABCD-1234", userCode)
+	assert.Equal(t, "access-1", token.AccessToken)
+	assert.Equal(t, "refresh-1", token.RefreshToken)
+}
+
+func TestTokenSource_RefreshesAndSaves(t *testing.T) {
+	server := newAuthorizationServer(t)
+
+	expired := &oauth2.Token{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(-time.Minute),
+	}
+
+	var saved []*oauth2.Token
+	source := TokenSource(context.Background(), Config(server.URL, "client"), expired, func(token *oauth2.Token) error {
+		saved = append(saved, token)
+
+		return nil
+	})
+
+	for range 2 {
+		token, err := source.Token()
+		require.NoError(t, err)
+		assert.Equal(t, "access-2", token.AccessToken)
+	}
+
+	require.Len(t, saved, 1)
+	assert.Equal(t, "refresh-2", saved[0].RefreshToken)
+}
+
+func TestEncodeDecode(t *testing.T) {
+	token := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	raw, err := Encode(token)
+	require.NoError(t, err)
+
+	decoded, ok := Decode(raw)
+	require.True(t, ok)
+	assert.Equal(t, token.AccessToken, decoded.AccessToken)
+	assert.Equal(t, token.RefreshToken, decoded.RefreshToken)
+	assert.True(t, token.Expiry.Equal(decoded.Expiry))
+
+	_, ok = Decode("glpat-123")
+	assert.False(t, ok)
+
+	_, ok = Decode(`{"other": true}`)
+	assert.False(t, ok)
+}
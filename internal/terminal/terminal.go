@@ -0,0 +1,92 @@
+// Package terminal detects what the standard output is connected to, to
+// decide whether to draw colors, spinners and how wide reports may be.
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ColorMode selects when output is colored.
+type ColorMode string
+
+// Supported color modes.
+const (
+	ColorAuto   ColorMode = "auto"
+	ColorAlways ColorMode = "always"
+	ColorNever  ColorMode = "never"
+)
+
+// ColorModes returns every supported color mode.
+func ColorModes() []ColorMode {
+	return []ColorMode{ColorAuto, ColorAlways, ColorNever}
+}
+
+// String implements pflag.Value.
+func (m *ColorMode) String() string {
+	return string(*m)
+}
+
+// Set implements pflag.Value.
+func (m *ColorMode) Set(s string) error {
+	for _, mode := range ColorModes() {
+		if strings.EqualFold(s, string(mode)) {
+			*m = mode
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported color mode %q", s)
+}
+
+// Type implements pflag.Value.
+func (m *ColorMode) Type() string {
+	return "when"
+}
+
+// IsTerminal reports whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd())) //nolint:gosec // file descriptors fit into int
+}
+
+// Width returns the width of the terminal f is connected to, or 0 when f is
+// not a terminal. COLUMNS, when set, takes precedence over the detected width.
+func Width(f *os.File) int {
+	if !IsTerminal(f) {
+		return 0
+	}
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	width, _, err := term.GetSize(int(f.Fd())) //nolint:gosec // file descriptors fit into int
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// UseColor reports whether output written to f is colored in the given mode.
+// ColorAuto colors terminals, unless NO_COLOR is set (https://no-color.org)
+// or TERM is "dumb".
+func UseColor(mode ColorMode, f *os.File) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false
+		}
+
+		return IsTerminal(f)
+	}
+}
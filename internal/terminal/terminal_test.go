@@ -0,0 +1,28 @@
+package terminal
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorMode_Set(t *testing.T) {
+	var mode ColorMode
+	require.NoError(t, mode.Set("Never"))
+	assert.Equal(t, ColorNever, mode)
+	assert.Error(t, mode.Set("sometimes"))
+}
+
+func TestUseColor(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	require.NoError(t, err)
+	defer f.Close()
+
+	t.Setenv("NO_COLOR", "")
+	assert.True(t, UseColor(ColorAlways, f))
+	assert.False(t, UseColor(ColorNever, f))
+	assert.False(t, UseColor(ColorAuto, f), "files are not terminals")
+	assert.Zero(t, Width(f))
+}